@@ -0,0 +1,139 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// exportMain exports a fuzz test corpus as a single artifact. The only
+// kind of export so far is an archive, with its format given by the
+// extension of the output file name.
+func exportMain(w, stdErr io.Writer, args []string) error {
+	if len(args) == 0 || args[0] != "archive" {
+		return errExportArgs
+	}
+	args = args[1:]
+	if len(args) == 0 {
+		return errConvertArgs
+	}
+	to, err := archiveFormat(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+	return convertMain(w, stdErr, append([]string{"-to", to}, args...))
+}
+
+// archiveFormat returns the archive format that the extension of name
+// stands for.
+func archiveFormat(name string) (string, error) {
+	switch n := strings.ToLower(name); {
+	case strings.HasSuffix(n, ".zip"):
+		return formatZip, nil
+	case strings.HasSuffix(n, ".tar.gz"), strings.HasSuffix(n, ".tgz"):
+		return formatTarGz, nil
+	}
+	return "", fmt.Errorf("%w: %q", errArchiveExt, name)
+}
+
+// writeZip writes the files to the dst zip archive, or to w, if dst is
+// "-", in version 1 encoding, each named as the Go toolchain would name
+// it.
+func writeZip(w io.Writer, dst string, files []corpus.File) error {
+	return writeArchive(w, dst, files, func(w io.Writer) archiveWriter {
+		return zipWriter{zip.NewWriter(w)}
+	})
+}
+
+// writeTarGz writes the files to the dst gzipped tar archive, or to w,
+// if dst is "-", as [writeZip] does.
+func writeTarGz(w io.Writer, dst string, files []corpus.File) error {
+	return writeArchive(w, dst, files, func(w io.Writer) archiveWriter {
+		gz := gzip.NewWriter(w)
+		return tarWriter{tar.NewWriter(gz), gz}
+	})
+}
+
+// An archiveWriter adds files to an archive.
+type archiveWriter interface {
+	add(name string, data []byte) error
+	// Close writes out the rest of the archive.
+	Close() error
+}
+
+// writeArchive writes the files to an archive, made with newArchive, in
+// version 1 encoding, each named as the Go toolchain would name it and
+// sorted by those names, with the duplicates left out, so that the same
+// corpus always makes the same archive.
+func writeArchive(
+	w io.Writer, dst string, files []corpus.File,
+	newArchive func(w io.Writer) archiveWriter,
+) error {
+	named := map[string][]byte{}
+	for _, f := range files {
+		name, data, err := encodeV1(f.Entry)
+		if err != nil {
+			return fmt.Errorf("converting %q: %w", f.Name, err)
+		}
+		named[name] = data
+	}
+	return writeDumpFile(w, dst, func(w io.Writer) error {
+		a := newArchive(w)
+		for _, name := range sortedKeys(named) {
+			if err := a.add(name, named[name]); err != nil {
+				return err
+			}
+		}
+		return a.Close()
+	})
+}
+
+type zipWriter struct{ *zip.Writer }
+
+func (z zipWriter) add(name string, data []byte) error {
+	f, err := z.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+type tarWriter struct {
+	*tar.Writer
+	gz *gzip.Writer
+}
+
+func (t tarWriter) add(name string, data []byte) error {
+	err := t.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Unix(0, 0),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = t.Write(data)
+	return err
+}
+
+func (t tarWriter) Close() error {
+	if err := t.Writer.Close(); err != nil {
+		return err
+	}
+	return t.gz.Close()
+}
+
+var (
+	errExportArgs = errors.New(`export kind ("archive") argument required`)
+	errArchiveExt = errors.New("unsupported archive file extension (want .zip, .tar.gz or .tgz)")
+)
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_exportMain_archive(t *testing.T) {
+	src := writeCorpus(t, map[string]string{
+		"a":   "int(2)",
+		"b":   "int(1)",
+		"dup": "int(1)",
+	})
+	want := map[string]string{}
+	for _, v := range []string{"int(1)", "int(2)"} {
+		data := "go test fuzz v1\n" + v + "\n"
+		want[corpus.FileName([]byte(data))] = data
+	}
+	tests := map[string]struct {
+		name string
+		read func(t *testing.T, b []byte) (names []string, files map[string]string)
+	}{"zip": {
+		name: "out.zip",
+		read: readZip,
+	}, "tar.gz": {
+		name: "out.tar.gz",
+		read: readTarGz,
+	}, "tgz": {
+		name: "out.TGZ",
+		read: readTarGz,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), tt.name)
+			req := require.New(t)
+			req.NoError(exportMain(io.Discard, io.Discard, []string{"archive", src, dst}))
+			b, err := os.ReadFile(dst)
+			req.NoError(err)
+			names, files := tt.read(t, b)
+			req.Equal(sortedKeys(want), names, "sorted, without duplicates")
+			req.Equal(want, files)
+
+			// The same corpus makes the same archive.
+			req.NoError(exportMain(io.Discard, io.Discard, []string{"archive", src, dst}))
+			again, err := os.ReadFile(dst)
+			req.NoError(err)
+			req.Equal(b, again)
+		})
+	}
+}
+
+func Test_exportMain_errors(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"a": "int(1)"})
+	tests := map[string]struct {
+		args []string
+		wErr error
+	}{"no args": {
+		wErr: errExportArgs,
+	}, "unknown kind": {
+		args: []string{"tape", src, "out.zip"},
+		wErr: errExportArgs,
+	}, "no paths": {
+		args: []string{"archive"},
+		wErr: errConvertArgs,
+	}, "unknown extension": {
+		args: []string{"archive", src, "out.rar"},
+		wErr: errArchiveExt,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := exportMain(io.Discard, io.Discard, tt.args)
+			require.ErrorIs(t, err, tt.wErr)
+		})
+	}
+}
+
+func readZip(t *testing.T, b []byte) (names []string, files map[string]string) {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+	files = map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		names = append(names, f.Name)
+		files[f.Name] = string(data)
+	}
+	return
+}
+
+func readTarGz(t *testing.T, b []byte) (names []string, files map[string]string) {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	r := tar.NewReader(gz)
+	files = map[string]string{}
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		names = append(names, h.Name)
+		files[h.Name] = string(data)
+	}
+}
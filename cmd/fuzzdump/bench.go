@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+	"time"
+)
+
+// bench measures the throughput of reading from the file systems it
+// wraps and writing to its Writer. The reads may happen concurrently.
+type bench struct {
+	w       io.Writer
+	start   time.Time
+	mu      sync.Mutex
+	files   int
+	read    int64
+	written int64
+}
+
+// newBench starts measuring the throughput of writing to w.
+func newBench(w io.Writer) *bench {
+	return &bench{w: w, start: now()}
+}
+
+// now is replaced in tests.
+var now = time.Now
+
+// FS returns fsys with the reads from it measured by b.
+func (b *bench) FS(fsys fs.FS) fs.FS {
+	return benchFS{fsys, b}
+}
+
+// Write implements the [io.Writer] interface.
+func (b *bench) Write(p []byte) (n int, err error) {
+	n, err = b.w.Write(p)
+	b.mu.Lock()
+	b.written += int64(n)
+	b.mu.Unlock()
+	return
+}
+
+// report the throughput measured so far to w.
+func (b *bench) report(w io.Writer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d := now().Sub(b.start)
+	s := d.Seconds()
+	fmt.Fprintf(w, "%s: bench: %d files in %s (%.1f files/s),"+
+		" read %.3f MB (%.3f MB/s), wrote %.3f MB (%.3f MB/s)\n",
+		cmdName, b.files, d, perSecond(float64(b.files), s),
+		mb(b.read), perSecond(mb(b.read), s),
+		mb(b.written), perSecond(mb(b.written), s))
+}
+
+func mb(n int64) float64 { return float64(n) / 1e6 }
+
+func perSecond(v, s float64) float64 {
+	if s <= 0 {
+		return 0
+	}
+	return v / s
+}
+
+// benchFS counts the regular files opened in it.
+type benchFS struct {
+	fs.FS
+	b *bench
+}
+
+// Open implements the [fs.FS] interface.
+func (f benchFS) Open(name string) (fs.File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := file.Stat(); err == nil && fi.Mode().IsRegular() {
+		f.b.mu.Lock()
+		f.b.files++
+		f.b.mu.Unlock()
+	}
+	return &benchFile{file, f.b}, nil
+}
+
+// benchFile counts the bytes read from it.
+type benchFile struct {
+	fs.File
+	b *bench
+}
+
+// Read implements the [io.Reader] interface.
+func (f *benchFile) Read(p []byte) (n int, err error) {
+	n, err = f.File.Read(p)
+	f.b.mu.Lock()
+	f.b.read += int64(n)
+	f.b.mu.Unlock()
+	return
+}
+
+// ReadDir implements the [fs.ReadDirFile] interface.
+func (f *benchFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d, ok := f.File.(fs.ReadDirFile); ok {
+		return d.ReadDir(n)
+	}
+	return nil, &fs.PathError{Op: "readdir", Err: errNotDir}
+}
+
+var errNotDir = errors.New("not a directory")
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_bench(t *testing.T) {
+	defer func(v func() time.Time) { now = v }(now)
+	start := time.Unix(0, 0)
+	now = func() time.Time { return start }
+
+	fsys := fstest.MapFS{
+		"1": {Data: []byte("go test fuzz v1\nint(1)\n")},
+		"2": {Data: []byte("go test fuzz v1\nint(2)\n")},
+	}
+	w := &bytes.Buffer{}
+	b := newBench(w)
+	req := require.New(t)
+	req.NoError(fuzzdump.DumpDir(b, b.FS(fsys), "."))
+
+	now = func() time.Time { return start.Add(2 * time.Second) }
+	out := &bytes.Buffer{}
+	b.report(out)
+	req.Equal("fuzzdump: bench: 2 files in 2s (1.0 files/s),"+
+		" read 0.000 MB (0.000 MB/s), wrote 0.000 MB (0.000 MB/s)\n",
+		out.String())
+	req.Equal(2, b.files)
+	req.EqualValues(2*len("go test fuzz v1\nint(1)\n"), b.read)
+	req.EqualValues(w.Len(), b.written)
+}
+
+func Test_benchFS_Open(t *testing.T) {
+	b := newBench(io.Discard).FS(fstest.MapFS{"foo": {}})
+	t.Run("absent", func(t *testing.T) {
+		_, err := b.Open("bar")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("ReadDir on a file", func(t *testing.T) {
+		f, err := b.Open("foo")
+		req := require.New(t)
+		req.NoError(err)
+		_, err = f.(fs.ReadDirFile).ReadDir(-1)
+		req.ErrorIs(err, errNotDir)
+	})
+}
+
+func Test_perSecond(t *testing.T) {
+	require.Zero(t, perSecond(1, 0))
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// cacheCorpusDirs returns the paths of the corpus directories in the
+// fuzz cache of the Go toolchain of the fuzz targets, as
+// [cacheCorpusDir] finds them.
+func cacheCorpusDirs(targets []string) (dirs []string, err error) {
+	root, err := goOutput("env", "GOCACHE")
+	if err != nil {
+		return
+	}
+	if root == "" || root == "off" {
+		return nil, errNoGoCache
+	}
+	root = filepath.Join(root, "fuzz")
+	// The package in the current directory, if there is one, is the
+	// likeliest to have the fuzz targets.
+	pkg, _ := goOutput("list", "-f", "{{.ImportPath}}", ".")
+	dirs = make([]string, len(targets))
+	for i, t := range targets {
+		if dirs[i], err = cacheCorpusDir(root, pkg, t); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// cacheCorpusDir returns the path of the corpus directory of the fuzz
+// target in the fuzz cache at root, where the Go toolchain keeps the
+// corpus it generates for each target in a directory named after the
+// import path of its package and the target, e.g.
+// "$GOCACHE/fuzz/example.com/pkg/FuzzParse".
+//
+// If pkg is given and has a directory for target there, that is the one.
+// Otherwise the target has to be the only one with its name in the
+// cache.
+func cacheCorpusDir(root, pkg, target string) (string, error) {
+	if pkg != "" {
+		dir := filepath.Join(root, filepath.FromSlash(pkg), target)
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			return dir, nil
+		}
+	}
+	all, err := fuzzdump.FindCorpora(os.DirFS(root), ".")
+	if err != nil && !errors.Is(err, fuzzdump.ErrNoCorpora) {
+		return "", err
+	}
+	var found []string
+	for _, d := range all {
+		if filepath.Base(d) == target {
+			found = append(found, d)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", errNoCacheCorpus, target)
+	case 1:
+		return filepath.Join(root, filepath.FromSlash(found[0])), nil
+	}
+	return "", fmt.Errorf("%w: %s in %s", errAmbiguousTarget, target,
+		strings.Join(found, ", "))
+}
+
+// goOutput runs the go command with args, returning its standard
+// output, trimmed of white space.
+var goOutput = func(args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	stdErr := &bytes.Buffer{}
+	cmd.Stderr = stdErr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go %s: %w: %s", args[0], err, bytes.TrimSpace(stdErr.Bytes()))
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
+
+var (
+	errNoGoCache       = errors.New("the Go build cache is off")
+	errNoCacheCorpus   = errors.New("no corpus of fuzz target in the Go fuzz cache")
+	errAmbiguousTarget = errors.New("fuzz target has corpora of several packages in the Go fuzz cache")
+)
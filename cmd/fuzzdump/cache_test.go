@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cacheCorpusDirs(t *testing.T) {
+	cache := t.TempDir()
+	for _, dir := range []string{
+		"example.com/a/FuzzParse",
+		"example.com/b/FuzzParse",
+		"example.com/b/FuzzOnly",
+	} {
+		dir = filepath.Join(cache, "fuzz", filepath.FromSlash(dir))
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		data := []byte("go test fuzz v1\nint(1)\n")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "1"), data, 0o644))
+	}
+	tests := map[string]struct {
+		gocache string
+		pkg     string
+		targets []string
+		want    []string
+		wErr    error
+	}{"package": {
+		pkg:     "example.com/a",
+		targets: []string{"FuzzParse"},
+		want:    []string{"example.com/a/FuzzParse"},
+	}, "only one": {
+		pkg:     "example.com/a",
+		targets: []string{"FuzzOnly"},
+		want:    []string{"example.com/b/FuzzOnly"},
+	}, "no package": {
+		targets: []string{"FuzzOnly", "FuzzOnly"},
+		want:    []string{"example.com/b/FuzzOnly", "example.com/b/FuzzOnly"},
+	}, "ambiguous": {
+		targets: []string{"FuzzParse"},
+		wErr:    errAmbiguousTarget,
+	}, "absent": {
+		targets: []string{"FuzzAbsent"},
+		wErr:    errNoCacheCorpus,
+	}, "cache off": {
+		gocache: "off",
+		targets: []string{"FuzzParse"},
+		wErr:    errNoGoCache,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			gocache := tt.gocache
+			if gocache == "" {
+				gocache = cache
+			}
+			fakeGoOutput(t, map[string]string{
+				"env GOCACHE":               gocache,
+				"list -f {{.ImportPath}} .": tt.pkg,
+			})
+			got, err := cacheCorpusDirs(tt.targets)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			if tt.wErr != nil {
+				return
+			}
+			for i, w := range tt.want {
+				tt.want[i] = filepath.Join(cache, "fuzz", filepath.FromSlash(w))
+			}
+			req.Equal(tt.want, got)
+		})
+	}
+	t.Run("dump", func(t *testing.T) {
+		fakeGoOutput(t, map[string]string{"env GOCACHE": cache})
+		stdOut := &bytes.Buffer{}
+		req := require.New(t)
+		req.NoError(dumpMain(stdOut, io.Discard, []string{"-cache", "FuzzOnly"}))
+		req.Equal("{\n\tint(1),\n}\n", stdOut.String())
+	})
+}
+
+// fakeGoOutput replaces goOutput for the duration of t with one that
+// returns the outputs by the arguments joined with spaces, failing for
+// any others.
+func fakeGoOutput(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	orig := goOutput
+	t.Cleanup(func() { goOutput = orig })
+	goOutput = func(args ...string) (string, error) {
+		out, ok := outputs[strings.Join(args, " ")]
+		if !ok || out == "" && args[0] == "list" {
+			return "", errors.New("go " + args[0] + ": failed")
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// checkMain checks a fuzz test corpus directory against thresholds,
+// for use as a CI gate.
+func checkMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("check")
+	var (
+		maxEntries = fl.Int("max-entries", -1,
+			"fail if there are more than `n` entries (-1 for no limit)")
+		maxBytes = fl.Int64("max-bytes", -1,
+			"fail if the entry files take more than `n` bytes (-1 for no limit)")
+		maxInvalid = fl.Int("max-invalid", -1,
+			"fail if more than `n` entries are invalid (-1 for no limit)")
+		report = fl.String("format", reportText,
+			"report `format`: "+strings.Join(sortedKeys(reporters), ", "))
+	)
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) == 0 || len(args[0]) == 0 {
+		return errNoDirArg
+	}
+	write, ok := reporters[*report]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadFormat, *report)
+	}
+	fsys, dir, err := corpusFS(args[0])
+	if err != nil {
+		return err
+	}
+	s, err := readStats(fsys, dir)
+	if err != nil {
+		return err
+	}
+	s.dir = args[0]
+
+	breach := func(what string, n, max int64) {
+		if max >= 0 && n > max {
+			s.breaches = append(s.breaches, fmt.Sprintf("%d %s (max %d)", n, what, max))
+		}
+	}
+	breach("entries", int64(s.entries), int64(*maxEntries))
+	breach("bytes", s.bytes, *maxBytes)
+	breach("invalid", int64(len(s.invalid)), int64(*maxInvalid))
+	if err := write(w, s); err != nil {
+		return err
+	}
+	if len(s.breaches) > 0 {
+		return fmt.Errorf("%w: %s", errThreshold, strings.Join(s.breaches, ", "))
+	}
+	return nil
+}
+
+// Check report formats.
+const (
+	reportText  = "text"
+	reportSARIF = "sarif"
+	reportJUnit = "junit"
+)
+
+// A checkReporter writes a report of the stats of a checked corpus to w.
+type checkReporter func(w io.Writer, s stats) error
+
+var reporters = map[string]checkReporter{
+	reportText:  writeTextReport,
+	reportSARIF: writeSARIFReport,
+	reportJUnit: writeJUnitReport,
+}
+
+// writeTextReport writes a one-line summary of s to w.
+func writeTextReport(w io.Writer, s stats) error {
+	_, err := fmt.Fprintf(w, "%d entries, %d bytes, %d invalid\n",
+		s.entries, s.bytes, len(s.invalid))
+	return err
+}
+
+// stats of a corpus directory.
+type stats struct {
+	// Path of the directory, as given.
+	dir string
+	// Names of the entry files.
+	names   []string
+	entries int
+	bytes   int64
+	// Entries that are malformed or have malformed values.
+	invalid []invalidFile
+	// Argument types of the first valid entry.
+	types []string
+	// Descriptions of the thresholds exceeded.
+	breaches []string
+}
+
+// An invalidFile is the name of an entry file along with the error
+// that makes it invalid.
+type invalidFile struct {
+	name string
+	err  error
+}
+
+// readStats of the corpus directory dir in fsys.
+func readStats(fsys fs.FS, dir string) (s stats, err error) {
+	names, err := fileNames(fsys, dir)
+	if err != nil {
+		return
+	}
+	s.names = names
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return s, err
+		}
+		s.entries++
+		s.bytes += int64(len(b))
+		var vals []corpus.Value
+		e, err := corpus.Unmarshal(b)
+		if err == nil {
+			vals, err = e.Values()
+		}
+		if err != nil {
+			s.invalid = append(s.invalid, invalidFile{name, err})
+		} else if s.types == nil {
+			s.types = valueTypes(vals)
+		}
+	}
+	return
+}
+
+const errThreshold = fuzzdump.ErrThresholdExceeded
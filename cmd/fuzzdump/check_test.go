@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkMain(t *testing.T) {
+	// 2 entries, 23+22 bytes, 1 invalid.
+	values := map[string]string{"1": "int(1)", "2": "int(("}
+	tests := map[string]struct {
+		flags    []string
+		wErrText string
+		wCode    int
+	}{"no thresholds": {
+		wCode: fuzzdump.ExitSuccess,
+	}, "within thresholds": {
+		flags: []string{"-max-entries", "2", "-max-bytes", "45", "-max-invalid", "1"},
+		wCode: fuzzdump.ExitSuccess,
+	}, "entries": {
+		flags:    []string{"-max-entries", "1"},
+		wErrText: errThreshold.Error() + ": 2 entries (max 1)",
+		wCode:    fuzzdump.ExitThreshold,
+	}, "all": {
+		flags: []string{"-max-entries", "0", "-max-bytes", "44", "-max-invalid", "0"},
+		wErrText: errThreshold.Error() +
+			": 2 entries (max 0), 45 bytes (max 44), 1 invalid (max 0)",
+		wCode: fuzzdump.ExitThreshold,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dir := writeCorpus(t, values)
+			stdOut := &bytes.Buffer{}
+			err := checkMain(stdOut, io.Discard, append(tt.flags, dir))
+			req := require.New(t)
+			if tt.wErrText != "" {
+				req.EqualError(err, tt.wErrText)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wCode, exitCodeFor(err))
+			req.Equal("2 entries, 45 bytes, 1 invalid\n", stdOut.String())
+		})
+	}
+}
+
+func Test_checkMain_errors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		wErr error
+	}{
+		"no dir":     {wErr: errNoDirArg},
+		"bad format": {args: []string{"-format", "xml", "dir"}, wErr: errBadFormat},
+		"absent":     {args: []string{filepath.Join(t.TempDir(), "absent")}, wErr: os.ErrNotExist},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := checkMain(io.Discard, io.Discard, tt.args)
+			require.ErrorIs(t, err, tt.wErr)
+		})
+	}
+}
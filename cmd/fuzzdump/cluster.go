@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// clusterMain reports the clusters of similar entries of a fuzz test
+// corpus directory: how many entries each has, and a few of them as
+// examples.
+func clusterMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("cluster")
+	var (
+		maxDist = fl.Int("max-distance", 12,
+			"cluster entries whose 64-bit simhashes differ in at most `n` bits")
+		examples = fl.Int("examples", 3,
+			"list up to `n` entries of each cluster as examples")
+	)
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 1 || args[0] == "" {
+		return errNoDirArg
+	}
+	if *maxDist < 0 || *maxDist > 64 {
+		return fmt.Errorf("%w: %d", errBadDistance, *maxDist)
+	}
+	fsys, dir, err := corpusFS(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := fuzzdump.ReadDir(fsys, dir, fuzzdump.WithNormalizedValues())
+	var errs fuzzdump.CorpusErrors
+	if e := errs.Capture(err); e != nil {
+		return e
+	}
+	clusters, err := clusterEntries(c, *maxDist)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%d entries, %d clusters\n", len(c), len(clusters)); err != nil {
+		return err
+	}
+	for i, cl := range clusters {
+		names := cl.names
+		if len(names) > *examples {
+			names = names[:*examples]
+		}
+		s := fmt.Sprintf("cluster %d: %d entries", i+1, len(cl.names))
+		if len(names) > 0 {
+			s += ", e.g. " + strings.Join(names, ", ")
+		}
+		if _, err := fmt.Fprintln(w, s); err != nil {
+			return err
+		}
+	}
+	return errs.AsError()
+}
+
+// An entryCluster holds the names of the files of similar entries, the
+// first of which represents them.
+type entryCluster struct {
+	hash  uint64 // Of the representative entry.
+	names []string
+}
+
+// clusterEntries clusters the entries of c by the similarity of their
+// contents: each joins the first cluster whose representative has a
+// simhash differing from its own in at most maxDist bits, or starts a
+// new one. The clusters are returned largest first.
+func clusterEntries(c fuzzdump.Corpus, maxDist int) ([]entryCluster, error) {
+	var clusters []entryCluster
+	for _, f := range c {
+		b, err := entryContents(f.Entry)
+		if err != nil {
+			return nil, &fuzzdump.FileError{Name: f.Name, Err: err}
+		}
+		h := simhash(b)
+		i := 0
+		for ; i < len(clusters); i++ {
+			if bits.OnesCount64(clusters[i].hash^h) <= maxDist {
+				break
+			}
+		}
+		if i == len(clusters) {
+			clusters = append(clusters, entryCluster{hash: h})
+		}
+		clusters[i].names = append(clusters[i].names, f.Name)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].names) > len(clusters[j].names)
+	})
+	return clusters, nil
+}
+
+// entryContents returns the contents of the values of e, one after
+// another: those of strings and []byte as they are, and those of other
+// types encoded as in a corpus entry file.
+func entryContents(e corpus.Entry) ([]byte, error) {
+	vals, err := e.Values()
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	for i, v := range vals {
+		switch v := v.(type) {
+		case string:
+			b = append(b, v...)
+		case []byte:
+			b = append(b, v...)
+		default:
+			b = append(b, e[i]...)
+		}
+	}
+	return b, nil
+}
+
+// simhash returns the simhash of the n-grams of b, or of b as a whole,
+// if it is shorter than one, so that similar contents get hashes that
+// differ in few bits.
+func simhash(b []byte) uint64 {
+	var weights [64]int
+	add := func(gram []byte) {
+		h := fnv.New64a()
+		h.Write(gram)
+		sum := h.Sum64()
+		for i := range weights {
+			if sum>>i&1 == 1 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	if len(b) < simhashGram {
+		add(b)
+	}
+	for i := 0; i+simhashGram <= len(b); i++ {
+		add(b[i : i+simhashGram])
+	}
+	var h uint64
+	for i, w := range weights {
+		if w > 0 {
+			h |= 1 << i
+		}
+	}
+	return h
+}
+
+// simhashGram is the length of the n-grams that simhash hashes.
+const simhashGram = 3
+
+var errBadDistance = errors.New("distance must be from 0 to 64 bits")
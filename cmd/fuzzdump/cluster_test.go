@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"math/bits"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_clusterMain(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{
+		"a1": `string("GET /index.html HTTP/1.1\r\nHost: example.com\r\n")`,
+		"a2": `string("GET /index.htm HTTP/1.1\r\nHost: example.com\r\n")`,
+		"a3": `string("GET /index.html HTTP/1.0\r\nHost: example.org\r\n")`,
+		"b1": `string("{\"id\": 1, \"tags\": [\"x\", \"y\"], \"ok\": true}")`,
+		"b2": `string("{\"id\": 2, \"tags\": [\"x\", \"y\"], \"ok\": true}")`,
+		"c1": `string("\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09")`,
+	})
+	bad := writeCorpus(t, map[string]string{"a": `string("x")`, "b": "int(2"})
+	tests := map[string]struct {
+		args []string
+		wOut string
+		wErr error
+	}{"clusters": {
+		args: []string{"-examples", "2", dir},
+		wOut: "6 entries, 3 clusters\n" +
+			"cluster 1: 3 entries, e.g. a1, a2\n" +
+			"cluster 2: 2 entries, e.g. b1, b2\n" +
+			"cluster 3: 1 entries, e.g. c1\n",
+	}, "exact": {
+		args: []string{"-max-distance", "0", "-examples", "0", dir},
+		wOut: "6 entries, 6 clusters\n" +
+			"cluster 1: 1 entries\ncluster 2: 1 entries\ncluster 3: 1 entries\n" +
+			"cluster 4: 1 entries\ncluster 5: 1 entries\ncluster 6: 1 entries\n",
+	}, "invalid entry": {
+		args: []string{bad},
+		wOut: "1 entries, 1 clusters\ncluster 1: 1 entries, e.g. a\n",
+		wErr: fuzzdump.ErrMalformedValue,
+	}, "bad distance": {
+		args: []string{"-max-distance", "65", dir},
+		wErr: errBadDistance,
+	}, "no dir": {
+		wErr: errNoDirArg,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := clusterMain(w, io.Discard, tt.args)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, w.String())
+		})
+	}
+}
+
+func Test_simhash(t *testing.T) {
+	req := require.New(t)
+	a := simhash([]byte("the quick brown fox jumps over the lazy dog"))
+	b := simhash([]byte("the quick brown fox jumped over the lazy dog"))
+	c := simhash([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x10"))
+	req.Less(bits.OnesCount64(a^b), bits.OnesCount64(a^c))
+	req.Equal(simhash([]byte("ab")), simhash([]byte("ab")))
+	req.NotEqual(simhash([]byte("ab")), simhash([]byte("ba")))
+}
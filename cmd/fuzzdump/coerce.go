@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// coerceFiles coerces the values of the entries of files to the
+// argument types in sig, as [coerce] does. The entries that cannot be
+// coerced are left out and their errors returned.
+func coerceFiles(
+	files []corpus.File, sig []string,
+) (out []corpus.File, errs fuzzdump.CorpusErrors) {
+	for _, f := range files {
+		e, err := coerceEntry(f.Entry, sig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting %q: %w", f.Name, err))
+			continue
+		}
+		out = append(out, corpus.File{Name: f.Name, Entry: e})
+	}
+	return
+}
+
+// coerceEntry returns e with its values coerced to the argument types
+// in sig.
+func coerceEntry(e corpus.Entry, sig []string) (corpus.Entry, error) {
+	vals, err := e.Values()
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) != len(sig) {
+		return nil, fmt.Errorf("%w: want %d, got %d",
+			fuzzdump.ErrInconsistentArgCount, len(sig), len(vals))
+	}
+	for i, v := range vals {
+		if vals[i], err = coerce(v, sig[i]); err != nil {
+			return nil, fmt.Errorf("arg%d: %w", i, err)
+		}
+	}
+	return corpus.NewEntry(vals...)
+}
+
+// coerce returns v as a value of the named type, as long as that can
+// represent it exactly: an integer as any integer type it is within the
+// range of, a float as any float type that has the same value, and a
+// string as a []byte, or vice versa.
+func coerce(v corpus.Value, typ string) (corpus.Value, error) {
+	if valueTypes([]corpus.Value{v})[0] == typ {
+		return v, nil
+	}
+	switch x := v.(type) {
+	case string:
+		if typ == "[]byte" {
+			return []byte(x), nil
+		}
+	case []byte:
+		if typ == "string" {
+			return string(x), nil
+		}
+	case float32:
+		if typ == "float64" {
+			return float64(x), nil
+		}
+	case float64:
+		if typ == "float32" && (float64(float32(x)) == x || math.IsNaN(x)) {
+			return float32(x), nil
+		}
+	default:
+		if n, ok := intValue(v); ok {
+			if c, ok := coerceInt(n, typ); ok {
+				return c, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %T(%v) as %s", errCoerce, v, v, typ)
+}
+
+// anInt is an integer of any type, as its sign and magnitude.
+type anInt struct {
+	neg bool
+	abs uint64
+}
+
+// intValue returns v as anInt, if it is an integer.
+func intValue(v corpus.Value) (n anInt, ok bool) {
+	signed := func(i int64) (anInt, bool) {
+		if i < 0 {
+			return anInt{true, uint64(-(i + 1)) + 1}, true
+		}
+		return anInt{false, uint64(i)}, true
+	}
+	switch x := v.(type) {
+	case int:
+		return signed(int64(x))
+	case int8:
+		return signed(int64(x))
+	case int16:
+		return signed(int64(x))
+	case int32:
+		return signed(int64(x))
+	case int64:
+		return signed(x)
+	case uint:
+		return anInt{abs: uint64(x)}, true
+	case uint8:
+		return anInt{abs: uint64(x)}, true
+	case uint16:
+		return anInt{abs: uint64(x)}, true
+	case uint32:
+		return anInt{abs: uint64(x)}, true
+	case uint64:
+		return anInt{abs: x}, true
+	}
+	return
+}
+
+// coerceInt returns n as a value of the named integer type, if it is
+// within its range.
+func coerceInt(n anInt, typ string) (v corpus.Value, ok bool) {
+	bits, signed := intTypes[typ].bits, intTypes[typ].signed
+	if bits == 0 {
+		return
+	}
+	if signed {
+		limit := uint64(1) << (bits - 1)
+		if n.neg && n.abs > limit || !n.neg && n.abs >= limit {
+			return
+		}
+	} else if n.neg || bits < 64 && n.abs >= 1<<bits {
+		return
+	}
+	i, u := int64(n.abs), n.abs
+	if n.neg {
+		i = -int64(n.abs-1) - 1
+	}
+	switch typ {
+	case "int":
+		return int(i), true
+	case "int8":
+		return int8(i), true
+	case "int16":
+		return int16(i), true
+	case "int32":
+		return int32(i), true
+	case "int64":
+		return i, true
+	case "uint":
+		return uint(u), true
+	case "uint8":
+		return uint8(u), true
+	case "uint16":
+		return uint16(u), true
+	case "uint32":
+		return uint32(u), true
+	}
+	return u, true
+}
+
+// intTypes by their names.
+var intTypes = map[string]struct {
+	bits   uint
+	signed bool
+}{
+	"int":    {strconv.IntSize, true},
+	"int8":   {8, true},
+	"int16":  {16, true},
+	"int32":  {32, true},
+	"int64":  {64, true},
+	"uint":   {strconv.IntSize, false},
+	"uint8":  {8, false},
+	"uint16": {16, false},
+	"uint32": {32, false},
+	"uint64": {64, false},
+}
+
+var errCoerce = errors.New("value cannot be represented in the target type")
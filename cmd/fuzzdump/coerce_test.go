@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_coerce(t *testing.T) {
+	tests := map[string]struct {
+		v    corpus.Value
+		typ  string
+		want corpus.Value
+	}{
+		"same":               {v: int(5), typ: "int", want: int(5)},
+		"widened":            {v: int(5), typ: "int64", want: int64(5)},
+		"narrowed":           {v: int64(-128), typ: "int8", want: int8(-128)},
+		"narrowed too far":   {v: int64(-129), typ: "int8"},
+		"to unsigned":        {v: int8(127), typ: "uint8", want: uint8(127)},
+		"negative unsigned":  {v: int64(-1), typ: "uint"},
+		"unsigned to signed": {v: uint8(200), typ: "int8"},
+		"max uint64":         {v: uint64(math.MaxUint64), typ: "int64"},
+		"min int64":          {v: int64(math.MinInt64), typ: "int32"},
+		"max uint32":         {v: uint32(math.MaxUint32), typ: "uint16"},
+		"uint64 to uint32":   {v: uint64(math.MaxUint32), typ: "uint32", want: uint32(math.MaxUint32)},
+		"float exact":        {v: float64(0.5), typ: "float32", want: float32(0.5)},
+		"float inexact":      {v: float64(0.1), typ: "float32"},
+		"float widened":      {v: float32(0.1), typ: "float64", want: float64(float32(0.1))},
+		"float overflow":     {v: float64(math.MaxFloat64), typ: "float32"},
+		"string to bytes":    {v: "foo", typ: "[]byte", want: []byte("foo")},
+		"bytes to string":    {v: []byte("foo"), typ: "string", want: "foo"},
+		"bool to int":        {v: true, typ: "int"},
+		"int to float":       {v: int(1), typ: "float64"},
+		"unknown type":       {v: int(1), typ: "complex128"},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := coerce(tt.v, tt.typ)
+			req := require.New(t)
+			if tt.want == nil {
+				req.ErrorIs(err, errCoerce)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+	if math.MaxInt == math.MaxInt64 {
+		t.Run("min int64 to int", func(t *testing.T) {
+			got, err := coerce(int64(math.MinInt64), "int")
+			require.NoError(t, err)
+			require.Equal(t, int(math.MinInt64), got)
+		})
+	}
+}
+
+func Test_convertMain_coerce(t *testing.T) {
+	src := writeCorpus(t, map[string]string{
+		"a": "int(1)\nstring(\"foo\")",
+		"b": "int(300)\nstring(\"bar\")",
+		"c": "int(2)",
+	})
+	dst := filepath.Join(t.TempDir(), "dst")
+	err := convertMain(io.Discard, io.Discard, []string{"-coerce", "int8,[]byte", src, dst})
+	req := require.New(t)
+	req.ErrorIs(err, errCoerce)
+	req.ErrorIs(err, fuzzdump.ErrInconsistentArgCount)
+	req.Equal(fuzzdump.ExitSoft, exitCodeFor(err))
+
+	want := []byte("go test fuzz v1\nint8(1)\n[]byte(\"foo\")\n")
+	names, err := os.ReadDir(dst)
+	req.NoError(err)
+	req.Len(names, 1)
+	got, err := os.ReadFile(filepath.Join(dst, names[0].Name()))
+	req.NoError(err)
+	req.True(bytes.Equal(want, got), "%q", got)
+
+	err = convertMain(io.Discard, io.Discard, []string{"-coerce", "bool", src, dst})
+	req.ErrorIs(err, fuzzdump.ErrEmptyCorpus)
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/corpusdir"
+)
+
+// convertMain converts a fuzz test corpus from one format to another.
+func convertMain(w, _ io.Writer, args []string) (err error) {
+	fl := newFlagSet("convert")
+	var (
+		from = fl.String("from", formatAuto,
+			"source `format`: "+strings.Join(sortedKeys(readers), ", ")+", or auto")
+		to = fl.String("to", formatV1,
+			"destination `format`: "+strings.Join(sortedKeys(writers), ", "))
+		coerceTo = fl.String("coerce", "",
+			"coerce values to the comma-separated argument `types`, e.g. \"int64,[]byte\"")
+		tags = fl.String("tags", "",
+			"convert just the entries that have any of the comma-separated `tags`")
+		record = fl.Bool("provenance", false,
+			"record where the entries came from in a sidecar file (v1 only)")
+	)
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return errConvertArgs
+	}
+	src, dst := args[0], args[1]
+	if dst != "-" {
+		unlock, err := lockCorpus(dst)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	if *from == formatAuto {
+		if *from, err = detectFormat(src); err != nil {
+			return
+		}
+	}
+	read, ok := readers[*from]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadFormat, *from)
+	}
+	write, ok := writers[*to]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadFormat, *to)
+	}
+	if *record && *to != formatV1 {
+		return fmt.Errorf("%w: %q", errProvenanceFormat, *to)
+	}
+	var errs fuzzdump.CorpusErrors
+	files, err := read(src)
+	if e := errs.Capture(err); e != nil {
+		return e
+	}
+	if *tags != "" {
+		if files, err = filterTagged(files, src, strings.Split(*tags, ",")); err != nil {
+			return err
+		}
+	}
+	if *coerceTo != "" {
+		var cErrs fuzzdump.CorpusErrors
+		files, cErrs = coerceFiles(files, parseSignature(*coerceTo))
+		if errs = append(errs, cErrs...); len(files) == 0 {
+			return errs.Capture(fuzzdump.ErrEmptyCorpus)
+		}
+	}
+	if err := write(w, dst, files); err != nil {
+		return err
+	}
+	if *record {
+		if err := recordProvenances(dst, src, *from, files); err != nil {
+			return err
+		}
+	}
+	return errs.AsError()
+}
+
+// Corpus formats.
+const (
+	formatAuto      = "auto"
+	formatRaw       = "raw"
+	formatV1        = "v1"
+	formatJSONDump  = "json-dump"
+	formatJSON      = "json"
+	formatNDJSON    = "ndjson"
+	formatLibFuzzer = "libfuzzer"
+	formatZip       = "zip"
+	formatTarGz     = "tar.gz"
+)
+
+// A corpusReader reads the entry files of a corpus at src.
+//
+// Along with the valid entries, it may return [fuzzdump.CorpusErrors]
+// reporting the invalid ones.
+type corpusReader func(src string) ([]corpus.File, error)
+
+// A corpusWriter writes the entry files of a corpus to dst, or, if dst
+// is "-" and the format allows that, to w.
+type corpusWriter func(w io.Writer, dst string, files []corpus.File) error
+
+var (
+	readers = map[string]corpusReader{
+		formatRaw:      readRawDir,
+		formatV1:       readV1Dir,
+		formatJSONDump: readJSONDump,
+	}
+	writers = map[string]corpusWriter{
+		formatV1:        writeV1Dir,
+		formatJSON:      writeJSONDump,
+		formatNDJSON:    writeNDJSONDump,
+		formatLibFuzzer: writeRawDir,
+		formatZip:       writeZip,
+		formatTarGz:     writeTarGz,
+	}
+)
+
+// detectFormat returns the format of the corpus at src: a file is
+// taken for a JSON dump, a directory with its first file starting with
+// the version 1 encoding header, for a Go corpus, and any other
+// directory, for a raw one.
+func detectFormat(src string) (string, error) {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return formatJSONDump, nil
+	}
+	fsys, dir, err := corpusFS(src)
+	if err != nil {
+		return "", err
+	}
+	names, err := fileNames(fsys, dir)
+	if err != nil || len(names) == 0 {
+		return formatV1, err
+	}
+	b, err := fs.ReadFile(fsys, path.Join(dir, names[0]))
+	if err != nil {
+		return "", err
+	}
+	if bytes.HasPrefix(b, []byte(corpus.Version1)) {
+		return formatV1, nil
+	}
+	return formatRaw, nil
+}
+
+// readV1Dir reads a Go fuzz test corpus directory.
+func readV1Dir(src string) ([]corpus.File, error) {
+	return readDirFiles(src, func(data []byte) (corpus.Entry, error) {
+		return corpus.Unmarshal(data)
+	})
+}
+
+// readRawDir reads a directory of raw inputs, such as a libFuzzer
+// corpus, taking each file for a single []byte argument.
+func readRawDir(src string) ([]corpus.File, error) {
+	return readDirFiles(src, func(data []byte) (corpus.Entry, error) {
+		return corpus.NewEntry(data)
+	})
+}
+
+// readDirFiles reads the entries from the regular files in the src
+// directory, using decode to make an entry of the data of each.
+func readDirFiles(
+	src string, decode func(data []byte) (corpus.Entry, error),
+) (files []corpus.File, err error) {
+	fsys, dir, err := corpusFS(src)
+	if err != nil {
+		return
+	}
+	names, err := fileNames(fsys, dir)
+	if err != nil {
+		return
+	}
+	var errs fuzzdump.CorpusErrors
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(data)
+		if err != nil {
+			if err = errs.Capture(&fuzzdump.FileError{Name: name, Err: err}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		files = append(files, corpus.File{Name: name, Entry: e})
+	}
+	if len(files) == 0 {
+		return nil, errs.Capture(fuzzdump.ErrEmptyCorpus)
+	}
+	return files, errs.AsError()
+}
+
+// fileNames returns the names of the regular files in dir in fsys.
+func fileNames(fsys fs.FS, dir string) (names []string, err error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			names = append(names, e.Name())
+		}
+	}
+	return
+}
+
+// readJSONDump reads a JSON dump file, or the standard input, if src
+// is "-". The dump may be either a JSON array of entry files, or an
+// NDJSON stream of them, one per line.
+func readJSONDump(src string) (files []corpus.File, err error) {
+	var b []byte
+	if src == "-" {
+		b, err = io.ReadAll(stdIn)
+	} else {
+		b, err = os.ReadFile(src)
+	}
+	if err != nil {
+		return
+	}
+	dump, err := decodeJSONDump(b)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", src, err)
+	}
+	var errs fuzzdump.CorpusErrors
+	for _, d := range dump {
+		var e corpus.Entry
+		if err := json.Unmarshal(d.Values, &e); err != nil {
+			if err = errs.Capture(&fuzzdump.FileError{Name: d.Name, Err: err}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		files = append(files, corpus.File{Name: d.Name, Entry: e})
+	}
+	if len(files) == 0 {
+		return nil, errs.Capture(fuzzdump.ErrEmptyCorpus)
+	}
+	return files, errs.AsError()
+}
+
+// jsonDumpFile is a [corpus.File] with its values left to be decoded
+// separately, so that each invalid entry can be reported.
+type jsonDumpFile struct {
+	Name   string          `json:"name"`
+	Values json.RawMessage `json:"values"`
+}
+
+// decodeJSONDump decodes the entry files of a JSON array or NDJSON dump
+// in b.
+func decodeJSONDump(b []byte) (dump []jsonDumpFile, err error) {
+	if b = bytes.TrimSpace(b); bytes.HasPrefix(b, []byte("[")) {
+		err = json.Unmarshal(b, &dump)
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for {
+		var f jsonDumpFile
+		if err = dec.Decode(&f); err == io.EOF {
+			return dump, nil
+		} else if err != nil {
+			return nil, err
+		}
+		dump = append(dump, f)
+	}
+}
+
+// stdIn is replaced in tests.
+var stdIn io.Reader = os.Stdin
+
+// writeV1Dir writes the files to the dst directory in version 1
+// encoding, each named as the Go toolchain would name it.
+func writeV1Dir(_ io.Writer, dst string, files []corpus.File) error {
+	return writeDirFiles(dst, files, encodeV1)
+}
+
+// encodeV1 returns the version 1 encoding of e, and the name the Go
+// toolchain would give a file with it.
+func encodeV1(e corpus.Entry) (name string, data []byte, err error) {
+	if data, err = corpus.Marshal(e); err != nil {
+		return
+	}
+	return corpus.FileName(data), data, nil
+}
+
+// writeRawDir writes the files to the dst directory as raw inputs, such
+// as for a libFuzzer corpus, each named after its SHA-1 hash, as
+// libFuzzer does. Only entries of a single []byte or string argument
+// can be written this way.
+func writeRawDir(_ io.Writer, dst string, files []corpus.File) error {
+	return writeDirFiles(dst, files, func(e corpus.Entry) (string, []byte, error) {
+		vals, err := e.Values()
+		if err != nil {
+			return "", nil, err
+		}
+		if len(vals) != 1 {
+			return "", nil, errNotRaw
+		}
+		var data []byte
+		switch v := vals[0].(type) {
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		default:
+			return "", nil, errNotRaw
+		}
+		return fmt.Sprintf("%x", sha1.Sum(data)), data, nil
+	})
+}
+
+// writeDirFiles writes the files to the dst directory, creating it, if
+// necessary, using encode to get the name and data to write of each.
+// Nothing is written unless all of them can be, see [writeDir].
+func writeDirFiles(
+	dst string,
+	files []corpus.File,
+	encode func(e corpus.Entry) (name string, data []byte, err error),
+) error {
+	return writeDir(dst, func(stage *corpusdir.Stage) error {
+		for _, f := range files {
+			name, data, err := encode(f.Entry)
+			if err != nil {
+				return fmt.Errorf("converting %q: %w", f.Name, err)
+			}
+			if err := stage.WriteFile(name, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeJSONDump writes the files to the dst file as a JSON dump, or to
+// w, if dst is "-".
+func writeJSONDump(w io.Writer, dst string, files []corpus.File) error {
+	return writeDumpFile(w, dst, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(files)
+	})
+}
+
+// writeNDJSONDump writes the files to the dst file as an NDJSON dump,
+// one per line, or to w, if dst is "-".
+func writeNDJSONDump(w io.Writer, dst string, files []corpus.File) error {
+	return writeDumpFile(w, dst, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		for _, f := range files {
+			if err := enc.Encode(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeDumpFile with the output of write, or writes it to w, if dst is
+// "-".
+func writeDumpFile(w io.Writer, dst string, write func(w io.Writer) error) error {
+	if dst == "-" {
+		return write(w)
+	}
+	return writeFile(dst, write)
+}
+
+// sortedKeys of m.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var (
+	errConvertArgs      = errors.New("source and destination path arguments required")
+	errBadFormat        = errors.New("unsupported corpus format")
+	errProvenanceFormat = errors.New("provenance can only be recorded for v1 destinations")
+	errNotRaw           = errors.New("raw inputs must be a single []byte or string argument")
+)
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_convertMain(t *testing.T) {
+	const jsonDump = `[
+	{
+		"name": "1",
+		"values": [
+			{
+				"type": "int",
+				"value": 5
+			}
+		]
+	},
+	{
+		"name": "2",
+		"values": [
+			{
+				"type": "int",
+				"value": 3
+			}
+		]
+	}
+]
+`
+	tests := map[string]struct {
+		args     func(t *testing.T) []string
+		wOut     string
+		wErr     error
+		wErrText string
+	}{"no args": {
+		args: func(*testing.T) []string { return nil },
+		wErr: errConvertArgs,
+	}, "help": {
+		args: func(*testing.T) []string { return []string{"-h"} },
+		wOut: "  -from format\n",
+	}, "bad from": {
+		args: func(t *testing.T) []string {
+			return []string{"-from", "foo", corpusDir(t), "-"}
+		},
+		wErrText: errBadFormat.Error() + `: "foo"`,
+	}, "bad to": {
+		args: func(t *testing.T) []string {
+			return []string{"-to", "foo", corpusDir(t), "-"}
+		},
+		wErrText: errBadFormat.Error() + `: "foo"`,
+	}, "absent source": {
+		args: func(t *testing.T) []string {
+			return []string{filepath.Join(t.TempDir(), "absent"), "-"}
+		},
+		wErr: os.ErrNotExist,
+	}, "v1 to json": {
+		args: func(t *testing.T) []string {
+			return []string{"-to", "json", corpusDir(t), "-"}
+		},
+		wOut: jsonDump,
+	}, "invalid entry": {
+		args: func(t *testing.T) []string {
+			dir := writeCorpus(t, map[string]string{"1": "int(5)"})
+			os.WriteFile(filepath.Join(dir, "2"), []byte(corpus.Version1+"\n"), 0o644)
+			return []string{"-from", "v1", "-to", "json", dir, "-"}
+		},
+		wErr: fuzzdump.ErrMalformedEntry,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut := &bytes.Buffer{}
+			err := convertMain(stdOut, io.Discard, tt.args(t))
+			req := require.New(t)
+			switch {
+			case tt.wErr != nil:
+				req.ErrorIs(err, tt.wErr)
+			case tt.wErrText != "":
+				req.EqualError(err, tt.wErrText)
+			default:
+				req.NoError(err)
+			}
+			req.Contains(stdOut.String(), tt.wOut)
+		})
+	}
+}
+
+func Test_convertMain_roundTrip(t *testing.T) {
+	src := writeCorpus(t, map[string]string{
+		"a": `string("foo")`,
+		"b": `[]byte("\xff")`,
+	})
+	tmp := t.TempDir()
+	var (
+		raw  = filepath.Join(tmp, "raw")
+		dump = filepath.Join(tmp, "dump.json")
+		v1   = filepath.Join(tmp, "v1")
+	)
+	req := require.New(t)
+	for _, args := range [][]string{
+		{"-to", "libfuzzer", src, raw},
+		{"-to", "json", raw, dump},
+		{dump, v1},
+	} {
+		req.NoError(convertMain(io.Discard, io.Discard, args), "%q", args)
+	}
+	for _, data := range []string{"foo", "\xff"} {
+		b, err := os.ReadFile(filepath.Join(raw, fmt.Sprintf("%x", sha1.Sum([]byte(data)))))
+		req.NoError(err)
+		req.Equal(data, string(b))
+	}
+	for _, v := range []string{`[]byte("foo")`, `[]byte("\xff")`} {
+		data := []byte(corpus.Version1 + "\n" + v + "\n")
+		b, err := os.ReadFile(filepath.Join(v1, corpus.FileName(data)))
+		req.NoError(err)
+		req.Equal(string(data), string(b))
+	}
+}
+
+func Test_convertMain_stdin(t *testing.T) {
+	defer func(v io.Reader) { stdIn = v }(stdIn)
+	stdIn = strings.NewReader(`[{"name":"x","values":[{"type":"bool","value":true}]}]`)
+	dst := filepath.Join(t.TempDir(), "v1")
+	req := require.New(t)
+	req.NoError(convertMain(io.Discard, io.Discard, []string{"-from", "json-dump", "-", dst}))
+	data := corpus.Version1 + "\nbool(true)\n"
+	b, err := os.ReadFile(filepath.Join(dst, corpus.FileName([]byte(data))))
+	req.NoError(err)
+	req.Equal(data, string(b))
+}
+
+func Test_detectFormat(t *testing.T) {
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "dump.json")
+	rawDir := filepath.Join(tmp, "raw")
+	emptyDir := filepath.Join(tmp, "empty")
+	req := require.New(t)
+	req.NoError(os.WriteFile(file, []byte("[]"), 0o644))
+	req.NoError(os.Mkdir(rawDir, 0o755))
+	req.NoError(os.WriteFile(filepath.Join(rawDir, "1"), []byte("foo"), 0o644))
+	req.NoError(os.Mkdir(emptyDir, 0o755))
+
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"json dump": {src: file, want: formatJSONDump},
+		"v1":        {src: corpusDir(t), want: formatV1},
+		"raw":       {src: rawDir, want: formatRaw},
+		"empty":     {src: emptyDir, want: formatV1},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := detectFormat(tt.src)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+}
+
+func Test_readJSONDump(t *testing.T) {
+	tests := map[string]struct {
+		json     string
+		wFiles   int
+		wErr     error
+		wErrText string
+	}{"syntax": {
+		json:     "[",
+		wErrText: "unexpected end of JSON input",
+	}, "empty": {
+		json: "[]",
+		wErr: fuzzdump.ErrEmptyCorpus,
+	}, "malformed value": {
+		json: `[{"name":"a","values":[{"type":"int","value":1}]},` +
+			`{"name":"b","values":[{"type":"int8","value":1000}]}]`,
+		wFiles: 1,
+		wErr:   fuzzdump.ErrMalformedValue,
+	}, "ndjson syntax": {
+		json:     `{"name":"a","values":[]}` + "\n{",
+		wErrText: "unexpected EOF",
+	}, "bad values": {
+		json:     `[{"name":"a","values":{}}]`,
+		wErrText: "cannot unmarshal",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "dump.json")
+			req := require.New(t)
+			req.NoError(os.WriteFile(src, []byte(tt.json), 0o644))
+			files, err := readJSONDump(src)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.ErrorContains(err, tt.wErrText)
+			}
+			req.Len(files, tt.wFiles)
+		})
+	}
+}
+
+func Test_convertMain_ndjson(t *testing.T) {
+	src := writeCorpus(t, map[string]string{
+		"a": "int(1)\nstring(\"x\")",
+		"b": "int(2)\nstring(\"y\")",
+	})
+	tmp := t.TempDir()
+	dump := filepath.Join(tmp, "dump.ndjson")
+	dst := filepath.Join(tmp, "v1")
+	req := require.New(t)
+	req.NoError(convertMain(io.Discard, io.Discard, []string{"-to", "ndjson", src, dump}))
+	b, err := os.ReadFile(dump)
+	req.NoError(err)
+	req.Equal(`{"name":"a","values":[{"type":"int","value":1},{"type":"string","value":"x"}]}`+"\n"+
+		`{"name":"b","values":[{"type":"int","value":2},{"type":"string","value":"y"}]}`+"\n",
+		string(b))
+
+	req.NoError(convertMain(io.Discard, io.Discard, []string{dump, dst}))
+	for _, v := range []string{"int(1)\nstring(\"x\")", "int(2)\nstring(\"y\")"} {
+		data := corpus.Version1 + "\n" + v + "\n"
+		b, err := os.ReadFile(filepath.Join(dst, corpus.FileName([]byte(data))))
+		req.NoError(err)
+		req.Equal(data, string(b))
+	}
+}
+
+func Test_writeRawDir(t *testing.T) {
+	tests := map[string]corpus.Entry{
+		"multiple args": {[]byte("int(1)"), []byte("int(2)")},
+		"not bytes":     {[]byte("int(1)")},
+		"malformed":     {[]byte("int(")},
+	}
+	for n, e := range tests {
+		t.Run(n, func(t *testing.T) {
+			files := []corpus.File{{Name: "x", Entry: e}}
+			err := writeRawDir(io.Discard, t.TempDir(), files)
+			require.ErrorContains(t, err, `converting "x": `)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// coverageMain reports the code coverage of each entry of a fuzz test
+// corpus directory, and which of them cover code that none of the others
+// do.
+func coverageMain(w, stdErr io.Writer, args []string) error {
+	fl := newFlagSet("coverage")
+	pkg := fl.String("pkg", "",
+		"the `dir`ectory of the package with the fuzz test (default DIR/../../..)")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 1 || args[0] == "" {
+		return errNoDirArg
+	}
+	c, err := measureCoverage(stdErr, args[0], *pkg)
+	if c == nil {
+		return err
+	}
+	if wErr := writeCoverage(w, c); wErr != nil {
+		return wErr
+	}
+	return err
+}
+
+// A coverSet is a set of the code blocks, as named in cover profiles
+// (e.g. "example.com/pkg/file.go:12.2,14.16"), that an entry covers.
+type coverSet map[string]bool
+
+// coverage is the code each entry of a corpus covers, by entry name.
+type coverage map[string]coverSet
+
+// unique returns the number of the blocks that the named entry covers,
+// but none of the others do.
+func (c coverage) unique(name string) (n int) {
+	for b := range c[name] {
+		if !c.coveredByOthers(b, name) {
+			n++
+		}
+	}
+	return
+}
+
+// coveredByOthers reports whether any entry but the named one covers
+// block.
+func (c coverage) coveredByOthers(block, name string) bool {
+	for other, s := range c {
+		if other != name && s[block] {
+			return true
+		}
+	}
+	return false
+}
+
+// total returns the number of the blocks that any of the entries cover.
+func (c coverage) total() int {
+	all := coverSet{}
+	for _, s := range c {
+		for b := range s {
+			all[b] = true
+		}
+	}
+	return len(all)
+}
+
+// measureCoverage runs the fuzz test named as the corpus directory dir
+// with each of its entries in turn, collecting the code each covers.
+// The package with the test is in pkgDir, or, if that is empty, three
+// levels up from dir, as in "pkg/testdata/fuzz/FuzzMyFunc".
+//
+// An entry that the test fails with is reported to stdErr and left out,
+// but the coverage of the rest is still returned, along with
+// errEntriesFailed.
+func measureCoverage(stdErr io.Writer, dir, pkgDir string) (coverage, error) {
+	dir = filepath.Clean(dir)
+	if pkgDir == "" {
+		pkgDir = filepath.Join(dir, "..", "..", "..")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "fuzzdump-cover-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	profile := filepath.Join(tmp, "cover.out")
+	fuzzName := filepath.Base(dir)
+	c := coverage{}
+	failed := 0
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		name := e.Name()
+		run := "^" + regexp.QuoteMeta(fuzzName) + "$/^" + regexp.QuoteMeta(name) + "$"
+		err := goTest(pkgDir, "-run="+run, "-covermode=set", "-coverprofile="+profile)
+		if err == nil {
+			c[name], err = readCoverProfile(profile)
+		}
+		if err != nil {
+			fmt.Fprintf(stdErr, "%s: %s: %v\n", cmdName, name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return c, fmt.Errorf("%w: %d of %d", errEntriesFailed, failed, len(c)+failed)
+	}
+	return c, nil
+}
+
+// goTest runs "go test" with args in dir.
+var goTest = func(dir string, args ...string) error {
+	cmd := exec.Command("go", append([]string{"test"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go test: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// readCoverProfile reads the blocks covered in the named cover profile.
+func readCoverProfile(name string) (coverSet, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseCoverProfile(f)
+}
+
+// parseCoverProfile parses the blocks covered in a cover profile, with
+// lines in the form of "file:start,end statements count" following the
+// "mode:" line.
+func parseCoverProfile(r io.Reader) (coverSet, error) {
+	s := coverSet{}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if line == 1 && strings.HasPrefix(text, "mode:") || text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: line %d: %q", errBadProfile, line, text)
+		}
+		if fields[2] != "0" {
+			s[fields[0]] = true
+		}
+	}
+	return s, sc.Err()
+}
+
+// writeCoverage writes the number of blocks each entry of c covers, and
+// how many of those none of the others do, followed by a summary.
+func writeCoverage(w io.Writer, c coverage) error {
+	names := sortedKeys(c)
+	contributing := 0
+	for _, name := range names {
+		u := c.unique(name)
+		if u > 0 {
+			contributing++
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%d blocks, %d unique\n", name, len(c[name]), u); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d of %d entries contribute unique coverage, %d blocks covered\n",
+		contributing, len(names), c.total())
+	return err
+}
+
+var (
+	errEntriesFailed = errors.New("fuzz test failed with some entries")
+	errBadProfile    = errors.New("malformed cover profile")
+)
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_coverageMain(t *testing.T) {
+	pkg := t.TempDir()
+	dir := filepath.Join(pkg, "testdata", "fuzz", "FuzzFoo")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+	var runs []string
+	fakeGoTest(t, func(dir string, args ...string) error {
+		require.Equal(t, filepath.Join(pkg, "testdata", "fuzz", "FuzzFoo", "..", "..", ".."), dir)
+		runs = append(runs, args[0])
+		return nil
+	}, map[string]string{
+		"a": "f.go:1.1,2.2 1 1\nf.go:3.1,4.2 1 1\nf.go:5.1,6.2 1 0\n",
+		"b": "f.go:1.1,2.2 1 1\nf.go:3.1,4.2 1 0\nf.go:5.1,6.2 1 1\n",
+		"c": "f.go:1.1,2.2 1 1\nf.go:3.1,4.2 1 0\nf.go:5.1,6.2 1 0\n",
+	})
+	stdOut := &bytes.Buffer{}
+	req := require.New(t)
+	req.NoError(coverageMain(stdOut, io.Discard, []string{dir}))
+	req.Equal([]string{
+		"-run=^FuzzFoo$/^a$", "-run=^FuzzFoo$/^b$", "-run=^FuzzFoo$/^c$",
+	}, runs)
+	req.Equal("a\t2 blocks, 1 unique\n"+
+		"b\t2 blocks, 1 unique\n"+
+		"c\t1 blocks, 0 unique\n"+
+		"2 of 3 entries contribute unique coverage, 3 blocks covered\n",
+		stdOut.String())
+}
+
+func Test_coverageMain_failed(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"a": "int(1)", "crash": "int(2)"})
+	fakeGoTest(t, func(dir string, args ...string) error {
+		if strings.Contains(args[0], "crash") {
+			return errSnap
+		}
+		return nil
+	}, map[string]string{"a": "mode: set\nf.go:1.1,2.2 1 1\n"})
+	stdOut, stdErr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := coverageMain(stdOut, stdErr, []string{"-pkg", t.TempDir(), dir})
+	req := require.New(t)
+	req.ErrorIs(err, errEntriesFailed)
+	req.EqualError(err, errEntriesFailed.Error()+": 1 of 2")
+	req.Equal("fuzzdump: crash: "+snap+"\n", stdErr.String())
+	req.Equal("a\t1 blocks, 1 unique\n"+
+		"1 of 1 entries contribute unique coverage, 1 blocks covered\n",
+		stdOut.String())
+}
+
+func Test_parseCoverProfile(t *testing.T) {
+	tests := map[string]struct {
+		profile string
+		want    coverSet
+		wErr    error
+	}{"empty": {
+		want: coverSet{},
+	}, "covered": {
+		profile: "mode: set\nf.go:1.1,2.2 1 1\nf.go:3.1,4.2 2 0\n",
+		want:    coverSet{"f.go:1.1,2.2": true},
+	}, "malformed": {
+		profile: "mode: set\nf.go:1.1,2.2 1\n",
+		wErr:    errBadProfile,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := parseCoverProfile(strings.NewReader(tt.profile))
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.want, got)
+		})
+	}
+}
+
+// fakeGoTest replaces goTest for the duration of t with one that calls
+// run and, if that succeeds, writes the cover profile given in profiles
+// for the entry that the -run argument names.
+func fakeGoTest(t *testing.T, run func(dir string, args ...string) error, profiles map[string]string) {
+	t.Helper()
+	orig := goTest
+	t.Cleanup(func() { goTest = orig })
+	goTest = func(dir string, args ...string) error {
+		if err := run(dir, args...); err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(args[0][strings.LastIndex(args[0], "^")+1:], "$")
+		profile := strings.TrimPrefix(args[len(args)-1], "-coverprofile=")
+		p, ok := profiles[name]
+		if !ok {
+			return errors.New("no profile for " + name)
+		}
+		return os.WriteFile(profile, []byte(p), 0o644)
+	}
+}
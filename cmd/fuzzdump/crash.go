@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// splitCrashers returns the names of the entries of the corpus directory
+// dir in fsys that look like the failing inputs that go test -fuzz
+// writes there, and those of the rest of them, the seeds.
+//
+// The failing inputs are told apart by being named by the hash of their
+// contents, as Go names them. Seeds added by hand usually are not,
+// though ones copied from the fuzzing cache are taken for crashers, too.
+// Files that cannot be read are taken for seeds, leaving reporting them
+// to the dump.
+func splitCrashers(fsys fs.FS, dir string) (crashers, seeds map[string]bool, err error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+	crashers, seeds = map[string]bool{}, map[string]bool{}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err == nil && e.Name() == corpus.FileName(b) {
+			crashers[e.Name()] = true
+		} else {
+			seeds[e.Name()] = true
+		}
+	}
+	return
+}
+
+// dumpCrashers dumps the corpus directory dir in fsys with dump, in two
+// sections, headed by a "// crashers" and a "// seeds" comment, for the
+// failing inputs written by go test -fuzz, and the rest of the entries,
+// respectively. A section is left out if it would be empty.
+//
+// The returned error, if any, is the one of the sections that warrants
+// the highest exit status code. If the directory cannot be read, or is
+// empty, it is dumped as a whole, for the dump to report that.
+func dumpCrashers(w io.Writer, fsys fs.FS, dir string, dump func(w io.Writer, fsys fs.FS) error) (err error) {
+	crashers, seeds, err := splitCrashers(fsys, dir)
+	if err != nil || len(crashers) == 0 && len(seeds) == 0 {
+		return dump(w, fsys)
+	}
+	first := true
+	for _, s := range []struct {
+		title string
+		names map[string]bool
+	}{{"crashers", crashers}, {"seeds", seeds}} {
+		if len(s.names) == 0 {
+			continue
+		}
+		out := &bytes.Buffer{}
+		e := dump(out, selectedFS{fsys, dir, s.names})
+		if wErr := writeSection(w, first, s.title, out); wErr != nil {
+			return wErr
+		}
+		first = false
+		if exitCodeFor(e) > exitCodeFor(err) {
+			err = e
+		}
+	}
+	return
+}
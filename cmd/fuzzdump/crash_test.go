@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dumpMain_crashers(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"seed": "int(1)"})
+	data := []byte("go test fuzz v1\nint(42)\n")
+	err := os.WriteFile(filepath.Join(dir, corpus.FileName(data)), data, 0o644)
+	require.NoError(t, err)
+	seedsOnly := writeCorpus(t, map[string]string{"seed": "int(1)"})
+
+	tests := map[string]struct {
+		args []string
+		want string
+		wErr error
+	}{"sections": {
+		args: []string{"-crashers", dir},
+		want: "// crashers\n{\n\tint(42),\n}\n\n// seeds\n{\n\tint(1),\n}\n",
+	}, "no crashers": {
+		args: []string{"-crashers", seedsOnly},
+		want: "// seeds\n{\n\tint(1),\n}\n",
+	}, "only crashers": {
+		args: []string{"-only-crashers", dir},
+		want: "{\n\tint(42),\n}\n",
+	}, "only crashers, none": {
+		args: []string{"-only-crashers", seedsOnly},
+		wErr: fuzzdump.ErrEmptyCorpus,
+	}, "absent dir": {
+		args: []string{"-crashers", "-allow-empty", filepath.Join(dir, "absent")},
+		want: "{\n}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut := &bytes.Buffer{}
+			err := dumpMain(stdOut, io.Discard, tt.args)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.want, stdOut.String())
+		})
+	}
+}
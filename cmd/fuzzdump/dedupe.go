@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpusdir"
+)
+
+// dedupeMain removes the entries of a fuzz test corpus directory that
+// hold the same values as another, or just lists them.
+func dedupeMain(w, _ io.Writer, args []string) (err error) {
+	fl := newFlagSet("dedupe")
+	dryRun := fl.Bool("n", false,
+		"list the duplicate entries, and the ones they duplicate, without removing any")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 1 || args[0] == "" {
+		return errNoDirArg
+	}
+	dir := args[0]
+	if *dryRun {
+		dups, err := fuzzdump.Duplicates(os.DirFS(dir), ".")
+		if exitCodeFor(err) >= fuzzdump.ExitHard {
+			return err
+		}
+		n := 0
+		for _, names := range dups {
+			for _, name := range names[1:] {
+				if _, wErr := fmt.Fprintf(w, "%s\tduplicates %s\n", name, names[0]); wErr != nil {
+					return wErr
+				}
+				n++
+			}
+		}
+		if _, wErr := fmt.Fprintf(w, "%d duplicate entries\n", n); wErr != nil {
+			return wErr
+		}
+		return err
+	}
+	unlock, err := lockCorpus(dir)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	removed, err := corpusdir.Dedupe(wfs, dir)
+	if exitCodeFor(err) >= fuzzdump.ExitHard && removed == 0 {
+		return err
+	}
+	if _, wErr := fmt.Fprintf(w, "removed %d duplicate entries\n", removed); wErr != nil {
+		return wErr
+	}
+	return err
+}
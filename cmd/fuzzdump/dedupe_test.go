@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dedupeMain(t *testing.T) {
+	values := map[string]string{
+		"a": "int(1)",
+		"b": "int(0x1)",
+		"c": "int(2)",
+		"d": "int(1)",
+	}
+	tests := map[string]struct {
+		args   []string
+		values map[string]string
+		wOut   string
+		wLeft  int
+		wErr   error
+	}{"remove": {
+		values: values,
+		wOut:   "removed 2 duplicate entries\n",
+		wLeft:  2,
+	}, "dry run": {
+		args:   []string{"-n"},
+		values: values,
+		wOut:   "b\tduplicates a\nd\tduplicates a\n2 duplicate entries\n",
+		wLeft:  4,
+	}, "invalid": {
+		values: map[string]string{"a": "int(1)", "b": "int(1)", "c": "int("},
+		wOut:   "removed 1 duplicate entries\n",
+		wLeft:  2,
+		wErr:   fuzzdump.ErrMalformedValue,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dir := writeCorpus(t, tt.values)
+			stdOut := &bytes.Buffer{}
+			err := dedupeMain(stdOut, io.Discard, append(tt.args, dir))
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, stdOut.String())
+			entries, err := os.ReadDir(dir)
+			req.NoError(err)
+			req.Len(entries, tt.wLeft)
+		})
+	}
+	t.Run("no dir", func(t *testing.T) {
+		require.ErrorIs(t, dedupeMain(io.Discard, io.Discard, nil), errNoDirArg)
+	})
+}
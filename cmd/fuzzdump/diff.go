@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// diffMain reports the entries that are only in one of two fuzz test
+// corpus directories, by their values, e.g., to review what a fuzzing
+// session has contributed before committing it.
+func diffMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("diff")
+	format := fl.String("format", diffText,
+		"output `format`: "+strings.Join(sortedKeys(diffWriters), ", "))
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return errDiffArgs
+	}
+	write, ok := diffWriters[*format]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadFormat, *format)
+	}
+	for _, dir := range args {
+		if _, err := os.Stat(dir); err != nil {
+			return err
+		}
+	}
+	added, removed, err := fuzzdump.Diff(os.DirFS(args[0]), os.DirFS(args[1]))
+	if exitCodeFor(err) >= fuzzdump.ExitHard {
+		return err
+	}
+	if wErr := write(w, added, removed); wErr != nil {
+		return wErr
+	}
+	return err
+}
+
+// Diff output formats.
+const (
+	diffText = "text"
+	diffJSON = "json"
+)
+
+// A diffWriter writes the entries added to and removed from a corpus to
+// w.
+type diffWriter func(w io.Writer, added, removed fuzzdump.Corpus) error
+
+var diffWriters = map[string]diffWriter{
+	diffText: writeTextDiff,
+	diffJSON: writeJSONDiff,
+}
+
+// writeTextDiff writes the removed entries to w, marked with "-", then
+// the added ones, marked with "+".
+func writeTextDiff(w io.Writer, added, removed fuzzdump.Corpus) error {
+	if err := writeDiff(w, "-", removed); err != nil {
+		return err
+	}
+	return writeDiff(w, "+", added)
+}
+
+// writeDiff writes each of the entries of c to w on a line of its own,
+// after the mark and the name of its file, with the values separated by
+// commas.
+func writeDiff(w io.Writer, mark string, c fuzzdump.Corpus) error {
+	for _, f := range c {
+		_, err := fmt.Fprintf(w, "%s %s\t%s\n",
+			mark, f.Name, bytes.Join(f.Entry, []byte(", ")))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONDiff writes the added and removed entries to w as a JSON
+// object of records with the names of their files, the hashes and the
+// typed values. The entries of files by the same name in both corpora
+// are listed as changed instead.
+func writeJSONDiff(w io.Writer, added, removed fuzzdump.Corpus) error {
+	d := jsonDiff{
+		Added:   []jsonDiffEntry{},
+		Removed: []jsonDiffEntry{},
+		Changed: []jsonDiffChange{},
+	}
+	was := make(map[string]corpus.File, len(removed))
+	for _, f := range removed {
+		was[f.Name] = f
+	}
+	for _, f := range added {
+		if old, ok := was[f.Name]; ok {
+			delete(was, f.Name)
+			d.Changed = append(d.Changed, jsonDiffChange{
+				Name: f.Name,
+				Old:  newJSONDiffEntry(old),
+				New:  newJSONDiffEntry(f),
+			})
+			continue
+		}
+		d.Added = append(d.Added, newJSONDiffEntry(f))
+	}
+	for _, f := range removed {
+		if _, ok := was[f.Name]; ok {
+			d.Removed = append(d.Removed, newJSONDiffEntry(f))
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(d)
+}
+
+// jsonDiff is the JSON output of diffMain.
+type jsonDiff struct {
+	Added   []jsonDiffEntry  `json:"added"`
+	Removed []jsonDiffEntry  `json:"removed"`
+	Changed []jsonDiffChange `json:"changed"`
+}
+
+// A jsonDiffEntry is an entry of a jsonDiff, with the hex SHA-256 hash
+// of its file.
+type jsonDiffEntry struct {
+	Name   string       `json:"name"`
+	Hash   string       `json:"hash"`
+	Values corpus.Entry `json:"values"`
+}
+
+func newJSONDiffEntry(f corpus.File) jsonDiffEntry {
+	return jsonDiffEntry{
+		Name:   f.Name,
+		Hash:   hex.EncodeToString(f.Hash[:]),
+		Values: f.Entry,
+	}
+}
+
+// A jsonDiffChange is a file of a jsonDiff whose entry differs between
+// the corpora.
+type jsonDiffChange struct {
+	Name string        `json:"name"`
+	Old  jsonDiffEntry `json:"old"`
+	New  jsonDiffEntry `json:"new"`
+}
+
+var errDiffArgs = errors.New("two corpus directory arguments required")
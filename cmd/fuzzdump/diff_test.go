@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_diffMain(t *testing.T) {
+	tests := map[string]struct {
+		a, b map[string]string
+		args []string
+		wOut string
+		wErr error
+	}{"nominal": {
+		a:    map[string]string{"1": "int(1)\nstring(\"a\")", "2": "int(2)\nstring(\"b\")"},
+		b:    map[string]string{"1": "int(0x1)\nstring(\"a\")", "3": "int(3)\nstring(\"c\")"},
+		wOut: "- 2\tint(2), string(\"b\")\n+ 3\tint(3), string(\"c\")\n",
+	}, "same": {
+		a: map[string]string{"1": "int(1)"},
+		b: map[string]string{"2": "int(1)"},
+	}, "invalid": {
+		a:    map[string]string{"1": "int(1)"},
+		b:    map[string]string{"1": "int(1)", "2": "int("},
+		wErr: fuzzdump.ErrMalformedValue,
+	}, "text": {
+		a:    map[string]string{"1": "int(1)"},
+		b:    map[string]string{"2": "int(2)"},
+		args: []string{"-format", "text"},
+		wOut: "- 1\tint(1)\n+ 2\tint(2)\n",
+	}, "json": {
+		a:    map[string]string{"1": "int(1)", "2": "int(2)"},
+		b:    map[string]string{"1": "int(5)", "3": "int(3)"},
+		args: []string{"-format", "json"},
+		wOut: `{
+	"added": [
+		{
+			"name": "3",
+			"hash": "` + fileHash("int(3)") + `",
+			"values": [
+				{
+					"type": "int",
+					"value": 3
+				}
+			]
+		}
+	],
+	"removed": [
+		{
+			"name": "2",
+			"hash": "` + fileHash("int(2)") + `",
+			"values": [
+				{
+					"type": "int",
+					"value": 2
+				}
+			]
+		}
+	],
+	"changed": [
+		{
+			"name": "1",
+			"old": {
+				"name": "1",
+				"hash": "` + fileHash("int(1)") + `",
+				"values": [
+					{
+						"type": "int",
+						"value": 1
+					}
+				]
+			},
+			"new": {
+				"name": "1",
+				"hash": "` + fileHash("int(5)") + `",
+				"values": [
+					{
+						"type": "int",
+						"value": 5
+					}
+				]
+			}
+		}
+	]
+}
+`,
+	}, "json same": {
+		a:    map[string]string{"1": "int(1)"},
+		b:    map[string]string{"1": "int(1)"},
+		args: []string{"-format", "json"},
+		wOut: "{\n\t\"added\": [],\n\t\"removed\": [],\n\t\"changed\": []\n}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			a, b := writeCorpus(t, tt.a), writeCorpus(t, tt.b)
+			stdOut := &bytes.Buffer{}
+			err := diffMain(stdOut, io.Discard, append(tt.args, a, b))
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, stdOut.String())
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		a := writeCorpus(t, map[string]string{"1": "int(1)"})
+		err := diffMain(io.Discard, io.Discard, []string{a, filepath.Join(a, "missing")})
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("args", func(t *testing.T) {
+		require.ErrorIs(t, diffMain(io.Discard, io.Discard, []string{"a"}), errDiffArgs)
+	})
+	t.Run("format", func(t *testing.T) {
+		a := writeCorpus(t, map[string]string{"1": "int(1)"})
+		err := diffMain(io.Discard, io.Discard, []string{"-format", "xml", a, a})
+		require.ErrorIs(t, err, errBadFormat)
+	})
+}
+
+// fileHash returns the hex SHA-256 hash of the entry file that
+// writeCorpus writes for the lines.
+func fileHash(lines string) string {
+	h := sha256.Sum256([]byte(corpus.Version1 + "\n" + lines + "\n"))
+	return hex.EncodeToString(h[:])
+}
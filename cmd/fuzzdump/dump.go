@@ -0,0 +1,417 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
+)
+
+// dumpMain dumps a fuzz test corpus directory.
+func dumpMain(w, stdErr io.Writer, args []string) (err error) {
+	fl := newFlagSet("")
+	var (
+		canonical = fl.Bool("canonical", false,
+			"normalize values and sort entries by their contents")
+		normalize = fl.Bool("normalize", false,
+			"render values anew from their decoded form, keeping the entry order")
+		argLabels = fl.Bool("arg-labels", false,
+			"prefix values with comments stating their argument index")
+		allowEmpty = fl.Bool("allow-empty", false,
+			"dump an empty or missing corpus directory as empty braces")
+		atomic = fl.Bool("atomic", false,
+			"write nothing unless the whole corpus could be dumped")
+		strict = fl.Bool("strict", false,
+			"write nothing if any corpus entry is invalid")
+		assumeV1 = fl.Bool("assume-v1", false,
+			"dump entry files lacking a version header if they hold only values")
+		recoverEntries = fl.Bool("recover", false,
+			"dump the valid leading values of entries followed by garbage, reporting where")
+		frame = fl.String("frame", "",
+			"write each entry as a separate record framed by `kind`: "+
+				strings.Join(sortedKeys(framings), " or "))
+		outFormat = fl.String("format", formatDump,
+			"output `format`: "+strings.Join(sortedKeys(dumpFormats), ", "))
+		jsonOut = fl.Bool("json", false,
+			"dump the entries as a JSON array of arrays of typed values (-format json)")
+		preview = fl.Int("preview", 0,
+			"dump just the first and last `n` entries, noting how many are omitted")
+		sortBy = fl.String("sort", "name",
+			"dump the entries in the order of their `key`: "+strings.Join(sortedKeys(sortOrders), ", "))
+		descending = fl.Bool("desc", false,
+			"dump the entries in the descending order of the -sort key")
+		bytesFormat = fl.String("bytes", "raw",
+			"render []byte values in the `format`: "+strings.Join(sortedKeys(bytesFormats), ", "))
+		limit = fl.Int("limit", 0,
+			"dump at most `n` entries, noting how many more are omitted")
+		offset = fl.Int("offset", 0,
+			"leave out the first `n` entries, noting how many are omitted")
+		goFunc = fl.String("func", "",
+			"with -format goadd, write a Go source file with a function `name`d so making the calls")
+		goPkg = fl.String("pkg", os.Getenv("GOPACKAGE"),
+			"package `name` of the file that -func writes (default $GOPACKAGE)")
+		receiver = fl.String("receiver", "f",
+			"with -format goadd, call the Add method of the variable `name`d so")
+		hashSalt = fl.String("hash-values", "",
+			"replace string and []byte values with hashes salted with `salt`")
+		tags = fl.String("tags", "",
+			"dump just the entries that have any of the comma-separated `tags`")
+		crashers = fl.Bool("crashers", false,
+			"dump the failing inputs written by go test -fuzz in a section of their own")
+		onlyCrashers = fl.Bool("only-crashers", false,
+			"dump just the failing inputs written by go test -fuzz")
+		respectLock = fl.Bool("respect-lock", false,
+			"fail if a corpus is locked by another fuzzdump process mutating it")
+		sizeNotes = fl.Bool("sizes", false,
+			"note the number of arguments and decoded size of each entry in comments")
+		fileNames = fl.Bool("names", false,
+			"note the file name of each entry in a comment")
+		showProvenance = fl.Bool("provenance", false,
+			"note where imported entries came from in comments")
+		summaryOnly = fl.Bool("summary-only", false,
+			"validate the corpus and print just a summary of it, without any values")
+		memCap = fl.Int64("memory-cap", 0,
+			"fail if the entries to hold in memory at once take more than `bytes` (a hard cap)")
+		fromCache = fl.Bool("cache", false,
+			"dump the Go fuzz cache corpora of the fuzz targets given as arguments")
+		recursive = fl.Bool("r", false,
+			"dump every fuzz test corpus directory in the trees under the directories")
+		jobs = fl.Int("j", runtime.GOMAXPROCS(0),
+			"dump up to `n` directories concurrently")
+		readJobs = fl.Int("read-jobs", 0,
+			"read and parse up to `n` files of each directory concurrently (default GOMAXPROCS)")
+		output = fl.String("o", "",
+			"write output to `file` instead of the standard output")
+		generate = fl.Bool("generate", false,
+			"mark the output file as generated code (requires -o)")
+		benchmark = fl.Bool("bench", false,
+			"report the throughput of the dump to the standard error")
+		cpuProfile = fl.String("cpuprofile", "",
+			"write a CPU profile to `file`")
+		memProfile = fl.String("memprofile", "",
+			"write a memory profile to `file`")
+		profileN = fl.Int("profile-files", 0,
+			"report the `n` slowest and largest entry files to the standard error")
+	)
+	var min, max argBounds
+	fl.Var(&min, "min", "skip entries with argN less than `argN=value` (repeatable)")
+	fl.Var(&max, "max", "skip entries with argN greater than `argN=value` (repeatable)")
+	var minLen, maxLen lenBounds
+	fl.Var(&minLen, "min-len",
+		"skip entries with strings or []byte shorter than `[argN=]length` (repeatable)")
+	fl.Var(&maxLen, "max-len",
+		"skip entries with strings or []byte longer than `[argN=]length` (repeatable)")
+	filter := fl.String("filter", "",
+		"dump just the entries that the expression `expr` holds for, e.g. 'arg[0] > 5 && len < 100'")
+	var find hexBytes
+	fl.Var(&find, "find-hex",
+		"dump just the entries with strings or []byte containing the `bytes` in hex")
+	var versions stringList
+	fl.Var(&versions, "accept-version",
+		"also accept entry files with the version `header` (repeatable)")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) == 0 {
+		return errNoDirArg
+	}
+	for _, a := range args {
+		if a == "" {
+			return errNoDirArg
+		}
+	}
+	if *fromCache {
+		if args, err = cacheCorpusDirs(args); err != nil {
+			return
+		}
+	}
+	if *recursive {
+		if args, err = findCorpora(args); err != nil {
+			return
+		}
+	}
+	if *generate && *output == "" {
+		return errGenerateNoOutput
+	}
+	if *jsonOut {
+		*outFormat = formatJSON
+	} else if !isFlagSet(fl, "format") {
+		if f, ok := outputFormat(*output); ok {
+			*outFormat = f
+		}
+	}
+	dumpFormat, ok := dumpFormats[*outFormat]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadFormat, *outFormat)
+	}
+	if *goFunc != "" {
+		if *outFormat != formatGoAdd {
+			return errFuncFormat
+		}
+		if len(args) != 1 || *recursive {
+			return errFuncDirs
+		}
+		dumpFormat = goAddFunc(goAddFile{Package: *goPkg, Func: *goFunc, Receiver: *receiver}, dumpFormat)
+	}
+	stop, err := startProfiling(*cpuProfile, *memProfile)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if e := stop(); err == nil {
+			err = e
+		}
+	}()
+	order, ok := sortOrders[*sortBy]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadSort, *sortBy)
+	}
+	bf, ok := bytesFormats[*bytesFormat]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadBytes, *bytesFormat)
+	}
+	var opts []fuzzdump.Option
+	if order != fuzzdump.SortByName || *descending {
+		opts = append(opts, fuzzdump.WithSort(order, *descending))
+	}
+	if bf != format.BytesRaw {
+		opts = append(opts, fuzzdump.WithBytesFormat(bf))
+	}
+	if *outFormat == formatGoAdd {
+		opts = append(opts, fuzzdump.WithReceiver(*receiver))
+	}
+	if *canonical {
+		opts = append(opts, fuzzdump.WithCanonical())
+	}
+	if *normalize {
+		opts = append(opts, fuzzdump.WithNormalizedValues())
+	}
+	if *argLabels {
+		opts = append(opts, fuzzdump.WithArgLabels())
+	}
+	if *allowEmpty {
+		opts = append(opts, fuzzdump.WithAllowEmpty())
+	}
+	if *atomic {
+		opts = append(opts, fuzzdump.WithAtomicOutput())
+	}
+	if *strict {
+		opts = append(opts, fuzzdump.WithStrict())
+	}
+	if len(versions) > 0 {
+		opts = append(opts, fuzzdump.WithAcceptVersions(
+			append([]string{corpus.Version1}, versions...)...))
+	}
+	if *readJobs > 0 {
+		opts = append(opts, fuzzdump.WithConcurrency(*readJobs))
+	}
+	if *memCap > 0 {
+		opts = append(opts, fuzzdump.WithMemoryCap(*memCap))
+	}
+	if *fileNames {
+		opts = append(opts, fuzzdump.WithFileNames())
+	}
+	if *sizeNotes {
+		opts = append(opts, fuzzdump.WithSizeNotes())
+	}
+	if *frame != "" {
+		f, ok := framings[*frame]
+		if !ok {
+			return fmt.Errorf("%w: %q", errBadFraming, *frame)
+		}
+		opts = append(opts, fuzzdump.WithFraming(f))
+	}
+	if *limit > 0 {
+		opts = append(opts, fuzzdump.WithLimit(*limit))
+	}
+	if *offset > 0 {
+		opts = append(opts, fuzzdump.WithOffset(*offset))
+	}
+	if *preview > 0 {
+		opts = append(opts, fuzzdump.WithPreview(*preview))
+	}
+	if *hashSalt != "" {
+		opts = append(opts, fuzzdump.WithHashedValues([]byte(*hashSalt)))
+	}
+	if *assumeV1 {
+		opts = append(opts, fuzzdump.WithAssumeVersion1())
+	}
+	if *recoverEntries {
+		opts = append(opts, fuzzdump.WithRecovery())
+	}
+	if len(find) > 0 {
+		opts = append(opts, fuzzdump.WithFind(find))
+	}
+	if *filter != "" {
+		keep, err := fuzzdump.ParseFilter(*filter)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, fuzzdump.WithFilter(keep))
+	}
+	for _, v := range min {
+		opts = append(opts, fuzzdump.WithMin(v.arg, v.value))
+	}
+	for _, v := range max {
+		opts = append(opts, fuzzdump.WithMax(v.arg, v.value))
+	}
+	for _, v := range minLen {
+		opts = append(opts, fuzzdump.WithMinLen(v.len, v.args...))
+	}
+	for _, v := range maxLen {
+		opts = append(opts, fuzzdump.WithMaxLen(v.len, v.args...))
+	}
+	dump := func(w io.Writer) error {
+		if *generate {
+			if _, err := io.WriteString(w, generatedHeader); err != nil {
+				return err
+			}
+		}
+		wrapFS := func(fsys fs.FS) fs.FS { return fsys }
+		if *benchmark {
+			b := newBench(w)
+			defer b.report(stdErr)
+			w, wrapFS = b, b.FS
+		}
+		dumpDir := func(w io.Writer, dir string) error {
+			if *respectLock {
+				if err := checkUnlocked(dir); err != nil {
+					return err
+				}
+			}
+			fsys, name, err := corpusFS(dir)
+			if err != nil {
+				return err
+			}
+			opts := opts
+			if *showProvenance {
+				p, err := readProvenances(dir)
+				if err != nil {
+					return err
+				}
+				opts = append(opts[:len(opts):len(opts)], fuzzdump.WithEntryComments(p.comment()))
+			}
+			if *tags != "" {
+				if fsys, err = newTaggedFS(fsys, name, dir, strings.Split(*tags, ",")); err != nil {
+					return err
+				}
+			}
+			if *onlyCrashers {
+				// Left for the dump to report, if the directory cannot be read.
+				if crashers, _, err := splitCrashers(fsys, name); err == nil {
+					fsys = selectedFS{fsys, name, crashers}
+				}
+			}
+			dump := func(w io.Writer, fsys fs.FS) error {
+				if *summaryOnly {
+					return summarizeDir(w, wrapFS(fsys), name, *allowEmpty)
+				}
+				return dumpFormat(w, wrapFS(fsys), name, opts...)
+			}
+			if *crashers && !*onlyCrashers {
+				err = dumpCrashers(w, fsys, name, dump)
+			} else {
+				err = dump(w, fsys)
+			}
+			if *summaryOnly {
+				return err
+			}
+			if *profileN < 1 {
+				return err
+			}
+			profiles, pErr := profileFiles(fsys, name)
+			if pErr == nil {
+				pErr = reportProfiles(stdErr, dir, profiles, *profileN)
+			}
+			if err != nil {
+				return err
+			}
+			return pErr
+		}
+		if len(args) == 1 && !*recursive {
+			return dumpDir(w, args[0])
+		}
+		return dumpDirs(w, stdErr, args, *jobs, dumpDir)
+	}
+	if *output == "" {
+		return dump(w)
+	}
+	return writeFile(*output, dump)
+}
+
+// Dump output formats, besides those of convert.
+const (
+	formatDump    = "dump"
+	formatGoAdd   = "goadd"
+	formatColumns = "columns"
+)
+
+// dumpFormats are the functions that dump a corpus by the name of the
+// format of their output.
+var dumpFormats = map[string]func(w io.Writer, fsys fs.FS, dir string, opts ...fuzzdump.Option) error{
+	formatDump:    fuzzdump.DumpDir,
+	formatJSON:    fuzzdump.DumpDirJSON,
+	formatNDJSON:  fuzzdump.DumpDirNDJSON,
+	formatGoAdd:   fuzzdump.DumpDirGoAdd,
+	formatColumns: fuzzdump.DumpDirColumns,
+}
+
+// outputFormats are the dump formats by the output file name extension
+// that stands for them.
+var outputFormats = map[string]string{
+	".go":     formatGoAdd,
+	".json":   formatJSON,
+	".ndjson": formatNDJSON,
+	".txt":    formatDump,
+}
+
+// outputFormat returns the dump format that the extension of the output
+// file name stands for, if any.
+func outputFormat(name string) (string, bool) {
+	f, ok := outputFormats[strings.ToLower(filepath.Ext(name))]
+	return f, ok
+}
+
+// sortOrders by the name of the key they sort the entries by.
+var sortOrders = map[string]fuzzdump.SortOrder{
+	"name":     fuzzdump.SortByName,
+	"contents": fuzzdump.SortByContents,
+	"size":     fuzzdump.SortBySize,
+	"mtime":    fuzzdump.SortByModTime,
+	"hash":     fuzzdump.SortByHash,
+}
+
+// bytesFormats by the name of the format they render []byte values in.
+var bytesFormats = map[string]format.BytesFormat{
+	"raw":     format.BytesRaw,
+	"hex":     format.BytesHex,
+	"dump":    format.BytesHexDump,
+	"b64":     format.BytesBase64,
+	"summary": format.BytesSummary,
+}
+
+// framings by the name of their kind.
+var framings = map[string]format.Framing{
+	"length": format.LengthPrefixed,
+	"rs":     format.RecordSeparated,
+}
+
+// generatedHeader marks the output as generated, as recognized by Go
+// tooling.
+const generatedHeader = "// Code generated by fuzzdump; DO NOT EDIT.\n\n"
+
+var (
+	errGenerateNoOutput = errors.New("-generate requires an output file (-o)")
+	errBadFraming       = errors.New("unsupported framing")
+	errBadSort          = errors.New("unsupported sort key")
+	errBadBytes         = errors.New("unsupported []byte format")
+)
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dumpMain(t *testing.T) {
+	stdOut := &bytes.Buffer{}
+
+	tests := map[string]struct {
+		args     []string
+		wOut     string
+		wErr     error
+		wErrText string
+	}{"dir not given": {
+		wErr: errNoDirArg,
+	}, "bad flag": {
+		args:     []string{"-foo"},
+		wErrText: "flag provided but not defined: -foo",
+	}, "help": {
+		args: []string{"-h"},
+		wOut: "  -arg-labels\n",
+	}, "canonical": {
+		args: []string{"-canonical", corpusDir(t)},
+		wOut: "{\n\tint(3),\n\tint(5),\n}\n",
+	}, "range": {
+		args: []string{"-min", "arg0=4", "-max", "arg0=6", corpusDir(t)},
+		wOut: "{\n\tint(0x5),\n}\n",
+	}, "bad bound": {
+		args:     []string{"-min", "arg0", corpusDir(t)},
+		wErrText: `invalid value "arg0" for flag -min: ` + errBadArgBound.Error(),
+	}, "length": {
+		args: []string{"-max-len", "arg0=2", stringCorpusDir(t)},
+		wOut: "{\n\tstring(\"ab\"),\n}\n",
+	}, "filter": {
+		args: []string{"-filter", `arg[0].type == "string" && len > 2`, stringCorpusDir(t)},
+		wOut: "{\n\tstring(\"abc\"),\n}\n",
+	}, "bad filter": {
+		args: []string{"-filter", "len", stringCorpusDir(t)},
+		wErr: fuzzdump.ErrBadFilter,
+	}, "limit and offset": {
+		args: []string{"-canonical", "-offset", "1", "-limit", "1", corpusDir(t)},
+		wOut: "{\n\t// ... 1 entry omitted\n\tint(5),\n}\n",
+	}, "goadd limit": {
+		args: []string{"-format", "goadd", "-limit", "1", corpusDir(t)},
+		wOut: "f.Add(int(5))\n// ... 1 entry omitted\n",
+	}, "sort": {
+		args: []string{"-sort", "contents", "-desc", corpusDir(t)},
+		wOut: "{\n\tint(3),\n\tint(0x5),\n}\n",
+	}, "sort descending by name": {
+		args: []string{"-desc", corpusDir(t)},
+		wOut: "{\n\tint(3),\n\tint(0x5),\n}\n",
+	}, "bad sort": {
+		args: []string{"-sort", "age", corpusDir(t)},
+		wErr: errBadSort,
+	}, "bytes": {
+		args: []string{"-bytes", "hex", writeCorpus(t, map[string]string{"1": `[]byte("ab")`})},
+		wOut: "{\n\t[]byte{0x61, 0x62},\n}\n",
+	}, "bad bytes": {
+		args: []string{"-bytes", "octal", corpusDir(t)},
+		wErr: errBadBytes,
+	}, "find hex": {
+		args: []string{"-find-hex", "6263", stringCorpusDir(t)},
+		wOut: "{\n\t// found in arg0 at 1\n\tstring(\"abc\"),\n}\n",
+	}, "bad hex": {
+		args: []string{"-find-hex", "6", stringCorpusDir(t)},
+		wErrText: `invalid value "6" for flag -find-hex: ` + errBadHex.Error() +
+			": encoding/hex: odd length hex string",
+	}, "names": {
+		args: []string{"-names", "-max-len", "arg0=2", stringCorpusDir(t)},
+		wOut: "{\n\t// 1\n\tstring(\"ab\"),\n}\n",
+	}, "sizes": {
+		args: []string{"-sizes", "-canonical", corpusDir(t)},
+		wOut: "{\n\t// 1 arg, 8 B\n\tint(3),\n\t// 1 arg, 8 B\n\tint(5),\n}\n",
+	}, "json": {
+		args: []string{"-json", "-canonical", corpusDir(t)},
+		wOut: "[\n[{\"type\":\"int\",\"value\":3}],\n[{\"type\":\"int\",\"value\":5}]\n]\n",
+	}, "goadd": {
+		args: []string{"-format", "goadd", "-canonical", corpusDir(t)},
+		wOut: "f.Add(int(3))\nf.Add(int(5))\n",
+	}, "columns": {
+		args: []string{"-format", "columns", "-canonical", corpusDir(t)},
+		wOut: "int(3)\nint(5)\n",
+	}, "bad format": {
+		args: []string{"-format", "yaml", corpusDir(t)},
+		wErr: errBadFormat,
+	}, "memory cap": {
+		args: []string{"-canonical", "-memory-cap", "10", corpusDir(t)},
+		wErr: fuzzdump.ErrMemoryCap,
+	}, "allow empty": {
+		args: []string{"-allow-empty", filepath.Join(t.TempDir(), "absent")},
+		wOut: "{\n}\n",
+	}, "atomic": {
+		args: []string{"-atomic", corpusDir(t)},
+		wOut: "{\n\tint(0x5),\n\tint(3),\n}\n",
+	}, "read jobs": {
+		args: []string{"-read-jobs", "1", corpusDir(t)},
+		wOut: "{\n\tint(0x5),\n\tint(3),\n}\n",
+	}, "multiple dirs": {
+		args: []string{"-j", "2", corpusDir(t), stringCorpusDir(t)},
+		wOut: "{\n\tint(0x5),\n\tint(3),\n}\n\n// ",
+	}, "empty dir in multiple": {
+		args: []string{corpusDir(t), ""},
+		wErr: errNoDirArg,
+	}, "generate without output": {
+		args: []string{"-generate", corpusDir(t)},
+		wErr: errGenerateNoOutput,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut.Reset()
+			err := dumpMain(stdOut, io.Discard, tt.args)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			if tt.wErrText != "" {
+				req.EqualError(err, tt.wErrText)
+				return
+			}
+			req.NoError(err)
+			req.Contains(stdOut.String(), tt.wOut)
+		})
+	}
+}
+
+func Test_dumpMain_bench(t *testing.T) {
+	stdOut, stdErr := &bytes.Buffer{}, &bytes.Buffer{}
+	req := require.New(t)
+	req.NoError(dumpMain(stdOut, stdErr, []string{"-bench", corpusDir(t)}))
+	req.Equal("{\n\tint(0x5),\n\tint(3),\n}\n", stdOut.String())
+	req.Contains(stdErr.String(), "fuzzdump: bench: 2 files in ")
+}
+
+func Test_dumpMain_strict(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{
+		"1": "int(1)",
+		"2": "int(",
+	})
+	stdOut := &bytes.Buffer{}
+	err := dumpMain(stdOut, io.Discard, []string{"-strict", dir})
+	req := require.New(t)
+	req.ErrorIs(err, fuzzdump.ErrMalformedValue)
+	req.Equal(fuzzdump.ExitSoft, exitCodeFor(err))
+	req.Empty(stdOut.String())
+}
+
+func Test_dumpMain_profiles(t *testing.T) {
+	dir := t.TempDir()
+	mem := filepath.Join(dir, "mem.pprof")
+	args := []string{"-memprofile", mem, corpusDir(t)}
+	req := require.New(t)
+	req.NoError(dumpMain(io.Discard, io.Discard, args))
+	req.FileExists(mem)
+
+	args = []string{"-cpuprofile", filepath.Join(dir, "absent", "cpu"), "."}
+	req.ErrorIs(dumpMain(io.Discard, io.Discard, args), os.ErrNotExist)
+}
+
+func Test_dumpMain_generate(t *testing.T) {
+	stdOut := &bytes.Buffer{}
+	name := filepath.Join(t.TempDir(), "dump.txt")
+	args := []string{"-generate", "-o", name, corpusDir(t)}
+
+	req := require.New(t)
+	req.NoError(dumpMain(stdOut, io.Discard, args))
+	req.Empty(stdOut.String())
+	b, err := os.ReadFile(name)
+	req.NoError(err)
+	req.Equal(generatedHeader+"{\n\tint(0x5),\n\tint(3),\n}\n", string(b))
+}
+
+func Test_dumpMain_outputFormat(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		args []string
+		want string
+	}{"json": {
+		name: "dump.JSON",
+		want: "[\n[{\"type\":\"int\",\"value\":3}],\n[{\"type\":\"int\",\"value\":5}]\n]\n",
+	}, "text": {
+		name: "dump.txt",
+		want: "{\n\tint(3),\n\tint(5),\n}\n",
+	}, "ndjson": {
+		name: "dump.ndjson",
+		want: "[{\"type\":\"int\",\"value\":3}]\n[{\"type\":\"int\",\"value\":5}]\n",
+	}, "go": {
+		name: "seeds_test.go",
+		want: "f.Add(int(3))\nf.Add(int(5))\n",
+	}, "none": {
+		name: "dump",
+		want: "{\n\tint(3),\n\tint(5),\n}\n",
+	}, "golden": {
+		name: "x.golden",
+		want: "{\n\tint(3),\n\tint(5),\n}\n",
+	}, "unknown": {
+		name: "dump.csv",
+		want: "{\n\tint(3),\n\tint(5),\n}\n",
+	}, "explicit": {
+		name: "dump.json",
+		args: []string{"-format", "columns"},
+		want: "int(3)\nint(5)\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), tt.name)
+			args := append(tt.args, "-canonical", "-o", name, corpusDir(t))
+			req := require.New(t)
+			req.NoError(dumpMain(io.Discard, io.Discard, args))
+			b, err := os.ReadFile(name)
+			req.NoError(err)
+			req.Equal(tt.want, string(b))
+		})
+	}
+}
+
+// corpusDir creates a temporary corpus directory with entries that
+// are neither sorted by name nor normalized.
+func corpusDir(t *testing.T) string {
+	t.Helper()
+	return writeCorpus(t, map[string]string{
+		"1": "int(0x5)",
+		"2": "int(3)",
+	})
+}
+
+// stringCorpusDir creates a temporary corpus directory with string
+// entries of different lengths.
+func stringCorpusDir(t *testing.T) string {
+	t.Helper()
+	return writeCorpus(t, map[string]string{
+		"1": `string("ab")`,
+		"2": `string("abc")`,
+	})
+}
+
+func Test_dumpMain_acceptVersion(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"1": "acme fuzz v1\nint(1)\n",
+		"2": "go test fuzz v1\nint(2)\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+	stdOut := &bytes.Buffer{}
+	err := dumpMain(stdOut, io.Discard, []string{"-accept-version", "acme fuzz v1", dir})
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal("{\n\tint(1),\n\tint(2),\n}\n", stdOut.String())
+}
+
+func Test_dumpMain_assumeV1(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1"), []byte("int(1)\n"), 0o644))
+	stdOut := &bytes.Buffer{}
+	err := dumpMain(stdOut, io.Discard, []string{"-assume-v1", dir})
+	req := require.New(t)
+	req.ErrorIs(err, fuzzdump.ErrMissingVersion)
+	req.Equal(fuzzdump.ExitSoft, exitCodeFor(err))
+	req.Equal("{\n\tint(1),\n}\n", stdOut.String())
+}
+
+func Test_dumpMain_frame(t *testing.T) {
+	tests := map[string]struct {
+		frame string
+		wOut  string
+		wErr  error
+	}{"length": {
+		frame: "length",
+		wOut:  "15\n{\n\tint(0x5),\n}\n13\n{\n\tint(3),\n}\n",
+	}, "rs": {
+		frame: "rs",
+		wOut:  "\x1e{\n\tint(0x5),\n}\n\x1e{\n\tint(3),\n}\n",
+	}, "bad": {
+		frame: "xml",
+		wErr:  errBadFraming,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut := &bytes.Buffer{}
+			err := dumpMain(stdOut, io.Discard, []string{"-frame", tt.frame, corpusDir(t)})
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.wOut, stdOut.String())
+		})
+	}
+}
+
+func Test_dumpMain_preview(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"1": "int(1)", "2": "int(2)", "3": "int(3)"})
+	stdOut := &bytes.Buffer{}
+	err := dumpMain(stdOut, io.Discard, []string{"-preview", "1", dir})
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal("{\n\tint(1),\n\t// ... 1 entry omitted\n\tint(3),\n}\n", stdOut.String())
+}
+
+func Test_dumpMain_recursive(t *testing.T) {
+	root := t.TempDir()
+	for dir, value := range map[string]string{
+		filepath.Join("a", "FuzzA"): "int(1)",
+		filepath.Join("b", "FuzzB"): "int(2)",
+	} {
+		dir = filepath.Join(root, dir)
+		req := require.New(t)
+		req.NoError(os.MkdirAll(dir, 0o755))
+		data := []byte("go test fuzz v1\n" + value + "\n")
+		req.NoError(os.WriteFile(filepath.Join(dir, "1"), data, 0o644))
+	}
+	stdOut := &bytes.Buffer{}
+	req := require.New(t)
+	req.NoError(dumpMain(stdOut, io.Discard, []string{"-r", root}))
+	req.Equal("// "+filepath.Join(root, "a", "FuzzA")+"\n{\n\tint(1),\n}\n"+
+		"\n// "+filepath.Join(root, "b", "FuzzB")+"\n{\n\tint(2),\n}\n",
+		stdOut.String())
+
+	err := dumpMain(io.Discard, io.Discard, []string{"-r", t.TempDir()})
+	req.ErrorIs(err, fuzzdump.ErrNoCorpora)
+}
+
+func Test_dumpMain_recover(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{
+		"1": "int(1)",
+		"2": "int(2)\n\x00garbage",
+	})
+	stdOut := &bytes.Buffer{}
+	err := dumpMain(stdOut, io.Discard, []string{"-recover", dir})
+	req := require.New(t)
+	req.ErrorIs(err, fuzzdump.ErrTrailingGarbage)
+	req.Equal(fuzzdump.ExitSoft, exitCodeFor(err))
+	req.Equal("{\n\tint(1),\n\tint(2),\n}\n", stdOut.String())
+}
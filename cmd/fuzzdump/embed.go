@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// embedMain generates a Go source file that embeds a fuzz test corpus
+// directory and provides a helper to add its entries as seeds.
+func embedMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("embed")
+	var (
+		pkg = fl.String("pkg", os.Getenv("GOPACKAGE"),
+			"package `name` of the generated file (default $GOPACKAGE)")
+		name = fl.String("name", "",
+			"`base` of the generated identifiers (default the directory name)")
+		output = fl.String("o", "",
+			"write output to `file` instead of the standard output")
+	)
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) == 0 || len(args[0]) == 0 {
+		return errNoDirArg
+	}
+	dir := filepath.ToSlash(filepath.Clean(args[0]))
+	if *name == "" {
+		*name = path.Base(dir)
+	}
+	gen := func(w io.Writer) error {
+		return writeEmbed(w, embedData{Package: *pkg, Dir: dir, Name: *name})
+	}
+	if *output == "" {
+		return gen(w)
+	}
+	return writeFile(*output, gen)
+}
+
+// embedData is what the generated embedding code is made of.
+type embedData struct {
+	// Package name of the generated file.
+	Package string
+	// Dir is the path of the corpus directory relative to the package.
+	Dir string
+	// Name is the base of the generated identifiers.
+	Name string
+}
+
+// writeEmbed writes the Go source embedding the corpus described by d
+// to w.
+func writeEmbed(w io.Writer, d embedData) error {
+	if !token.IsIdentifier(d.Package) {
+		return fmt.Errorf("%w: %q", errBadPackage, d.Package)
+	}
+	if !fs.ValidPath(d.Dir) || d.Dir == "." {
+		return fmt.Errorf("%w: %q", errBadEmbedDir, d.Dir)
+	}
+	d.Name = exported(d.Name)
+	if !token.IsIdentifier(d.Name) {
+		return fmt.Errorf("%w: %q", errBadName, d.Name)
+	}
+	b := &bytes.Buffer{}
+	if err := embedTmpl.Execute(b, d); err != nil {
+		return err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// exported returns s with its first letter in upper case.
+func exported(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+// unexported returns s with its first letter in lower case.
+func unexported(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+var embedTmpl = template.Must(template.New("embed").Funcs(template.FuncMap{
+	"unexported": unexported,
+	"quote":      func(s string) string { return fmt.Sprintf("%q", s) },
+}).Parse(strings.TrimSpace(generatedHeader) + `
+
+package {{.Package}}
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+//go:embed {{.Dir}}
+var {{unexported .Name}}CorpusFS embed.FS
+
+// {{.Name}}Corpus is the fuzz test corpus embedded from {{.Dir}}.
+var {{.Name}}Corpus fs.FS = {{unexported .Name}}CorpusFS
+
+// {{.Name}}CorpusDir is the path of the corpus directory in {{.Name}}Corpus.
+const {{.Name}}CorpusDir = {{quote .Dir}}
+
+// Add{{.Name}}Seeds adds the entries of {{.Name}}Corpus to f, e.g., a
+// *testing.F.
+func Add{{.Name}}Seeds(f fuzzdump.Seeder) error {
+	return fuzzdump.AddSeeds(f, {{.Name}}Corpus, {{.Name}}CorpusDir)
+}
+`))
+
+var (
+	errBadPackage  = errors.New("invalid package name")
+	errBadEmbedDir = errors.New("corpus directory must be a path within the package")
+	errBadName     = errors.New("invalid identifier base name")
+)
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_embedMain(t *testing.T) {
+	out := &bytes.Buffer{}
+	tests := map[string]struct {
+		args     []string
+		env      string
+		wOut     string
+		wErr     error
+		wErrText string
+	}{"dir not given": {
+		wErr: errNoDirArg,
+	}, "bad flag": {
+		args:     []string{"-foo"},
+		wErrText: "flag provided but not defined: -foo",
+	}, "help": {
+		args: []string{"-h"},
+		wOut: "  -pkg name\n",
+	}, "no package": {
+		args: []string{"testdata/fuzz/FuzzFoo"},
+		wErr: errBadPackage,
+	}, "package from env": {
+		args: []string{"testdata/fuzz/FuzzFoo"},
+		env:  "foo",
+		wOut: "package foo\n",
+	}, "name": {
+		args: []string{"-pkg", "foo", "-name", "bar", "testdata/fuzz/FuzzFoo"},
+		wOut: "func AddBarSeeds(",
+	}, "outside package": {
+		args: []string{"-pkg", "foo", "../fuzz/FuzzFoo"},
+		wErr: errBadEmbedDir,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			t.Setenv("GOPACKAGE", tt.env)
+			out.Reset()
+			err := embedMain(out, io.Discard, tt.args)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			if tt.wErrText != "" {
+				req.EqualError(err, tt.wErrText)
+				return
+			}
+			req.NoError(err)
+			req.Contains(out.String(), tt.wOut)
+		})
+	}
+	t.Run("output file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "corpus_gen.go")
+		args := []string{"-pkg", "foo", "-o", name, "testdata/fuzz/FuzzFoo"}
+		out.Reset()
+		req := require.New(t)
+		req.NoError(embedMain(out, io.Discard, args))
+		req.Empty(out.String())
+		b, err := os.ReadFile(name)
+		req.NoError(err)
+		req.Contains(string(b), "//go:embed testdata/fuzz/FuzzFoo\n")
+	})
+}
+
+func Test_writeEmbed(t *testing.T) {
+	const want = generatedHeader + `package foo
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+//go:embed testdata/fuzz/FuzzFoo
+var fuzzFooCorpusFS embed.FS
+
+// FuzzFooCorpus is the fuzz test corpus embedded from testdata/fuzz/FuzzFoo.
+var FuzzFooCorpus fs.FS = fuzzFooCorpusFS
+
+// FuzzFooCorpusDir is the path of the corpus directory in FuzzFooCorpus.
+const FuzzFooCorpusDir = "testdata/fuzz/FuzzFoo"
+
+// AddFuzzFooSeeds adds the entries of FuzzFooCorpus to f, e.g., a
+// *testing.F.
+func AddFuzzFooSeeds(f fuzzdump.Seeder) error {
+	return fuzzdump.AddSeeds(f, FuzzFooCorpus, FuzzFooCorpusDir)
+}
+`
+	tests := map[string]struct {
+		d    embedData
+		want string
+		wErr error
+	}{"nominal": {
+		d:    embedData{"foo", "testdata/fuzz/FuzzFoo", "FuzzFoo"},
+		want: want,
+	}, "bad package": {
+		d:    embedData{"foo-bar", "testdata", "FuzzFoo"},
+		wErr: errBadPackage,
+	}, "current dir": {
+		d:    embedData{"foo", ".", "FuzzFoo"},
+		wErr: errBadEmbedDir,
+	}, "bad name": {
+		d:    embedData{"foo", "testdata", "Fuzz-Foo"},
+		wErr: errBadName,
+	}, "empty name": {
+		d:    embedData{"foo", "testdata", ""},
+		wErr: errBadName,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := writeEmbed(w, tt.d)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, w.String())
+		})
+	}
+	t.Run("write error", func(t *testing.T) {
+		d := embedData{"foo", "testdata", "FuzzFoo"}
+		err := writeEmbed(errWriter{}, d)
+		require.ErrorIs(t, err, errSnap)
+	})
+}
+
+// errWriter returns errSnap on all Write calls.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errSnap }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
+)
+
+// A fileProfile is the size of an entry file and the time it takes to
+// read, parse and format it.
+type fileProfile struct {
+	name                string
+	size                int64
+	read, parse, format time.Duration
+}
+
+func (p fileProfile) total() time.Duration {
+	return p.read + p.parse + p.format
+}
+
+// profileFiles reads, parses and formats the entry files of the corpus
+// directory dir in fsys one after another, timing each stage. The stages
+// of an invalid file after the one it fails at are left untimed.
+func profileFiles(fsys fs.FS, dir string) ([]fileProfile, error) {
+	names, err := fileNames(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]fileProfile, len(names))
+	for i, name := range names {
+		p := &profiles[i]
+		p.name = name
+		start := now()
+		b, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		t := now()
+		p.size, p.read = int64(len(b)), t.Sub(start)
+		start = t
+		e, err := corpus.Unmarshal(b)
+		if err == nil {
+			_, err = e.Values()
+		}
+		t = now()
+		p.parse = t.Sub(start)
+		if err != nil {
+			continue
+		}
+		start = t
+		// Entry errors are write errors, which io.Discard never has.
+		format.NewPrinter(io.Discard, len(e)).Entry(e)
+		p.format = now().Sub(start)
+	}
+	return profiles, nil
+}
+
+// reportProfiles writes the n slowest and the n largest of the profiles
+// of the files in dir to w.
+func reportProfiles(w io.Writer, dir string, profiles []fileProfile, n int) error {
+	if n > len(profiles) {
+		n = len(profiles)
+	}
+	var buf bytes.Buffer
+	prefix := fmt.Sprintf("%s: profile: %s:", cmdName, dir)
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].total() > profiles[j].total()
+	})
+	fmt.Fprintf(&buf, "%s slowest %d of %d files:\n", prefix, n, len(profiles))
+	for _, p := range profiles[:n] {
+		fmt.Fprintf(&buf, "\t%s\t%s (read %s, parse %s, format %s)\n",
+			p.name, p.total(), p.read, p.parse, p.format)
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].size > profiles[j].size
+	})
+	fmt.Fprintf(&buf, "%s largest %d of %d files:\n", prefix, n, len(profiles))
+	for _, p := range profiles[:n] {
+		fmt.Fprintf(&buf, "\t%s\t%d bytes\n", p.name, p.size)
+	}
+	// Written at once, so as not to interleave with other directories.
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dumpMain_profileFiles(t *testing.T) {
+	defer func(v func() time.Time) { now = v }(now)
+	// Each call takes a millisecond longer than the one before.
+	var (
+		clock time.Time
+		calls time.Duration
+	)
+	now = func() time.Time {
+		calls++
+		clock = clock.Add(calls * time.Millisecond)
+		return clock
+	}
+	dir := writeCorpus(t, map[string]string{
+		"a": "int(1)",
+		"b": `string("longer")`,
+		"c": "int((",
+	})
+	stdErr := &bytes.Buffer{}
+	err := dumpMain(io.Discard, stdErr, []string{"-profile-files", "2", dir})
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal(cmdName+": profile: "+dir+": slowest 2 of 3 files:\n"+
+		"\tb\t21ms (read 6ms, parse 7ms, format 8ms)\n"+
+		"\tc\t21ms (read 10ms, parse 11ms, format 0s)\n"+
+		cmdName+": profile: "+dir+": largest 2 of 3 files:\n"+
+		"\tb\t33 bytes\n"+
+		"\ta\t23 bytes\n", stdErr.String())
+}
+
+func Test_reportProfiles(t *testing.T) {
+	tests := map[string]struct {
+		w     io.Writer
+		n     int
+		wErr  error
+		wText string
+	}{"more than there are": {
+		w: &bytes.Buffer{},
+		n: 5,
+		wText: cmdName + ": profile: dir: slowest 1 of 1 files:\n" +
+			"\tf\t3ns (read 1ns, parse 1ns, format 1ns)\n" +
+			cmdName + ": profile: dir: largest 1 of 1 files:\n" +
+			"\tf\t1 bytes\n",
+	}, "write error": {
+		w:    errWriter{},
+		n:    1,
+		wErr: errSnap,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			profiles := []fileProfile{{name: "f", size: 1, read: 1, parse: 1, format: 1}}
+			err := reportProfiles(tt.w, "dir", profiles, tt.n)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			if b, ok := tt.w.(*bytes.Buffer); ok {
+				req.Equal(tt.wText, b.String())
+			}
+		})
+	}
+}
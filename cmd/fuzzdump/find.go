@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// findMain lists the entries of a fuzz test corpus directory with
+// arguments of exact typed values, and extracts them, if asked to.
+func findMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("find")
+	var equals argValues
+	fl.Var(&equals, "equals",
+		"find the entries with the argument at index N equal to `argN=value`, e.g. arg0=uint(42)")
+	extractTo := fl.String("extract-to", "",
+		"copy the entry files found into the directory `path`, creating it, if need be")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 1 || args[0] == "" {
+		return errNoDirArg
+	}
+	fsys, dir, err := corpusFS(args[0])
+	if err != nil {
+		return err
+	}
+	opts := make([]fuzzdump.Option, len(equals))
+	for i, v := range equals {
+		opts[i] = fuzzdump.WithEqual(v.arg, v.value)
+	}
+	names, err := fuzzdump.Matching(fsys, dir, opts...)
+	if exitCodeFor(err) >= fuzzdump.ExitHard {
+		return err
+	}
+	if *extractTo != "" {
+		if wErr := extractEntries(*extractTo, fsys, dir, names); wErr != nil {
+			return wErr
+		}
+	}
+	for _, name := range names {
+		if _, wErr := fmt.Fprintln(w, name); wErr != nil {
+			return wErr
+		}
+	}
+	return err
+}
+
+// extractEntries copies the named entry files of dir in fsys into the
+// directory dst, creating it, if it does not exist.
+func extractEntries(dst string, fsys fs.FS, dir string, names []string) error {
+	if err := wfs.MkdirAll(dst); err != nil {
+		return err
+	}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if err := extractEntry(dst, name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findMain(t *testing.T) {
+	values := map[string]string{
+		"1": "uint(42)\nstring(\"a\")",
+		"2": "uint(0x2a)\nstring(\"b\")",
+		"3": "int(42)\nstring(\"a\")",
+	}
+	tests := map[string]struct {
+		args []string
+		wOut string
+		wErr error
+	}{"equals": {
+		args: []string{"-equals", "arg0=uint(42)"},
+		wOut: "1\n2\n",
+	}, "equals all": {
+		args: []string{"-equals", "arg0=uint(42)", "--equals", `arg1=string("a")`},
+		wOut: "1\n",
+	}, "none": {
+		args: []string{"-equals", "arg0=uint(7)"},
+	}, "bad value": {
+		args: []string{"-equals", "arg0=42"},
+		wErr: errBadArgValue,
+	}, "no dir": {
+		args: []string{"-equals", "arg0=uint(42)", ""},
+		wErr: errNoDirArg,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			args := tt.args
+			if tt.wErr != errNoDirArg {
+				args = append(args, writeCorpus(t, values))
+			}
+			stdOut := &bytes.Buffer{}
+			err := findMain(stdOut, io.Discard, args)
+			req := require.New(t)
+			if tt.wErr != nil {
+				// The flag package does not wrap the errors of values.
+				req.ErrorContains(err, tt.wErr.Error())
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.wOut, stdOut.String())
+		})
+	}
+	t.Run("missing dir", func(t *testing.T) {
+		err := findMain(io.Discard, io.Discard, []string{filepath.Join(t.TempDir(), "absent")})
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func Test_findMain_extract(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{
+		"1": "uint(42)",
+		"2": "uint(7)",
+		"3": "uint(",
+	})
+	dst := filepath.Join(t.TempDir(), "repro", "FuzzMyFunc")
+	stdOut := &bytes.Buffer{}
+	err := findMain(stdOut, io.Discard, []string{"-equals", "arg0=uint(42)", "-extract-to", dst, dir})
+	req := require.New(t)
+	req.ErrorIs(err, fuzzdump.ErrMalformedValue)
+	req.Equal("1\n", stdOut.String())
+	got, err := os.ReadFile(filepath.Join(dst, "1"))
+	req.NoError(err)
+	req.Equal("go test fuzz v1\nuint(42)\n", string(got))
+	entries, err := os.ReadDir(dst)
+	req.NoError(err)
+	req.Len(entries, 1)
+}
+
+func Test_findMain_extract_memFS(t *testing.T) {
+	m := &corpusdir.MemFS{}
+	setWFS(t, m)
+	dir := writeCorpus(t, map[string]string{"1": "uint(42)", "2": "uint(7)"})
+	err := findMain(io.Discard, io.Discard, []string{"-equals", "arg0=uint(42)", "-extract-to", "/repro/FuzzMyFunc", dir})
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal([]string{"repro", "repro/FuzzMyFunc", "repro/FuzzMyFunc/1"}, m.Names())
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// argBounds is a repeatable flag of argN=value pairs, setting numeric
+// bounds for the arguments at index N.
+type argBounds []argBound
+
+type argBound struct {
+	arg   int
+	value float64
+}
+
+// String implements the [flag.Value] interface.
+func (b *argBounds) String() string {
+	s := make([]string, len(*b))
+	for i, v := range *b {
+		s[i] = fmt.Sprintf("arg%d=%g", v.arg, v.value)
+	}
+	return strings.Join(s, ",")
+}
+
+// Set implements the [flag.Value] interface.
+func (b *argBounds) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return errBadArgBound
+	}
+	arg, err := parseArgIndex(k)
+	if err != nil {
+		return err
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBadArgBound, err)
+	}
+	*b = append(*b, argBound{arg, f})
+	return nil
+}
+
+// lenBounds is a repeatable flag of length bounds, each either applying
+// to all arguments, or to a single one, if given as argN=length.
+type lenBounds []lenBound
+
+type lenBound struct {
+	args []int // Indexes of the arguments to check, all if empty.
+	len  int
+}
+
+// String implements the [flag.Value] interface.
+func (b *lenBounds) String() string {
+	s := make([]string, len(*b))
+	for i, v := range *b {
+		s[i] = strconv.Itoa(v.len)
+		if len(v.args) > 0 {
+			s[i] = fmt.Sprintf("arg%d=%s", v.args[0], s[i])
+		}
+	}
+	return strings.Join(s, ",")
+}
+
+// Set implements the [flag.Value] interface.
+func (b *lenBounds) Set(s string) error {
+	var v lenBound
+	if k, l, ok := strings.Cut(s, "="); ok {
+		arg, err := parseArgIndex(k)
+		if err != nil {
+			return err
+		}
+		v.args = []int{arg}
+		s = l
+	}
+	l, err := strconv.Atoi(s)
+	if err != nil || l < 0 {
+		return fmt.Errorf("%w: %q", errBadLen, s)
+	}
+	v.len = l
+	*b = append(*b, v)
+	return nil
+}
+
+// argValues is a repeatable flag of argN=value pairs, setting the exact
+// typed values, e.g. uint(42), of the arguments at index N.
+type argValues []argValue
+
+type argValue struct {
+	arg   int
+	value corpus.Value
+}
+
+// String implements the [flag.Value] interface.
+func (a *argValues) String() string {
+	s := make([]string, len(*a))
+	for i, v := range *a {
+		line, _ := corpus.EncodeValue(v.value)
+		s[i] = fmt.Sprintf("arg%d=%s", v.arg, line)
+	}
+	return strings.Join(s, ",")
+}
+
+// Set implements the [flag.Value] interface.
+func (a *argValues) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return errBadArgValue
+	}
+	arg, err := parseArgIndex(k)
+	if err != nil {
+		return err
+	}
+	val, err := corpus.DecodeValue([]byte(v))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBadArgValue, err)
+	}
+	*a = append(*a, argValue{arg, val})
+	return nil
+}
+
+// stringList is a repeatable flag of strings.
+type stringList []string
+
+// String implements the [flag.Value] interface.
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+// Set implements the [flag.Value] interface.
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// hexBytes is a flag of a byte sequence given in hexadecimal.
+type hexBytes []byte
+
+// String implements the [flag.Value] interface.
+func (b *hexBytes) String() string { return strings.ToUpper(hex.EncodeToString(*b)) }
+
+// Set implements the [flag.Value] interface.
+func (b *hexBytes) Set(s string) error {
+	v, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBadHex, err)
+	}
+	*b = v
+	return nil
+}
+
+// parseArgIndex parses an argument reference in the form of "argN",
+// returning N.
+func parseArgIndex(s string) (int, error) {
+	if !strings.HasPrefix(s, "arg") {
+		return 0, fmt.Errorf("%w: %q", errBadArgRef, s)
+	}
+	i, err := strconv.Atoi(s[len("arg"):])
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("%w: %q", errBadArgRef, s)
+	}
+	return i, nil
+}
+
+// isFlagSet reports whether the flag with the given name was set on the
+// command line parsed by fl, rather than left at its default.
+func isFlagSet(fl *flag.FlagSet, name string) (set bool) {
+	fl.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return
+}
+
+var (
+	errBadArgBound = errors.New("bound must be given as argN=value")
+	errBadArgRef   = errors.New(`argument must be referred to as "argN"`)
+	errBadArgValue = errors.New("value must be given as argN=type(value)")
+	errBadLen      = errors.New("length must be a non-negative integer")
+	errBadHex      = errors.New("bytes must be given in hexadecimal")
+)
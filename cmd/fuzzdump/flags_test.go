@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_argBounds_Set(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want argBounds
+		wErr error
+	}{
+		"nominal":        {s: "arg2=100", want: argBounds{{2, 100}}},
+		"float":          {s: "arg0=-1.5e3", want: argBounds{{0, -1500}}},
+		"no value":       {s: "arg2", wErr: errBadArgBound},
+		"bad value":      {s: "arg2=foo", wErr: errBadArgBound},
+		"no prefix":      {s: "2=100", wErr: errBadArgRef},
+		"bad index":      {s: "argx=100", wErr: errBadArgRef},
+		"negative index": {s: "arg-1=100", wErr: errBadArgRef},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var b argBounds
+			err := b.Set(tt.s)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, b)
+		})
+	}
+}
+
+func Test_argBounds_String(t *testing.T) {
+	b := argBounds{{0, 1}, {2, 0.5}}
+	require.Equal(t, "arg0=1,arg2=0.5", b.String())
+}
+
+func Test_argValues_Set(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want argValues
+		wErr error
+	}{
+		"nominal":   {s: "arg1=uint(0x2a)", want: argValues{{1, uint(42)}}},
+		"string":    {s: `arg0=string("a=b")`, want: argValues{{0, "a=b"}}},
+		"no value":  {s: "arg1", wErr: errBadArgValue},
+		"untyped":   {s: "arg1=42", wErr: errBadArgValue},
+		"no prefix": {s: "1=uint(42)", wErr: errBadArgRef},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var a argValues
+			err := a.Set(tt.s)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, a)
+		})
+	}
+}
+
+func Test_argValues_String(t *testing.T) {
+	a := argValues{{0, uint(42)}, {2, "a"}}
+	require.Equal(t, `arg0=uint(42),arg2=string("a")`, a.String())
+}
+
+func Test_lenBounds_Set(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want lenBounds
+		wErr error
+	}{
+		"all":       {s: "10", want: lenBounds{{nil, 10}}},
+		"arg":       {s: "arg1=10", want: lenBounds{{[]int{1}, 10}}},
+		"bad len":   {s: "foo", wErr: errBadLen},
+		"negative":  {s: "-1", wErr: errBadLen},
+		"bad arg":   {s: "1=10", wErr: errBadArgRef},
+		"arg empty": {s: "arg1=", wErr: errBadLen},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var b lenBounds
+			err := b.Set(tt.s)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, b)
+		})
+	}
+}
+
+func Test_stringList(t *testing.T) {
+	var l stringList
+	req := require.New(t)
+	req.NoError(l.Set("foo"))
+	req.NoError(l.Set("bar,qux"))
+	req.Equal(stringList{"foo", "bar,qux"}, l)
+	req.Equal("foo,bar,qux", l.String())
+}
+
+func Test_hexBytes(t *testing.T) {
+	tests := map[string]struct {
+		s    string
+		want hexBytes
+		wErr error
+	}{"upper": {
+		s: "DEADBEEF", want: hexBytes{0xde, 0xad, 0xbe, 0xef},
+	}, "lower": {
+		s: "00ff", want: hexBytes{0x00, 0xff},
+	}, "odd length": {
+		s: "abc", wErr: errBadHex,
+	}, "not hex": {
+		s: "xyz0", wErr: errBadHex,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var b hexBytes
+			err := b.Set(tt.s)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, b)
+			req.Equal(strings.ToUpper(tt.s), b.String())
+		})
+	}
+}
+
+func Test_lenBounds_String(t *testing.T) {
+	b := lenBounds{{nil, 1}, {[]int{2}, 3}}
+	require.Equal(t, "1,arg2=3", b.String())
+}
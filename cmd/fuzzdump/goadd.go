@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"text/template"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// A dumpFunc dumps a corpus, as the functions in dumpFormats do.
+type dumpFunc = func(w io.Writer, fsys fs.FS, dir string, opts ...fuzzdump.Option) error
+
+// goAddFile is what a Go source file of calls of Add is made of.
+type goAddFile struct {
+	// Package name of the file.
+	Package string
+	// Func is the name of the function making the calls.
+	Func string
+	// Receiver is the name of its *testing.F parameter, whose Add method
+	// is called.
+	Receiver string
+	// Calls are the calls of Add, as fuzzdump.DumpDirGoAdd dumps them.
+	Calls string
+	// Math is whether the calls need package math imported.
+	Math bool
+}
+
+// goAddFunc returns dump wrapped to write the calls of Add that it dumps
+// to w in the body of the function of a Go source file that d describes,
+// e.g., to drop into a package as it is.
+func goAddFunc(d goAddFile, dump dumpFunc) dumpFunc {
+	return func(w io.Writer, fsys fs.FS, dir string, opts ...fuzzdump.Option) error {
+		b := &bytes.Buffer{}
+		err := dump(b, fsys, dir, opts...)
+		if exitCodeFor(err) >= fuzzdump.ExitHard {
+			return err
+		}
+		d.Calls = b.String()
+		if wErr := writeGoAddFile(w, d); wErr != nil {
+			return wErr
+		}
+		return err
+	}
+}
+
+// writeGoAddFile writes the Go source file that d describes to w.
+func writeGoAddFile(w io.Writer, d goAddFile) error {
+	for _, v := range []struct {
+		name string
+		err  error
+	}{{d.Package, errBadPackage}, {d.Func, errBadFunc}, {d.Receiver, errBadReceiver}} {
+		if !token.IsIdentifier(v.name) {
+			return fmt.Errorf("%w: %q", v.err, v.name)
+		}
+	}
+	b := &bytes.Buffer{}
+	if err := goAddTmpl.Execute(b, d); err != nil {
+		return err
+	}
+	// Only the calls of the functions of package math that return the
+	// floats Go has no literals for need it imported.
+	if usesMath(b.Bytes()) {
+		d.Math = true
+		b.Reset()
+		if err := goAddTmpl.Execute(b, d); err != nil {
+			return err
+		}
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// usesMath reports whether the Go source src refers to package math.
+func usesMath(src []byte) (uses bool) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		// Left for go/format to report.
+		return false
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := s.X.(*ast.Ident); ok && x.Name == "math" {
+				uses = true
+			}
+		}
+		return !uses
+	})
+	return
+}
+
+var goAddTmpl = template.Must(template.New("goadd").Parse(`package {{.Package}}
+
+import (
+{{- if .Math}}
+	"math"
+{{- end}}
+	"testing"
+)
+
+// {{.Func}} adds the entries of a fuzz test corpus to {{.Receiver}} as seeds.
+func {{.Func}}({{.Receiver}} *testing.F) {
+{{.Calls}}}
+`))
+
+var (
+	errBadFunc     = errors.New("invalid function name")
+	errBadReceiver = errors.New("invalid receiver name")
+	errFuncFormat  = errors.New("-func requires -format goadd")
+	errFuncDirs    = errors.New("-func requires a single corpus directory")
+)
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dumpMain_goAddFunc(t *testing.T) {
+	tests := map[string]struct {
+		args   []string
+		values map[string]string
+		wOut   string
+		wErr   error
+	}{"nominal": {
+		args:   []string{"-func", "seedFoo", "-pkg", "foo"},
+		values: map[string]string{"1": "int(1)\nstring(\"a\")", "2": "int(2)\nstring(\"b\")"},
+		wOut: "package foo\n\nimport (\n\t\"testing\"\n)\n\n" +
+			"// seedFoo adds the entries of a fuzz test corpus to f as seeds.\n" +
+			"func seedFoo(f *testing.F) {\n" +
+			"\tf.Add(int(1), string(\"a\"))\n" +
+			"\tf.Add(int(2), string(\"b\"))\n}\n",
+	}, "math": {
+		args:   []string{"-func", "seedFoo", "-pkg", "foo", "-receiver", "ff"},
+		values: map[string]string{"1": "float64(+Inf)\nstring(\"math.Pi\")"},
+		wOut: "package foo\n\nimport (\n\t\"math\"\n\t\"testing\"\n)\n\n" +
+			"// seedFoo adds the entries of a fuzz test corpus to ff as seeds.\n" +
+			"func seedFoo(ff *testing.F) {\n" +
+			"\tff.Add(float64(math.Inf(1)), string(\"math.Pi\"))\n}\n",
+	}, "no math in strings": {
+		args:   []string{"-func", "seedFoo", "-pkg", "foo"},
+		values: map[string]string{"1": "string(\"math.Pi\")"},
+		wOut: "package foo\n\nimport (\n\t\"testing\"\n)\n\n" +
+			"// seedFoo adds the entries of a fuzz test corpus to f as seeds.\n" +
+			"func seedFoo(f *testing.F) {\n" +
+			"\tf.Add(string(\"math.Pi\"))\n}\n",
+	}, "invalid entry": {
+		args:   []string{"-func", "seedFoo", "-pkg", "foo"},
+		values: map[string]string{"1": "int(1)", "2": "int("},
+		wOut:   "\tf.Add(int(1))\n}\n",
+		wErr:   fuzzdump.ErrMalformedValue,
+	}, "receiver only": {
+		args:   []string{"-receiver", "ff"},
+		values: map[string]string{"1": "int(1)"},
+		wOut:   "ff.Add(int(1))\n",
+	}, "bad func": {
+		args:   []string{"-func", "seed-foo", "-pkg", "foo"},
+		values: map[string]string{"1": "int(1)"},
+		wErr:   errBadFunc,
+	}, "bad package": {
+		args:   []string{"-func", "seedFoo", "-pkg", ""},
+		values: map[string]string{"1": "int(1)"},
+		wErr:   errBadPackage,
+	}, "bad receiver": {
+		args:   []string{"-func", "seedFoo", "-pkg", "foo", "-receiver", "1f"},
+		values: map[string]string{"1": "int(1)"},
+		wErr:   errBadReceiver,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut := &bytes.Buffer{}
+			args := append([]string{"-format", "goadd"}, tt.args...)
+			err := dumpMain(stdOut, io.Discard, append(args, writeCorpus(t, tt.values)))
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			if tt.wOut != "" {
+				req.Contains(stdOut.String(), tt.wOut)
+			}
+		})
+	}
+	t.Run("not goadd", func(t *testing.T) {
+		err := dumpMain(io.Discard, io.Discard, []string{"-func", "seedFoo", writeCorpus(t, nil)})
+		require.ErrorIs(t, err, errFuncFormat)
+	})
+	t.Run("multiple dirs", func(t *testing.T) {
+		dir := writeCorpus(t, nil)
+		err := dumpMain(io.Discard, io.Discard, []string{"-format", "goadd", "-func", "seedFoo", dir, dir})
+		require.ErrorIs(t, err, errFuncDirs)
+	})
+}
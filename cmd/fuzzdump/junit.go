@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// JUnit XML report structure, as understood by most CI systems.
+type (
+	junitTestSuites struct {
+		XMLName xml.Name         `xml:"testsuites"`
+		Suites  []junitTestSuite `xml:"testsuite"`
+	}
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// writeJUnitReport writes s to w as a JUnit XML report with a test case
+// for each entry file, failing for the invalid ones, and one more for
+// the thresholds, if any are exceeded.
+func writeJUnitReport(w io.Writer, s stats) error {
+	invalid := make(map[string]error, len(s.invalid))
+	for _, f := range s.invalid {
+		invalid[f.name] = f.err
+	}
+	suite := junitTestSuite{Name: s.dir}
+	addCase := func(name, rule string, err error) {
+		c := junitTestCase{Name: name, ClassName: s.dir}
+		if err != nil {
+			c.Failure = &junitFailure{Message: err.Error(), Type: rule, Text: err.Error()}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+		suite.Tests++
+	}
+	for _, name := range s.names {
+		addCase(name, ruleInvalidEntry, invalid[name])
+	}
+	for _, b := range s.breaches {
+		addCase("thresholds", ruleThreshold, fmt.Errorf("%w: %s", errThreshold, b))
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeJUnitReport(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"1": "int(1)", "2": "int(("})
+	stdOut := &bytes.Buffer{}
+	err := checkMain(stdOut, io.Discard, []string{"-format", "junit", "-max-entries", "1", dir})
+	req := require.New(t)
+	req.ErrorIs(err, errThreshold)
+
+	var report junitTestSuites
+	req.NoError(xml.Unmarshal(stdOut.Bytes(), &report))
+	req.Len(report.Suites, 1)
+	suite := report.Suites[0]
+	req.Equal(dir, suite.Name)
+	req.Equal(3, suite.Tests)
+	req.Equal(2, suite.Failures)
+
+	names := make([]string, len(suite.Cases))
+	for i, c := range suite.Cases {
+		names[i] = c.Name
+	}
+	req.Equal([]string{"1", "2", "thresholds"}, names)
+	req.Nil(suite.Cases[0].Failure)
+	req.Equal(ruleInvalidEntry, suite.Cases[1].Failure.Type)
+	req.Equal(ruleThreshold, suite.Cases[2].Failure.Type)
+	req.Equal(errThreshold.Error()+": 2 entries (max 1)", suite.Cases[2].Failure.Message)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lockCorpus takes the advisory lock of the corpus at path, so that no
+// other fuzzdump process mutates it (or, if asked to, reads it) at the
+// same time, returning the function to release it with.
+//
+// The lock is a file next to the corpus (rather than in it, as Go takes
+// every file in a corpus directory for an entry) that is created when
+// the lock is taken and removed when it is released. If the lock is
+// already taken, [errLocked] is returned, naming the process that holds
+// it. A lock left behind by a process that crashed has to be removed by
+// hand.
+func lockCorpus(path string) (unlock func(), err error) {
+	name := lockPath(path)
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, lockedError(name)
+	}
+	if err != nil {
+		return
+	}
+	host, _ := os.Hostname()
+	_, err = fmt.Fprintf(f, "%d@%s\n", os.Getpid(), host)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		os.Remove(name)
+		return
+	}
+	return func() { os.Remove(name) }, nil
+}
+
+// lockCorpora takes the locks of all the corpora at paths, as
+// [lockCorpus] does, releasing those already taken if that fails.
+func lockCorpora(paths ...string) (unlock func(), err error) {
+	var unlocks []func()
+	unlock = func() {
+		for _, u := range unlocks {
+			u()
+		}
+	}
+	for _, p := range paths {
+		u, err := lockCorpus(p)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		unlocks = append(unlocks, u)
+	}
+	return
+}
+
+// checkUnlocked returns [errLocked] if the lock of the corpus at path is
+// taken.
+func checkUnlocked(path string) error {
+	name := lockPath(path)
+	if _, err := os.Lstat(name); err != nil {
+		return nil
+	}
+	return lockedError(name)
+}
+
+// lockedError returns [errLocked] naming the lock file name and the
+// process holding it, as recorded in it.
+func lockedError(name string) error {
+	holder := "unknown process"
+	if b, err := os.ReadFile(name); err == nil && len(b) > 0 {
+		holder = "process " + strings.TrimSpace(string(b))
+	}
+	return fmt.Errorf("%w: %q by %s", errLocked, name, holder)
+}
+
+// lockPath returns the path of the lock file of the corpus at path.
+func lockPath(path string) string {
+	return filepath.Clean(path) + lockSuffix
+}
+
+const lockSuffix = ".fuzzdump-lock"
+
+var errLocked = errors.New("corpus locked")
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lockCorpus(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "FuzzFoo")
+	req := require.New(t)
+	unlock, err := lockCorpus(dir)
+	req.NoError(err)
+	b, err := os.ReadFile(lockPath(dir))
+	req.NoError(err)
+	req.Regexp(`^\d+@`, string(b))
+
+	_, err = lockCorpus(dir)
+	req.ErrorIs(err, errLocked)
+	req.Contains(err.Error(), "by process "+string(bytes.TrimSpace(b)))
+	req.ErrorIs(checkUnlocked(dir), errLocked)
+
+	unlock()
+	req.NoError(checkUnlocked(dir))
+	unlock, err = lockCorpus(dir + string(filepath.Separator))
+	req.NoError(err, "taken again after release")
+	unlock()
+}
+
+func Test_lockCorpora(t *testing.T) {
+	root := t.TempDir()
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	req := require.New(t)
+	unlockB, err := lockCorpus(b)
+	req.NoError(err)
+	_, err = lockCorpora(a, b)
+	req.ErrorIs(err, errLocked)
+	req.NoError(checkUnlocked(a), "released on failure")
+	unlockB()
+
+	unlock, err := lockCorpora(a, b)
+	req.NoError(err)
+	req.ErrorIs(checkUnlocked(a), errLocked)
+	req.ErrorIs(checkUnlocked(b), errLocked)
+	unlock()
+	req.NoError(checkUnlocked(a))
+	req.NoError(checkUnlocked(b))
+}
+
+func Test_locked(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"abc1": "int(1)"})
+	dst := filepath.Join(t.TempDir(), "dst")
+	unlock, err := lockCorpus(src)
+	require.NoError(t, err)
+	defer unlock()
+	unlockDst, err := lockCorpus(dst)
+	require.NoError(t, err)
+	defer unlockDst()
+
+	tests := map[string]struct {
+		main mainFn
+		args []string
+	}{"mv": {
+		main: mvMain,
+		args: []string{src, filepath.Join(t.TempDir(), "new")},
+	}, "convert": {
+		main: convertMain,
+		args: []string{src, dst},
+	}, "minimize": {
+		main: minimizeMain,
+		args: []string{src, dst},
+	}, "tag": {
+		main: tagMain,
+		args: []string{src, "slow", "abc"},
+	}, "dump": {
+		main: dumpMain,
+		args: []string{"-respect-lock", src},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := tt.main(io.Discard, io.Discard, tt.args)
+			require.ErrorIs(t, err, errLocked)
+		})
+	}
+	// Reading is not affected, unless asked to be.
+	require.NoError(t, dumpMain(io.Discard, io.Discard, []string{src}))
+	require.NoError(t, tagMain(io.Discard, io.Discard, []string{src}))
+}
@@ -6,6 +6,184 @@
 //
 //	$ fuzzdump ./fuzz/FuzzMyFunc
 //
+// When given multiple directories, it dumps them concurrently, writing
+// each dump in its own section headed by a "// dir" comment, in the
+// order the directories were given. The exit status is then the highest
+// of those of the individual directories.
+//
+// The following flags may precede the directory path:
+//
+//	-canonical
+//		normalize all values and sort entries by their contents, so the
+//		output only changes when the corpus values do
+//	-sort key
+//	-desc
+//		dump the entries in the order of their file names (name), their
+//		contents (contents), the sizes (size) or the modification times
+//		(mtime) of their files, or the SHA-256 hashes of their contents,
+//		whatever their files are named (hash), descending with -desc;
+//		-canonical takes precedence over -sort
+//	-normalize
+//		render all values anew from their decoded form, so the output is
+//		the same on every platform and with every Go version, but keep
+//		the entries in the order of their file names
+//	-bytes format
+//		render []byte values as they are (raw), as Go hex literals (hex),
+//		as their lengths after a hex dump of them in comments (dump), as
+//		their base64 encoding (b64), or as just their lengths (summary),
+//		which binary data is more readable as
+//	-arg-labels
+//		prefix each value of a multiple-argument corpus with a comment
+//		stating the index of the argument it holds, e.g. "/* arg0 */"
+//	-allow-empty
+//		treat an empty or missing corpus directory as a corpus with no
+//		entries, so it is dumped as empty braces with exit status 0
+//	-atomic
+//		render the entire dump in memory and write nothing if a critical
+//		error occurs, so that a truncated dump is never written
+//	-strict
+//		like -atomic, but also write nothing if any corpus file or value
+//		is invalid, only reporting the errors
+//	-min argN=value
+//	-max argN=value
+//		skip the entries whose argument at index N is not a number
+//		within the given (inclusive) bound; may be repeated
+//	-min-len [argN=]length
+//	-max-len [argN=]length
+//		skip the entries whose string and []byte arguments (or just
+//		the one at index N) are not within the given (inclusive) length
+//		in bytes; may be repeated
+//	-filter expr
+//		dump just the entries that the filter expression holds for, e.g.
+//		'arg[0].type == "string" && len > 100', written in a subset of
+//		Go, see fuzzdump.ParseFilter
+//	-find-hex bytes
+//		dump just the entries that have a string or []byte argument
+//		containing the bytes given in hexadecimal, e.g. DEADBEEF, noting
+//		the offsets where they are found in a comment ahead of each
+//	-accept-version header
+//		also accept corpus entry files with the given version header
+//		line, as written by a patched toolchain; may be repeated
+//	-assume-v1
+//		salvage the entry files that lack a version header, but hold
+//		only valid values, such as ones concatenated or trimmed by hand,
+//		dumping them, but still reporting them (with exit status 1)
+//	-recover
+//		salvage the entry files with valid leading values followed by
+//		lines that cannot be decoded, as when an append was corrupted,
+//		dumping those values, but reporting the line number and the byte
+//		offset where the garbage starts (with exit status 1)
+//	-frame kind
+//		write each entry as a separate record, a complete dump of just
+//		that entry, framed for streaming consumers: prefixed with its
+//		length in bytes and a newline (length), or with an ASCII record
+//		separator character (rs)
+//	-format format
+//		dump the entries in the format: the default (dump), a JSON
+//		array, one entry per line, each an array of its typed values,
+//		e.g. {"type":"int","value":42}, for processing with tools such
+//		as jq (json), the same arrays of values without the enclosing
+//		one, an entry per line, to stream into such tools (ndjson),
+//		calls of f.Add with the values as typed Go literals, e.g.
+//		f.Add(int(42)), to paste into a fuzz test (goadd),
+//		or a line per entry with its values aligned into columns across
+//		the corpus, to make the patterns in short values obvious (columns)
+//	-json
+//		the same as -format json
+//	-receiver name
+//		with -format goadd, call the Add method of the variable name
+//		instead of f
+//	-func name
+//	-pkg name
+//		with -format goadd, write a Go source file of package pkg (by
+//		default $GOPACKAGE, as go generate sets it), with a function
+//		name taking the *testing.F receiver and making the calls, to drop
+//		into a package as it is, e.g. with -generate -o seeds_test.go
+//	-preview n
+//		dump just the first and the last n entries (in the order they
+//		would be dumped otherwise), with a comment noting the number of
+//		those omitted in between
+//	-limit n
+//	-offset n
+//		dump at most n entries, after leaving out the first n ones (in
+//		the order they would be dumped otherwise), with comments noting
+//		the number of those omitted, e.g., to page through a huge corpus
+//	-hash-values salt
+//		replace the contents of every string and []byte value with as
+//		many bytes derived from a hash of it, salted with salt, keeping
+//		the structure, the sizes, and the other values intact, so the
+//		shape of a corpus can be shared without its payloads
+//	-tags a,b
+//		dump just the entries that have any of the comma-separated
+//		tags, as set with the tag command
+//	-crashers
+//		dump the failing inputs that go test -fuzz writes (named by the
+//		hash of their contents) in a section headed by a "// crashers"
+//		comment, followed by the rest in a "// seeds" one
+//	-only-crashers
+//		dump just the failing inputs that go test -fuzz writes
+//	-respect-lock
+//		fail if a corpus is locked by another fuzzdump command mutating
+//		it, instead of dumping it regardless
+//	-names
+//		note the name of the file of each entry in a comment ahead of
+//		it, to find the file of an entry spotted in the dump
+//	-sizes
+//		note the number of arguments and their total size once decoded
+//		in a comment ahead of each entry, e.g. "// 3 args, 18 KiB"
+//	-provenance
+//		note where the entries imported with convert -provenance came
+//		from in a comment ahead of each of them
+//	-summary-only
+//		validate the corpus and, instead of dumping it, print just a
+//		summary: the number of entries, the bytes they take, how many
+//		are invalid, and the argument types, e.g.:
+//		"12 entries, 540 bytes, 1 invalid, signature (int, string)"
+//	-memory-cap bytes
+//		fail if the entries that have to be held in memory at once, such
+//		as for sorting them with -canonical, take more than bytes; this
+//		is a hard cap, nothing is spilled to disk to get past it
+//	-cache
+//		take the arguments for fuzz target names, and dump their corpora
+//		in the fuzz cache of the Go toolchain, where the inputs go test
+//		-fuzz generates are kept, e.g. fuzzdump -cache FuzzParse; a
+//		target of the package in the current directory is looked up
+//		first, then one of any package, as long as only one has it
+//	-r
+//		dump every fuzz test corpus directory in the trees under the
+//		given directories, e.g. a whole testdata/fuzz, or the fuzz cache
+//		of the Go toolchain, each in its own section, as with multiple
+//		directories; a corpus directory is one named as a fuzz target,
+//		e.g. FuzzParse
+//	-j n
+//		dump up to n directories concurrently (default GOMAXPROCS)
+//	-read-jobs n
+//		read and parse up to n files of each directory concurrently
+//		(default GOMAXPROCS), e.g., more than there are CPUs, on a network
+//		file system; the output is in the same order either way
+//	-o file
+//		write the output to file instead of the standard output; the
+//		file is only replaced when there is something to write to it;
+//		unless -format or -json is given, the extension of the file
+//		name selects the format: .json the json, .ndjson the ndjson,
+//		.go the goadd, and .txt the dump one; any other extension,
+//		e.g. .golden, or none, leaves the default
+//	-bench
+//		after the dump, report the number of files read, the amounts
+//		of data read and written, the time it took, and the respective
+//		throughput to the standard error
+//	-cpuprofile file
+//	-memprofile file
+//		write a CPU or memory profile, respectively, to file, for use
+//		with "go tool pprof"
+//	-profile-files n
+//		after the dump, time reading, parsing and formatting each entry
+//		file on its own, and report the n slowest and the n largest
+//		files to the standard error
+//	-generate
+//		start the output with a "Code generated ... DO NOT EDIT." line,
+//		for use in //go:generate directives (requires -o)
+//
 // The output format of a single-argument corpus is similar to a plain
 // slice with the type omitted, e.g.:
 //
@@ -31,20 +209,263 @@
 //		// ... etc.
 //	}}
 //
+// The embed command generates a Go source file that embeds a corpus
+// directory (given relative to the package directory) and provides a
+// helper to add its entries to a fuzz test as seeds, e.g.:
+//
+//	//go:generate fuzzdump embed -o corpus_gen.go testdata/fuzz/FuzzMyFunc
+//
+// That declares FuzzMyFuncCorpus, an [io/fs.FS], and
+// AddFuzzMyFuncSeeds, which passes the entries to a [testing.F].
+// The package name defaults to that set by go generate.
+//
+// The convert command converts a corpus from one format to another,
+// e.g.:
+//
+//	$ fuzzdump convert -to json ./fuzz/FuzzMyFunc corpus.json
+//
+// The source format is detected, unless given with -from: a directory
+// of raw inputs (raw), such as a libFuzzer corpus, a Go corpus
+// directory (v1), or a JSON array or NDJSON dump file (json-dump). The
+// destination format is given with -to: a Go corpus directory (v1, the
+// default), a JSON array dump (json), an NDJSON dump (ndjson), a
+// directory of raw inputs (libfuzzer), or a zip (zip) or gzipped tar
+// (tar.gz) archive of Go corpus entry files. A dump is read from the standard
+// input, or written to the standard output, if its path is "-".
+//
+// Converting a dump back to a Go corpus directory re-encodes the typed
+// values and names the files as Go would, e.g.:
+//
+//	$ fuzzdump convert corpus.json ./fuzz/FuzzMyFunc
+//
+// With -coerce, the values are converted to the listed argument types,
+// as long as they are preserved: integers to any integer type they are
+// in range of, floats to a float type that holds them exactly, strings
+// to []byte and vice versa. Entries that cannot be converted are left
+// out and reported (with exit status 1), e.g.:
+//
+//	$ fuzzdump convert -coerce int64,[]byte ./fuzz/FuzzOld ./fuzz/FuzzNew
+//
+// The export archive command converts a corpus to an archive, with its
+// format given by the extension of the destination file name (.zip,
+// .tar.gz or .tgz), taking the same flags as the convert command, e.g.:
+//
+//	$ fuzzdump export archive -tags regression ./fuzz/FuzzMyFunc corpus.zip
+//
+// The files in the archive are named as Go would name them and sorted,
+// so the same corpus always makes the same archive.
+//
+// With -provenance, converting to a Go corpus directory also records
+// where each entry came from (the source path and format, the name of
+// the entry there, and the time) in a sidecar JSON file next to the
+// directory, e.g. FuzzMyFunc.fuzzdump-provenance.json, for dumps with
+// -provenance to note in comments.
+//
+// The mv command moves a corpus directory to another path, as when a
+// fuzz function is renamed, e.g.:
+//
+//	$ fuzzdump mv -signature int,string testdata/fuzz/FuzzOld testdata/fuzz/FuzzNew
+//
+// Nothing is moved unless every entry is valid and, if -signature is
+// given, has arguments of the listed types. The entries are added to
+// any already in the destination directory, but never replace a
+// different one. With -copy, the source directory is kept.
+//
+// The snapshot command backs up a corpus to a single file: a zip
+// archive of the entry files as they are, and a manifest of their names,
+// sizes, SHA-256 checksums, modification times and tags. The restore
+// command writes it back to a missing or empty directory, once every
+// entry checks out against the manifest, e.g.:
+//
+//	$ fuzzdump snapshot -o FuzzMyFunc.fdz ./fuzz/FuzzMyFunc
+//	$ fuzzdump restore FuzzMyFunc.fdz ./fuzz/FuzzMyFunc
+//
+// A snapshot is written to the standard output, or read from the
+// standard input, if its path is "-".
+//
+// The commands that write corpus directories (convert, merge, mv,
+// minimize and restore) write the files to a temporary staging
+// directory next to the destination first, and only move them into it
+// once all are written, so that a failure never leaves the destination
+// half written.
+//
+// They, and the dedupe and tag commands, also take an advisory lock of
+// the corpora they mutate: a file next to the corpus, with the
+// ".fuzzdump-lock" suffix, holding the ID of the process. If another
+// process holds the lock already, they fail. A dump only does with
+// -respect-lock. A lock left behind by a crashed process has to be
+// removed by hand.
+//
+// The check command reports the number of entries in a corpus, the
+// bytes they take, and how many of them are invalid, and fails with a
+// dedicated exit status if any of the given thresholds are exceeded,
+// for use as a CI gate, e.g.:
+//
+//	$ fuzzdump check -max-entries 1000 -max-bytes 1048576 -max-invalid 0 ./fuzz/FuzzMyFunc
+//
+// With -format sarif, it writes a SARIF 2.1.0 log instead, reporting
+// each invalid entry at its file, and each threshold exceeded at the
+// corpus directory, for code scanning tools to ingest. With -format
+// junit, it writes a JUnit XML report with a test case for each entry
+// file, failing for the invalid ones, and one for the thresholds, failing
+// if any are exceeded.
+//
+// The stats command reports the number of entries in a corpus, how many
+// of them are invalid, and how many have the same values as another, the
+// argument types, and the smallest, largest, mean and total size of the
+// entry files, e.g.:
+//
+//	$ fuzzdump stats ./fuzz/FuzzMyFunc
+//	entries     3
+//	invalid     1
+//	duplicates  1
+//	arguments   2 (int, string)
+//	size        min 21 B, max 37 B, mean 31.0 B, total 93 B
+//
+// Given several directories, or the -r flag to report on every corpus in
+// the trees under them, it writes the statistics of each in a section
+// headed by a comment naming the directory, with how many of its values
+// are shared with another, and a last one of all of them together.
+//
+// The diff command compares two corpora by the values of their entries,
+// and lists those only in the first, marked with "-", and those only in
+// the second, marked with "+", e.g., to review what a fuzzing session
+// has contributed:
+//
+//	$ fuzzdump diff ./testdata/fuzz/FuzzMyFunc ./fuzz-cache/FuzzMyFunc
+//	- 5e6f	int(7), string("bar")
+//	+ a1b2	int(42), string("foo")
+//
+// With -format json, it writes a JSON object of "added", "removed", and
+// "changed" records instead, each with the name of the file, its SHA-256
+// hash, and the typed values, e.g., for bots that comment the changes of
+// a corpus on pull requests. The entries of files by the same name in
+// both corpora are "changed", with their "old" and "new" records.
+//
+// The merge command copies the entries of one or more corpora into the
+// first directory given, creating it, if necessary, naming each file as
+// the Go toolchain does, by the SHA-256 hash of its contents, so that an
+// entry in several of them is copied just once, e.g.:
+//
+//	$ fuzzdump merge ./testdata/fuzz/FuzzMyFunc ./ci/FuzzMyFunc ./oss-fuzz/FuzzMyFunc
+//
+// The invalid entries, and those with a different number of arguments
+// than those already in the destination, are not copied, but reported
+// by the index of their source, as in "src1/582528ddfad69eb5".
+//
+// The entries with the same values, but encoded differently, e.g. int(1)
+// and int(0x1), are all copied, unless -strategy is prefer-existing, to
+// keep those already in the destination, or else the first copied, or
+// prefer-newer, to keep the one modified last, replacing the others.
+//
+// The dedupe command removes the entries of a corpus that have the same
+// values as another, even if encoded differently, keeping the first of
+// them by name. Given -n, it lists them instead, e.g.:
+//
+//	$ fuzzdump dedupe -n ./fuzz/FuzzMyFunc
+//	d4e5	duplicates a1b2
+//	1 duplicate entries
+//
+// The cluster command groups the entries of a corpus by the similarity
+// of their contents, and reports how many entries each cluster has,
+// with a few of them as examples, largest first, e.g.:
+//
+//	$ fuzzdump cluster ./fuzz/FuzzMyFunc
+//	10240 entries, 31 clusters
+//	cluster 1: 4096 entries, e.g. 0a1b2c3d4e5f6071, 1b2c3d4e5f607182, 2c3d4e5f60718293
+//
+// The entries are compared by the simhashes of the 3-byte sequences of
+// their string and []byte values (and the other values, as encoded),
+// which differ in few bits for similar contents. An entry joins the
+// first cluster whose first entry has a simhash differing from its own
+// in at most -max-distance bits (12 by default), or starts a new one.
+//
+// The show command dumps the single entry whose file name, or content
+// hash (the name Go would give the file), starts with a given prefix,
+// such as when a test fails on a seed reported by its file name, e.g.:
+//
+//	$ fuzzdump show ./fuzz/FuzzMyFunc 582528ddfad69eb5
+//
+// With -index n, it shows the entry at index n (from 0) of those sorted
+// by file name instead. With -extract-to path, it copies the entry file
+// to path (or into it, if that is a directory), e.g., to reproduce a
+// failure with a minimal corpus:
+//
+//	$ fuzzdump show -index 5 -extract-to ./repro/testdata/fuzz/FuzzMyFunc ./fuzz/FuzzMyFunc
+//
+// The find command lists the names of the entry files with arguments
+// equal to exact typed values, given as -equals argN=value, repeated for
+// each, all of which must match, as decoded, so that uint(42) matches
+// uint(0x2a), but not int(42). With -extract-to path, it also copies the
+// entry files found into the directory path, e.g.:
+//
+//	$ fuzzdump find -equals 'arg0=uint(42)' -extract-to ./repro/FuzzMyFunc ./fuzz/FuzzMyFunc
+//
+// The tag command tags the entries with file names starting with the
+// given prefixes, so that curated entries can be grouped, and dumped or
+// converted by tag with -tags, e.g.:
+//
+//	$ fuzzdump tag ./fuzz/FuzzMyFunc regression-1234 582528dd
+//	$ fuzzdump -tags regression-1234 ./fuzz/FuzzMyFunc
+//
+// With -d, it removes the tag instead. Given just the directory, it
+// lists the tags of each entry. The tags are kept in a sidecar JSON file
+// next to the corpus directory, e.g. FuzzMyFunc.fuzzdump-tags.json, as
+// Go takes every file in the directory for a corpus entry.
+//
+// The coverage command runs the fuzz test named as a corpus directory
+// with each of its entries in turn, collecting cover profiles, and
+// reports the number of code blocks each entry covers, and how many of
+// those none of the others do, e.g.:
+//
+//	$ fuzzdump coverage ./testdata/fuzz/FuzzMyFunc
+//	582528ddfad69eb5	42 blocks, 3 unique
+//	a7f3c0de99d81f22	40 blocks, 0 unique
+//	1 of 2 entries contribute unique coverage, 45 blocks covered
+//
+// The package with the test is taken to be in the directory three
+// levels up from the corpus directory, unless given with -pkg. The
+// entries that the test fails with are reported and left out.
+//
+// The minimize command copies a subset of the entries of a corpus that
+// preserves all of its coverage, as measured by the coverage command, to
+// another directory, picking greedily the entry that adds the most
+// coverage next, e.g.:
+//
+//	$ fuzzdump minimize ./testdata/fuzz/FuzzMyFunc ./minimized/FuzzMyFunc
+//
+// Nothing is copied if the test fails with any of the entries.
+//
+// Given -read-only as the first argument, fuzzdump refuses to run the
+// commands that write to the file system (convert, dedupe, embed,
+// export, merge, minimize, mv, restore, snapshot and tag), or to dump
+// with the -o, -cpuprofile, or -memprofile flags, or to show or find
+// with -extract-to, e.g.:
+//
+//	$ fuzzdump -read-only -canonical /srv/corpora/FuzzMyFunc
+//
+// Given -report FILE before the command, fuzzdump writes a JSON report
+// of the run to FILE, whatever the command and its output format: the
+// operation, its inputs, the exit status code, and every warning and
+// error with its category and the corpus file it concerns, if any.
+//
 // Exit status codes:
 //
 //	0  success,
 //	1  some files were invalid, but others could be dumped,
 //	2  no valid corpus files were found,
-//	3  another critical error occurred.
+//	3  another critical error occurred,
+//	4  the check command found a threshold exceeded.
 package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/antichris/go-fuzzdump"
 )
@@ -57,40 +478,184 @@ var exit = os.Exit
 
 var shellIface = func(fn mainFn) shellIfaceFn {
 	return func(stdOut, stdErr io.Writer, args []string) (exitCode int) {
-		if err := fn(stdOut, args[1:]); err != nil {
+		if err := fn(stdOut, stdErr, args[1:]); err != nil {
 			fmt.Fprintln(stdErr, path.Base(args[0])+":", err)
-			switch {
-			case errors.Is(err, fuzzdump.ErrEmptyCorpus):
-				return ExitEmptyCorpus
-			case fuzzdump.IsValidationError(err):
-				return ExitSoft
-			default:
-				return ExitHard
+			return exitCodeFor(err)
+		}
+		return fuzzdump.ExitSuccess
+	}
+}
+
+// exitCodeFor returns the exit status code that reports err, as
+// [fuzzdump.ExitCodeFor] does, but with the values that convert -coerce
+// cannot convert taken for invalid entries.
+func exitCodeFor(err error) int {
+	code := fuzzdump.ExitCodeFor(err)
+	if code == fuzzdump.ExitHard && errors.Is(err, errCoerce) {
+		return fuzzdump.ExitSoft
+	}
+	return code
+}
+
+func realMain(stdOut, stdErr io.Writer, args []string) (err error) {
+	g, args, err := parseGlobalFlags(args)
+	if err != nil {
+		return
+	}
+	operation, run := "dump", dumpMain
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			operation, run, args = args[0], cmd, args[1:]
+		}
+	}
+	if g.report != "" {
+		defer func() {
+			r := newRunReport(operation, args, err)
+			if e := writeRunReport(g.report, r); err == nil {
+				err = e
 			}
+		}()
+	}
+	switch {
+	case !g.readOnly:
+	case mutating[operation]:
+		return fmt.Errorf("%w: %s", errReadOnly, operation)
+	case operation == "dump" && hasOutputFlag(args):
+		return fmt.Errorf("%w: -o", errReadOnly)
+	case hasFlag(args, "extract-to"):
+		return fmt.Errorf("%w: -extract-to", errReadOnly)
+	}
+	return run(stdOut, stdErr, args)
+}
+
+// globalFlags apply to whatever command is run.
+type globalFlags struct {
+	readOnly bool
+	report   string
+}
+
+// parseGlobalFlags parses the flags that precede the command (or the
+// flags of the dump), returning the rest of args.
+func parseGlobalFlags(args []string) (g globalFlags, rest []string, err error) {
+	for len(args) > 0 {
+		name := strings.TrimPrefix(strings.TrimPrefix(args[0], "-"), "-")
+		switch {
+		case name == "read-only":
+			g.readOnly, args = true, args[1:]
+		case name == "report" && len(args) > 1:
+			g.report, args = args[1], args[2:]
+		case strings.HasPrefix(name, "report="):
+			g.report, args = strings.TrimPrefix(name, "report="), args[1:]
+		case name == "report":
+			return g, nil, errReportArg
+		default:
+			return g, args, nil
+		}
+	}
+	return g, args, nil
+}
+
+// hasOutputFlag reports whether the dump command args include a flag
+// that makes it write files.
+func hasOutputFlag(args []string) bool {
+	return hasFlag(args, "o", "cpuprofile", "memprofile")
+}
+
+// hasFlag reports whether args include any of the named flags, ahead of
+// a "--" argument, if any.
+func hasFlag(args []string, names ...string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
 		}
-		return ExitSuccess
+		name := strings.TrimLeft(a, "-")
+		if i := strings.IndexByte(name, '='); i >= 0 {
+			name = name[:i]
+		}
+		for _, n := range names {
+			if name == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commands that can be given as the first argument.
+var commands = map[string]mainFn{
+	"check":    checkMain,
+	"cluster":  clusterMain,
+	"convert":  convertMain,
+	"coverage": coverageMain,
+	"dedupe":   dedupeMain,
+	"diff":     diffMain,
+	"embed":    embedMain,
+	"merge":    mergeMain,
+	"export":   exportMain,
+	"find":     findMain,
+	"minimize": minimizeMain,
+	"mv":       mvMain,
+	"restore":  restoreMain,
+	"show":     showMain,
+	"snapshot": snapshotMain,
+	"stats":    statsMain,
+	"tag":      tagMain,
+}
+
+// mutating commands, which write to the file system, refused with
+// -read-only.
+var mutating = map[string]bool{
+	"convert":  true,
+	"dedupe":   true,
+	"embed":    true,
+	"merge":    true,
+	"export":   true,
+	"minimize": true,
+	"mv":       true,
+	"restore":  true,
+	"snapshot": true,
+	"tag":      true,
+}
+
+const cmdName = "fuzzdump"
+
+// newFlagSet returns a flag set for the named subcommand (or the main
+// command, if the name is empty) that leaves reporting errors to the
+// caller.
+func newFlagSet(name string) *flag.FlagSet {
+	if name != "" {
+		name = cmdName + " " + name
+	} else {
+		name = cmdName
 	}
+	fl := flag.NewFlagSet(name, flag.ContinueOnError)
+	fl.SetOutput(io.Discard)
+	return fl
 }
 
-func realMain(w io.Writer, args []string) error {
-	if len(args) == 0 || len(args[0]) == 0 {
-		return errNoDirArg
+// parseFlags parses args with fl. When help is requested, it prints the
+// flag defaults to w and reports that nothing else should be done.
+func parseFlags(fl *flag.FlagSet, w io.Writer, args []string) (done bool, err error) {
+	if err = fl.Parse(args); errors.Is(err, flag.ErrHelp) {
+		fl.SetOutput(w)
+		fl.PrintDefaults()
+		return true, nil
 	}
-	return fuzzdump.DumpDir(w, os.DirFS(args[0]), ".")
+	return err != nil, err
 }
 
 type (
 	// A shellIfaceFn takes command line arguments and standard output
 	// and error streams as [io.Writer]'s, and returns an exit code.
 	shellIfaceFn func(stdOut, stdErr io.Writer, args []string) (exitCode int)
-	mainFn       func(w io.Writer, args []string) error
+	// A mainFn takes command line arguments (sans the program name) and
+	// standard output and error streams as [io.Writer]'s, and returns an
+	// error, if any.
+	mainFn func(stdOut, stdErr io.Writer, args []string) error
 )
 
-const (
-	ExitSuccess = iota
-	ExitSoft
-	ExitEmptyCorpus
-	ExitHard
+var (
+	errNoDirArg  = errors.New("directory path argument required")
+	errReadOnly  = errors.New("refused to write in read-only mode")
+	errReportArg = errors.New("-report requires a file path")
 )
-
-var errNoDirArg = errors.New("directory path argument required")
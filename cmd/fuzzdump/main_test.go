@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/antichris/go-fuzzdump"
@@ -48,8 +49,9 @@ func Test_shellIface(t *testing.T) {
 		stdErr = &bytes.Buffer{}
 		args   = []string{"foo/bar", "qux"}
 
-		wWriter = stdOut
-		wArgs   = args[1:]
+		wWriter    = stdOut
+		wErrWriter = stdErr
+		wArgs      = args[1:]
 	)
 	type test struct {
 		err   error
@@ -67,22 +69,22 @@ func Test_shellIface(t *testing.T) {
 	tests := map[string]test{
 		"empty corpus": errorCase(
 			fuzzdump.ErrEmptyCorpus,
-			ExitEmptyCorpus,
+			fuzzdump.ExitEmptyCorpus,
 		), "malformed corpus": errorCase(
 			fuzzdump.ErrUnsupportedVersion,
-			ExitSoft,
+			fuzzdump.ExitSoft,
 		), "no valid files": errorCase(
 			fuzzdump.CorpusErrors{
 				fuzzdump.ErrMalformedEntry,
 				fuzzdump.ErrEmptyCorpus,
 			},
-			ExitEmptyCorpus,
+			fuzzdump.ExitEmptyCorpus,
 		), "critical error": errorCase(
 			errSnap,
-			ExitHard,
+			fuzzdump.ExitHard,
 		), "nominal": {
 			wOut:  outStr,
-			wCode: ExitSuccess,
+			wCode: fuzzdump.ExitSuccess,
 		},
 	}
 	for n, tt := range tests {
@@ -91,12 +93,12 @@ func Test_shellIface(t *testing.T) {
 			stdErr.Reset()
 
 			m := newMock(t)
-			mockMain := func(w io.Writer, args []string) error {
-				r := m.MethodCalled("mockMain", w, args)
+			mockMain := func(w, e io.Writer, args []string) error {
+				r := m.MethodCalled("mockMain", w, e, args)
 				fmt.Fprint(w, outStr)
 				return r.Error(0)
 			}
-			m.On("mockMain", wWriter, wArgs).Return(tt.err)
+			m.On("mockMain", wWriter, wErrWriter, wArgs).Return(tt.err)
 
 			gotMain := shellIface(mockMain)
 			gotCode := gotMain(stdOut, stdErr, args)
@@ -118,6 +120,7 @@ func Test_shellIface(t *testing.T) {
 
 func Test_realMain(t *testing.T) {
 	stdOut := &bytes.Buffer{}
+	dir := writeCorpus(t, map[string]string{"a": "int(1)"})
 
 	tests := map[string]struct {
 		args []string
@@ -131,10 +134,35 @@ func Test_realMain(t *testing.T) {
 	}, "err from dump": {
 		args: []string{"."},
 		wErr: fuzzdump.ErrEmptyCorpus,
+	}, "command": {
+		args: []string{"embed"},
+		wErr: errNoDirArg,
+	}, "read-only dump": {
+		args: []string{"-read-only", dir},
+		wOut: "{\n\tint(1),\n}\n",
+	}, "read-only reading command": {
+		args: []string{"--read-only", "show", dir, "a"},
+		wOut: "{\n\tint(1),\n}\n",
+	}, "read-only mutating command": {
+		args: []string{"-read-only", "tag", dir, "slow", "a"},
+		wErr: errReadOnly,
+	}, "read-only show extraction": {
+		args: []string{"-read-only", "show", "-extract-to", filepath.Join(dir, "out"), dir, "a"},
+		wErr: errReadOnly,
+	}, "read-only find extraction": {
+		args: []string{"-read-only", "find", "-extract-to", filepath.Join(dir, "out"), dir},
+		wErr: errReadOnly,
+	}, "read-only output file": {
+		args: []string{"-read-only", "-canonical", "-o=" + filepath.Join(dir, "out"), dir},
+		wErr: errReadOnly,
+	}, "read-only profile": {
+		args: []string{"-read-only", "-j", "2", "-cpuprofile", "cpu.out", dir},
+		wErr: errReadOnly,
 	}}
 	for n, tt := range tests {
 		t.Run(n, func(t *testing.T) {
-			err := realMain(stdOut, tt.args)
+			stdOut.Reset()
+			err := realMain(stdOut, io.Discard, tt.args)
 			req := require.New(t)
 			if tt.wErr != nil {
 				req.ErrorIs(err, tt.wErr)
@@ -146,6 +174,20 @@ func Test_realMain(t *testing.T) {
 	}
 }
 
+// writeCorpus creates a temporary corpus directory with files named
+// after the keys of values, holding the respective value lines.
+func writeCorpus(t *testing.T, values map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, value := range values {
+		data := []byte("go test fuzz v1\n" + value + "\n")
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
 var errSnap = errors.New(snap)
 
 const snap = "snap"
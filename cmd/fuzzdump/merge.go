@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpusdir"
+)
+
+// mergeMain copies the entries of several fuzz test corpus directories
+// into one, e.g., to collect the seeds found by fuzzing in CI and local
+// runs.
+func mergeMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("merge")
+	strategy := fl.String("strategy", strategyKeepBoth,
+		"`strategy` for entries with the same values, but encoded differently: "+
+			strings.Join(sortedKeys(mergeStrategies), ", "))
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) < 2 {
+		return errMergeArgs
+	}
+	s, ok := mergeStrategies[*strategy]
+	if !ok {
+		return fmt.Errorf("%w: %q", errBadStrategy, *strategy)
+	}
+	dst, srcs := args[0], make([]fs.FS, len(args)-1)
+	for i, src := range args[1:] {
+		if src == "" {
+			return errMergeArgs
+		}
+		if _, err := os.Stat(src); err != nil {
+			return err
+		}
+		if err := checkUnlocked(src); err != nil {
+			return err
+		}
+		srcs[i] = os.DirFS(src)
+	}
+	unlock, err := lockCorpus(dst)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return corpusdir.MergeWith(wfs, dst, s, srcs...)
+}
+
+// Merge strategies.
+const (
+	strategyKeepBoth       = "keep-both"
+	strategyPreferExisting = "prefer-existing"
+	strategyPreferNewer    = "prefer-newer"
+)
+
+var mergeStrategies = map[string]corpusdir.Strategy{
+	strategyKeepBoth:       corpusdir.KeepBoth,
+	strategyPreferExisting: corpusdir.PreferExisting,
+	strategyPreferNewer:    corpusdir.PreferNewer,
+}
+
+var (
+	errMergeArgs   = errors.New("destination and at least one source directory arguments required")
+	errBadStrategy = errors.New("unsupported merge strategy")
+)
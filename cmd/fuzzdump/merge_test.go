@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mergeMain(t *testing.T) {
+	const header = "go test fuzz v1\n"
+	name := func(v string) string { return corpus.FileName([]byte(header + v + "\n")) }
+	tests := map[string]struct {
+		args []string
+		srcs []map[string]string
+		want []string
+		wErr error
+	}{"nominal": {
+		srcs: []map[string]string{
+			{"ci": "int(1)", "ci-2": "int(2)"},
+			{"oss-fuzz": "int(2)", "local": "int(3)"},
+		},
+		want: []string{name("int(1)"), name("int(2)"), name("int(3)")},
+	}, "invalid": {
+		srcs: []map[string]string{{"1": "int(1)", "2": "int("}},
+		want: []string{name("int(1)")},
+		wErr: fuzzdump.ErrMalformedValue,
+	}, "keep both": {
+		args: []string{"-strategy", "keep-both"},
+		srcs: []map[string]string{{"ci": "int(1)"}, {"local": "int(0x1)"}},
+		want: []string{name("int(1)"), name("int(0x1)")},
+	}, "prefer existing": {
+		args: []string{"-strategy", "prefer-existing"},
+		srcs: []map[string]string{{"ci": "int(1)"}, {"local": "int(0x1)"}},
+		want: []string{name("int(1)")},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "FuzzFoo")
+			args := append(tt.args, dst)
+			for _, src := range tt.srcs {
+				args = append(args, writeCorpus(t, src))
+			}
+			err := mergeMain(io.Discard, io.Discard, args)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			entries, err := os.ReadDir(dst)
+			req.NoError(err)
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			req.ElementsMatch(tt.want, names)
+		})
+	}
+	t.Run("missing source", func(t *testing.T) {
+		dir := t.TempDir()
+		err := mergeMain(io.Discard, io.Discard, []string{filepath.Join(dir, "dst"), filepath.Join(dir, "absent")})
+		req := require.New(t)
+		req.ErrorIs(err, fs.ErrNotExist)
+		req.NoDirExists(filepath.Join(dir, "dst"))
+	})
+	t.Run("locked", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "FuzzFoo")
+		unlock, err := lockCorpus(dst)
+		req := require.New(t)
+		req.NoError(err)
+		defer unlock()
+		err = mergeMain(io.Discard, io.Discard, []string{dst, writeCorpus(t, map[string]string{"1": "int(1)"})})
+		req.ErrorIs(err, errLocked)
+		req.NoDirExists(dst)
+	})
+	t.Run("prefer newer", func(t *testing.T) {
+		dst := writeCorpus(t, map[string]string{name("int(1)"): "int(1)"})
+		src := writeCorpus(t, map[string]string{"local": "int(0x1)"})
+		mtime := time.Now().Add(time.Hour).Truncate(time.Second)
+		req := require.New(t)
+		req.NoError(os.Chtimes(filepath.Join(src, "local"), mtime, mtime))
+		req.NoError(mergeMain(io.Discard, io.Discard, []string{"-strategy", "prefer-newer", dst, src}))
+		req.NoFileExists(filepath.Join(dst, name("int(1)")))
+		fi, err := os.Stat(filepath.Join(dst, name("int(0x1)")))
+		req.NoError(err)
+		req.True(mtime.Equal(fi.ModTime()), "the modification time kept")
+	})
+	t.Run("strategy", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "FuzzFoo")
+		src := writeCorpus(t, map[string]string{"1": "int(1)"})
+		err := mergeMain(io.Discard, io.Discard, []string{"-strategy", "newest", dst, src})
+		req := require.New(t)
+		req.ErrorIs(err, errBadStrategy)
+		req.NoDirExists(dst)
+	})
+	t.Run("args", func(t *testing.T) {
+		require.ErrorIs(t, mergeMain(io.Discard, io.Discard, []string{"dst"}), errMergeArgs)
+	})
+}
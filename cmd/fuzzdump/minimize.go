@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// minimizeMain copies a subset of the entries of a fuzz test corpus
+// directory to another one that preserves all of the code coverage of
+// the whole corpus.
+func minimizeMain(w, stdErr io.Writer, args []string) error {
+	fl := newFlagSet("minimize")
+	pkg := fl.String("pkg", "",
+		"the `dir`ectory of the package with the fuzz test (default DIR/../../..)")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return errMinimizeArgs
+	}
+	src, dst := args[0], args[1]
+	if same, err := samePath(src, dst); err != nil || same {
+		if same {
+			err = errMvSame
+		}
+		return err
+	}
+	unlock, err := lockCorpus(dst)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// Nothing is copied if the test fails with any entry, lest it is lost.
+	c, err := measureCoverage(stdErr, src, *pkg)
+	if err != nil {
+		return err
+	}
+	names := c.minimize()
+	files := make([]corpus.File, len(names))
+	for i, name := range names {
+		files[i].Name = name
+	}
+	if err := copyFiles(src, dst, files); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "kept %d of %d entries, %d blocks covered\n",
+		len(names), len(c), c.total())
+	return err
+}
+
+// minimize returns the names of a small subset of the entries in c that
+// together cover all the blocks that any of them do, sorted.
+//
+// The subset is picked greedily: the entry that covers the most blocks
+// not yet covered by those picked before is picked next, the first by
+// name on a tie, until no entry adds any coverage.
+func (c coverage) minimize() (picked []string) {
+	names := sortedKeys(c)
+	covered := coverSet{}
+	for {
+		best, gain := "", 0
+		for _, name := range names {
+			n := 0
+			for b := range c[name] {
+				if !covered[b] {
+					n++
+				}
+			}
+			if n > gain {
+				best, gain = name, n
+			}
+		}
+		if gain == 0 {
+			break
+		}
+		picked = append(picked, best)
+		for b := range c[best] {
+			covered[b] = true
+		}
+	}
+	sort.Strings(picked)
+	return
+}
+
+var errMinimizeArgs = errors.New("source and destination directory arguments required")
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_coverage_minimize(t *testing.T) {
+	tests := map[string]struct {
+		c    coverage
+		want []string
+	}{"empty": {
+		c: coverage{},
+	}, "no coverage": {
+		c: coverage{"a": {}},
+	}, "redundant": {
+		c: coverage{
+			"a": {"1": true, "2": true},
+			"b": {"1": true, "2": true},
+		},
+		want: []string{"a"},
+	}, "greedy": {
+		c: coverage{
+			"a": {"1": true, "2": true, "3": true},
+			"b": {"1": true, "4": true},
+			"c": {"2": true, "3": true},
+			"d": {"4": true},
+		},
+		want: []string{"a", "b"},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.c.minimize())
+		})
+	}
+}
+
+func Test_minimizeMain(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"a": "int(1)", "b": "int(2)", "c": "int(3)"})
+	fakeGoTest(t, func(string, ...string) error { return nil }, map[string]string{
+		"a": "f.go:1.1,2.2 1 1\nf.go:3.1,4.2 1 0\n",
+		"b": "f.go:1.1,2.2 1 1\nf.go:3.1,4.2 1 0\n",
+		"c": "f.go:1.1,2.2 1 0\nf.go:3.1,4.2 1 1\n",
+	})
+	dst := filepath.Join(t.TempDir(), "dst")
+	stdOut := &bytes.Buffer{}
+	req := require.New(t)
+	req.NoError(minimizeMain(stdOut, io.Discard, []string{"-pkg", t.TempDir(), src, dst}))
+	req.Equal("kept 2 of 3 entries, 2 blocks covered\n", stdOut.String())
+	entries, err := os.ReadDir(dst)
+	req.NoError(err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	req.Equal([]string{"a", "c"}, names)
+}
+
+func Test_minimizeMain_errors(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"a": "int(1)", "crash": "int(2)"})
+	fakeGoTest(t, func(_ string, args ...string) error {
+		if strings.Contains(args[0], "crash") {
+			return errSnap
+		}
+		return nil
+	}, map[string]string{"a": "f.go:1.1,2.2 1 1\n"})
+	tests := map[string]struct {
+		args []string
+		wErr error
+	}{"no args": {
+		wErr: errMinimizeArgs,
+	}, "same dir": {
+		args: []string{src, src},
+		wErr: errMvSame,
+	}, "failed entry": {
+		args: []string{"-pkg", t.TempDir(), src, filepath.Join(t.TempDir(), "dst")},
+		wErr: errEntriesFailed,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := minimizeMain(io.Discard, io.Discard, tt.args)
+			require.ErrorIs(t, err, tt.wErr)
+			if len(tt.args) == 4 {
+				_, err := os.Stat(tt.args[3])
+				require.ErrorIs(t, err, os.ErrNotExist, "nothing copied")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// dumpDirs dumps each of the dirs with dump, running up to jobs of them
+// concurrently. The outputs are written to w in the order of dirs, each
+// in its own section headed by a comment naming the directory.
+//
+// The errors of the individual directories are reported to stdErr as
+// they are written. The returned error, if any, wraps the one that
+// warrants the highest exit status code.
+func dumpDirs(w, stdErr io.Writer, dirs []string, jobs int, dump func(w io.Writer, dir string) error) (err error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	type result struct {
+		out  bytes.Buffer
+		err  error
+		done chan struct{}
+	}
+	results := make([]result, len(dirs))
+	sem := make(chan struct{}, jobs)
+	for i, dir := range dirs {
+		r := &results[i]
+		r.done = make(chan struct{})
+		go func(dir string) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			defer close(r.done)
+			r.err = dump(&r.out, dir)
+		}(dir)
+	}
+	e := &dirsError{total: len(dirs)}
+	for i, dir := range dirs {
+		r := &results[i]
+		<-r.done
+		if err == nil {
+			err = writeSection(w, i == 0, dir, &r.out)
+		}
+		if r.err == nil {
+			continue
+		}
+		fmt.Fprintf(stdErr, "%s: %s: %v\n", cmdName, dir, r.err)
+		e.failed++
+		e.errs = append(e.errs, dirError{dir, r.err})
+		if exitCodeFor(r.err) > exitCodeFor(e.worst) {
+			e.worst = r.err
+		}
+	}
+	if err != nil || e.failed == 0 {
+		return
+	}
+	return e
+}
+
+// findCorpora returns the paths of the fuzz test corpus directories in
+// the trees under the roots, in the order of the roots, each found as
+// [fuzzdump.FindCorpora] finds them.
+func findCorpora(roots []string) (dirs []string, err error) {
+	for _, root := range roots {
+		found, err := fuzzdump.FindCorpora(os.DirFS(root), ".")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", root, err)
+		}
+		for _, d := range found {
+			dirs = append(dirs, filepath.Join(root, filepath.FromSlash(d)))
+		}
+	}
+	return
+}
+
+// writeSection writes the dump of dir in out to w, headed by a comment
+// naming dir, and preceded by an empty line, unless it is the first.
+func writeSection(w io.Writer, first bool, dir string, out io.WriterTo) (err error) {
+	sep := "\n"
+	if first {
+		sep = ""
+	}
+	if _, err = fmt.Fprintf(w, "%s// %s\n", sep, dir); err != nil {
+		return
+	}
+	_, err = out.WriteTo(w)
+	return
+}
+
+// dirsError reports that some of the corpus directories dumped together
+// failed. It wraps the error that warrants the highest exit status code.
+type dirsError struct {
+	failed, total int
+	worst         error
+	errs          []dirError // Of all the directories that failed.
+}
+
+// dirError is the error of a corpus directory dumped with others.
+type dirError struct {
+	dir string
+	err error
+}
+
+func (e *dirsError) Error() string {
+	return fmt.Sprintf("%d of %d corpus directories failed", e.failed, e.total)
+}
+
+func (e *dirsError) Unwrap() error { return e.worst }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dumpDirs(t *testing.T) {
+	dirs := []string{"a", "b", "c"}
+	tests := map[string]struct {
+		errs     map[string]error
+		wOut     string
+		wStdErr  string
+		wErrText string
+		wCode    int
+	}{"nominal": {
+		wOut:  "// a\n{a}\n\n// b\n{b}\n\n// c\n{c}\n",
+		wCode: fuzzdump.ExitSuccess,
+	}, "failures": {
+		errs: map[string]error{
+			"a": fuzzdump.ErrMalformedEntry,
+			"c": fuzzdump.ErrEmptyCorpus,
+		},
+		wOut: "// a\n{a}\n\n// b\n{b}\n\n// c\n{c}\n",
+		wStdErr: "fuzzdump: a: " + fuzzdump.ErrMalformedEntry.Error() + "\n" +
+			"fuzzdump: c: " + fuzzdump.ErrEmptyCorpus.Error() + "\n",
+		wErrText: "2 of 3 corpus directories failed",
+		wCode:    fuzzdump.ExitEmptyCorpus,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w, stdErr := &bytes.Buffer{}, &bytes.Buffer{}
+			err := dumpDirs(w, stdErr, dirs, 2, func(w io.Writer, dir string) error {
+				fmt.Fprintf(w, "{%s}\n", dir)
+				return tt.errs[dir]
+			})
+			req := require.New(t)
+			if tt.wErrText != "" {
+				req.EqualError(err, tt.wErrText)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wCode, exitCodeFor(err))
+			req.Equal(tt.wOut, w.String())
+			req.Equal(tt.wStdErr, stdErr.String())
+		})
+	}
+}
+
+func Test_dumpDirs_jobs(t *testing.T) {
+	const jobs = 2
+	var running, peak int32
+	dirs := make([]string, 8)
+	for i := range dirs {
+		dirs[i] = fmt.Sprint(i)
+	}
+	err := dumpDirs(io.Discard, io.Discard, dirs, jobs, func(io.Writer, string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	req := require.New(t)
+	req.NoError(err)
+	req.LessOrEqual(peak, int32(jobs))
+}
+
+func Test_dumpDirs_writeErr(t *testing.T) {
+	err := dumpDirs(errWriter{}, io.Discard, []string{"a", "b"}, 0,
+		func(io.Writer, string) error { return nil })
+	require.ErrorIs(t, err, errSnap)
+}
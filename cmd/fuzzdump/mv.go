@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/corpusdir"
+)
+
+// mvMain moves (or copies) a fuzz test corpus directory to another
+// path, as when a fuzz function is renamed.
+func mvMain(w, _ io.Writer, args []string) (err error) {
+	fl := newFlagSet("mv")
+	var (
+		signature = fl.String("signature", "",
+			"verify that entries have arguments of the comma-separated `types`, e.g. \"int,string\"")
+		keep = fl.Bool("copy", false,
+			"copy the corpus, keeping the source directory")
+	)
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return errMvArgs
+	}
+	src, dst := args[0], args[1]
+	if same, err := samePath(src, dst); err != nil || same {
+		if same {
+			err = errMvSame
+		}
+		return err
+	}
+	unlock, err := lockCorpora(src, dst)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	var sig []string
+	if *signature != "" {
+		sig = parseSignature(*signature)
+	}
+	// Nothing is moved unless the whole corpus is valid.
+	files, err := readV1Dir(src)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if err = checkSignature(f.Entry, sig); err != nil {
+			return &fuzzdump.FileError{Name: f.Name, Err: err}
+		}
+	}
+	if err = copyFiles(src, dst, files); err != nil || *keep {
+		return
+	}
+	for _, f := range files {
+		if err = wfs.Remove(filepath.Join(src, f.Name)); err != nil {
+			return
+		}
+	}
+	// Anything else there, such as a subdirectory, is left in place.
+	if err = wfs.Remove(src); err != nil && !isNotEmpty(src) {
+		return
+	}
+	return nil
+}
+
+// samePath reports whether a and b are the same path.
+func samePath(a, b string) (bool, error) {
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	b, err = filepath.Abs(b)
+	return a == b, err
+}
+
+// parseSignature returns the argument types listed in s, separated by
+// commas, with the byte and rune aliases resolved.
+func parseSignature(s string) []string {
+	sig := strings.Split(s, ",")
+	for i, t := range sig {
+		switch t = strings.TrimSpace(t); t {
+		case "byte":
+			t = "uint8"
+		case "rune":
+			t = "int32"
+		case "[]uint8":
+			t = "[]byte"
+		}
+		sig[i] = t
+	}
+	return sig
+}
+
+// checkSignature returns an error if the values of e do not have the
+// argument types in sig (if there are any).
+func checkSignature(e corpus.Entry, sig []string) error {
+	vals, err := e.Values()
+	if err != nil || sig == nil {
+		return err
+	}
+	types := valueTypes(vals)
+	if strings.Join(types, ",") != strings.Join(sig, ",") {
+		return fmt.Errorf("%w: want (%s), got (%s)", errSignature,
+			strings.Join(sig, ", "), strings.Join(types, ", "))
+	}
+	return nil
+}
+
+// valueTypes returns the type names of vals, as they would be given in
+// a signature.
+func valueTypes(vals []corpus.Value) []string {
+	types := make([]string, len(vals))
+	for i, v := range vals {
+		types[i] = fmt.Sprintf("%T", v)
+		if types[i] == "[]uint8" {
+			types[i] = "[]byte"
+		}
+	}
+	return types
+}
+
+// copyFiles with the given names from the src to the dst directory,
+// creating it, if necessary. A file in dst may only be overwritten with
+// the same contents. Nothing is copied unless all of them can be, see
+// [writeDir].
+func copyFiles(src, dst string, files []corpus.File) error {
+	return writeDir(dst, func(stage *corpusdir.Stage) error {
+		for _, f := range files {
+			data, err := os.ReadFile(filepath.Join(src, f.Name))
+			if err != nil {
+				return err
+			}
+			name := filepath.Join(dst, f.Name)
+			switch old, err := os.ReadFile(name); {
+			case err == nil && !bytes.Equal(old, data):
+				return fmt.Errorf("%w: %q", errMvConflict, name)
+			case err != nil && !errors.Is(err, fs.ErrNotExist):
+				return err
+			}
+			if err := stage.WriteFile(f.Name, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// isNotEmpty reports whether dir has any entries left.
+func isNotEmpty(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+var (
+	errMvArgs     = errors.New("source and destination directory arguments required")
+	errMvSame     = errors.New("source and destination are the same directory")
+	errMvConflict = errors.New("a different entry file already exists")
+	errSignature  = errors.New("entry does not match the signature")
+)
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mvMain(t *testing.T) {
+	values := map[string]string{
+		"1": "int(1)\nstring(\"a\")",
+		"2": "int(2)\nstring(\"b\")",
+	}
+	tests := map[string]struct {
+		flags    []string
+		values   map[string]string
+		dst      map[string]string
+		wErr     error
+		wErrText string
+		wKept    bool
+	}{"nominal": {
+		values: values,
+	}, "copy": {
+		flags:  []string{"-copy"},
+		values: values,
+		wKept:  true,
+	}, "signature": {
+		flags:  []string{"-signature", "int, string"},
+		values: values,
+	}, "signature mismatch": {
+		flags:    []string{"-signature", "int,[]byte"},
+		values:   values,
+		wErrText: `reading "1": ` + errSignature.Error() + ": want (int, []byte), got (int, string)",
+		wKept:    true,
+	}, "malformed value": {
+		values: map[string]string{"1": "int(1)", "2": "int("},
+		wErr:   fuzzdump.ErrMalformedValue,
+		wKept:  true,
+	}, "malformed entry": {
+		values: map[string]string{"1": "int(1)", "2": ""},
+		wErr:   fuzzdump.ErrMalformedEntry,
+		wKept:  true,
+	}, "same entry in destination": {
+		values: values,
+		dst:    map[string]string{"1": values["1"]},
+	}, "conflict": {
+		values: values,
+		dst:    map[string]string{"2": "int(3)\nstring(\"c\")"},
+		wErr:   errMvConflict,
+		wKept:  true,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			src := writeCorpus(t, tt.values)
+			dst := filepath.Join(t.TempDir(), "FuzzNew")
+			if tt.dst != nil {
+				dst = writeCorpus(t, tt.dst)
+			}
+			args := append(tt.flags, src, dst)
+			err := mvMain(io.Discard, io.Discard, args)
+			req := require.New(t)
+			switch {
+			case tt.wErr != nil:
+				req.ErrorIs(err, tt.wErr)
+			case tt.wErrText != "":
+				req.EqualError(err, tt.wErrText)
+			default:
+				req.NoError(err)
+				for name, v := range tt.values {
+					b, err := os.ReadFile(filepath.Join(dst, name))
+					req.NoError(err)
+					req.Equal("go test fuzz v1\n"+v+"\n", string(b))
+				}
+			}
+			_, err = os.Stat(src)
+			if tt.wKept {
+				req.NoError(err)
+				entries, err := os.ReadDir(src)
+				req.NoError(err)
+				req.Len(entries, len(tt.values))
+			} else {
+				req.ErrorIs(err, os.ErrNotExist)
+			}
+		})
+	}
+}
+
+func Test_mvMain_args(t *testing.T) {
+	dir := corpusDir(t)
+	tests := map[string]struct {
+		args []string
+		wErr error
+	}{
+		"none":   {wErr: errMvArgs},
+		"empty":  {args: []string{dir, ""}, wErr: errMvArgs},
+		"same":   {args: []string{dir, dir + string(filepath.Separator)}, wErr: errMvSame},
+		"absent": {args: []string{filepath.Join(dir, "absent"), dir + "2"}, wErr: os.ErrNotExist},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := mvMain(io.Discard, io.Discard, tt.args)
+			require.ErrorIs(t, err, tt.wErr)
+		})
+	}
+}
+
+func Test_mvMain_leftovers(t *testing.T) {
+	src := corpusDir(t)
+	req := require.New(t)
+	req.NoError(os.Mkdir(filepath.Join(src, "sub"), 0o755))
+	dst := filepath.Join(t.TempDir(), "FuzzNew")
+	req.NoError(mvMain(io.Discard, io.Discard, []string{src, dst}))
+	entries, err := os.ReadDir(src)
+	req.NoError(err)
+	req.Len(entries, 1)
+	req.Equal("sub", entries[0].Name())
+}
+
+func Test_parseSignature(t *testing.T) {
+	require.Equal(t, []string{"uint8", "int32", "[]byte", "string"},
+		parseSignature("byte, rune,[]uint8 , string"))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpusdir"
+)
+
+// wfs is the file system that the commands write to. The advisory locks
+// of corpora are taken on the local one regardless, see [lockCorpus], as
+// that needs a file created exclusively.
+var wfs corpusdir.FS = corpusdir.OS
+
+// writeFile with the given name with the output of fn.
+//
+// The output is written to a temporary file first, which only replaces
+// the named file when fn produced something worth keeping: when fn
+// succeeded, or when only some of the corpus entries were invalid.
+// Otherwise the named file is left untouched.
+func writeFile(name string, fn func(w io.Writer) error) (err error) {
+	tmp, err := tempName(name)
+	if err != nil {
+		return
+	}
+	f, err := wfs.Create(tmp)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if f != nil {
+			f.Close()
+			wfs.Remove(tmp)
+		}
+	}()
+	err = fn(f)
+	if exitCodeFor(err) > fuzzdump.ExitSoft {
+		return
+	}
+	e := f.Close()
+	f = nil
+	if e == nil {
+		e = wfs.Rename(tmp, name)
+	}
+	if e != nil {
+		wfs.Remove(tmp)
+		return e
+	}
+	return
+}
+
+// writeDir writes files to the dst directory, creating it, if necessary,
+// with fn, as a single transaction, see [corpusdir.WriteStaged].
+func writeDir(dst string, fn func(stage *corpusdir.Stage) error) error {
+	return corpusdir.WriteStaged(wfs, dst, fn)
+}
+
+// tempName returns a name for a temporary file or directory next to the
+// named one, hidden, and unlikely to be taken.
+func tempName(name string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	dir, base := filepath.Split(name)
+	return filepath.Join(dir, "."+base+"."+hex.EncodeToString(b)), nil
+}
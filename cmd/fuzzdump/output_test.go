@@ -0,0 +1,189 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeFile(t *testing.T) {
+	const (
+		old = "old"
+		out = "new"
+	)
+	tests := map[string]struct {
+		err   error
+		wData string
+	}{"nominal": {
+		wData: out,
+	}, "soft error": {
+		err:   fuzzdump.ErrMalformedEntry,
+		wData: out,
+	}, "empty corpus": {
+		err:   fuzzdump.ErrEmptyCorpus,
+		wData: old,
+	}, "hard error": {
+		err:   errSnap,
+		wData: old,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dir := t.TempDir()
+			name := filepath.Join(dir, "out")
+			req := require.New(t)
+			req.NoError(os.WriteFile(name, []byte(old), 0o644))
+
+			err := writeFile(name, func(w io.Writer) error {
+				io.WriteString(w, out)
+				return tt.err
+			})
+			req.ErrorIs(err, tt.err)
+			b, rErr := os.ReadFile(name)
+			req.NoError(rErr)
+			req.Equal(tt.wData, string(b))
+
+			// No temporary files must be left behind.
+			files, rErr := os.ReadDir(dir)
+			req.NoError(rErr)
+			req.Len(files, 1)
+		})
+	}
+	t.Run("bad dir", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "absent", "out")
+		err := writeFile(name, func(w io.Writer) error { return nil })
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func Test_writeDir(t *testing.T) {
+	write := func(names ...string) func(stage *corpusdir.Stage) error {
+		return func(stage *corpusdir.Stage) error {
+			for _, name := range names {
+				if err := stage.WriteFile(name, []byte(name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	failing := func(stage *corpusdir.Stage) error {
+		if err := write("a")(stage); err != nil {
+			return err
+		}
+		return errSnap
+	}
+	tests := map[string]struct {
+		setup func(t *testing.T, dst string)
+		fn    func(stage *corpusdir.Stage) error
+		wErr  error
+		// Whether any error is expected, as it varies between platforms.
+		wAnyErr bool
+		wNames  []string
+	}{"new dir": {
+		fn:     write("a", "b"),
+		wNames: []string{"a", "b"},
+	}, "existing dir": {
+		setup:  setupDir("old"),
+		fn:     write("a", "b"),
+		wNames: []string{"a", "b", "old"},
+	}, "failed, new dir": {
+		fn:   failing,
+		wErr: errSnap,
+	}, "failed, existing dir": {
+		setup:  setupDir("old"),
+		fn:     failing,
+		wErr:   errSnap,
+		wNames: []string{"old"},
+	}, "rolled back": {
+		setup: func(t *testing.T, dst string) {
+			// A non-empty directory cannot be replaced with a file.
+			require.NoError(t, os.MkdirAll(filepath.Join(dst, "b", "x"), 0o755))
+		},
+		fn:      write("a", "b"),
+		wAnyErr: true,
+		wNames:  []string{"b"},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			root := t.TempDir()
+			dst := filepath.Join(root, "dst")
+			if tt.setup != nil {
+				tt.setup(t, dst)
+			}
+			err := writeDir(dst, tt.fn)
+			req := require.New(t)
+			if tt.wAnyErr {
+				req.Error(err)
+			} else {
+				req.ErrorIs(err, tt.wErr)
+			}
+			var names []string
+			entries, rErr := os.ReadDir(dst)
+			if tt.wNames != nil {
+				req.NoError(rErr)
+			}
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			req.Equal(tt.wNames, names)
+
+			// No staging directories must be left behind.
+			entries, rErr = os.ReadDir(root)
+			req.NoError(rErr)
+			req.LessOrEqual(len(entries), 1)
+		})
+	}
+}
+
+// setupDir returns a function that creates a directory with the named
+// files.
+func setupDir(names ...string) func(t *testing.T, dir string) {
+	return func(t *testing.T, dir string) {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		for _, name := range names {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+		}
+	}
+}
+
+func Test_writeDir_memFS(t *testing.T) {
+	m := &corpusdir.MemFS{}
+	setWFS(t, m)
+	write := func(names ...string) func(stage *corpusdir.Stage) error {
+		return func(stage *corpusdir.Stage) error {
+			for _, name := range names {
+				if err := stage.WriteFile(name, []byte(name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	req := require.New(t)
+	req.NoError(writeDir("/fuzz/dst", write("a", "b", "a")))
+	req.NoError(writeDir("/fuzz/dst", write("c")))
+	req.ErrorIs(writeDir("/fuzz/dst", func(stage *corpusdir.Stage) error {
+		write("d")(stage)
+		return errSnap
+	}), errSnap)
+	req.NoError(writeFile("/fuzz/out", func(w io.Writer) error {
+		_, err := io.WriteString(w, "out")
+		return err
+	}))
+	req.Equal([]string{
+		"fuzz", "fuzz/dst", "fuzz/dst/a", "fuzz/dst/b", "fuzz/dst/c", "fuzz/out",
+	}, m.Names(), "no staging left behind")
+}
+
+// setWFS sets the file system that the commands write to for the
+// duration of the test.
+func setWFS(t *testing.T, fsys corpusdir.FS) {
+	old := wfs
+	wfs = fsys
+	t.Cleanup(func() { wfs = old })
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// corpusFS returns a file system and the name of the corpus directory
+// in it for the OS-specific directory path dir.
+//
+// The path is made absolute first, which resolves Windows drive-relative
+// paths (e.g. "C:fuzz"), drops trailing separators, and lets package os
+// apply its long path support, none of which [os.DirFS] does on its own.
+// The file system is then rooted at the parent directory, so that errors
+// name the corpus directory instead of just ".".
+func corpusFS(dir string) (fsys fs.FS, name string, err error) {
+	if dir, err = filepath.Abs(dir); err != nil {
+		return
+	}
+	root, name := filepath.Split(dir)
+	if name == "" {
+		// The root of a volume, e.g. "/", `C:\` or `\\host\share`.
+		return os.DirFS(dir), ".", nil
+	}
+	return os.DirFS(root), name, nil
+}
+
+// selectedFS is a file system with the entries of the corpus directory
+// dir limited to the selected names.
+type selectedFS struct {
+	fs.FS
+	dir   string
+	names map[string]bool
+}
+
+// ReadDir implements the [fs.ReadDirFS] interface.
+func (f selectedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(f.FS, name)
+	if err != nil || name != f.dir {
+		return entries, err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if f.names[e.Name()] {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+// osPathFS is a file system that opens the names as the OS-specific paths
+// they are in slash-separated form, relative to the working directory or
+// not, so that the corpus directories given as arguments can be read
+// together and are named as they were given. The names need not be valid
+// per [fs.ValidPath].
+type osPathFS struct{}
+
+// Open implements the [fs.FS] interface.
+func (osPathFS) Open(name string) (fs.File, error) {
+	return os.Open(filepath.FromSlash(name))
+}
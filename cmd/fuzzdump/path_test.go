@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The paths particular to Windows, drive-relative, UNC, and extended-length
+// ones, are tested by Test_corpusFS_windows.
+func Test_corpusFS(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, filepath.Dir(dir))
+	base := filepath.Base(dir)
+	sep := string(filepath.Separator)
+
+	tests := map[string]struct {
+		dir   string
+		wName string
+	}{"absolute": {
+		dir:   dir,
+		wName: base,
+	}, "relative": {
+		dir:   base,
+		wName: base,
+	}, "trailing separator": {
+		dir:   dir + sep + sep,
+		wName: base,
+	}, "unclean": {
+		dir:   filepath.Join(dir, "..") + sep + "." + sep + base,
+		wName: base,
+	}, "root": {
+		dir:   sep,
+		wName: ".",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			fsys, name, err := corpusFS(tt.dir)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.wName, name)
+			fi, err := fs.Stat(fsys, name)
+			req.NoError(err)
+			req.True(fi.IsDir())
+		})
+	}
+}
+
+func Test_corpusFS_notExist(t *testing.T) {
+	fsys, name, err := corpusFS(filepath.Join(t.TempDir(), "absent"))
+	req := require.New(t)
+	req.NoError(err)
+	_, err = fs.Stat(fsys, name)
+	req.ErrorIs(err, fs.ErrNotExist)
+	req.ErrorContains(err, "absent")
+}
+
+// chdir changes the working directory to dir for the duration of t.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_corpusFS_windows(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, filepath.Dir(dir))
+	base := filepath.Base(dir)
+	vol := filepath.VolumeName(dir)
+	// The same directory by the administrative share of its drive.
+	unc := `\\localhost\` + strings.TrimSuffix(vol, ":") + "$" + dir[len(vol):]
+
+	tests := map[string]struct {
+		dir   string
+		wName string
+		share bool // Whether it takes the administrative share.
+	}{"drive-relative": {
+		dir:   vol + base,
+		wName: base,
+	}, "trailing backslash": {
+		dir:   dir + `\`,
+		wName: base,
+	}, "forward slashes": {
+		dir:   filepath.ToSlash(dir) + "/",
+		wName: base,
+	}, "extended-length": {
+		dir:   `\\?\` + dir,
+		wName: base,
+	}, "drive root": {
+		dir:   vol + `\`,
+		wName: ".",
+	}, "UNC": {
+		dir:   unc,
+		wName: base,
+		share: true,
+	}, "UNC trailing backslash": {
+		dir:   unc + `\`,
+		wName: base,
+		share: true,
+	}, "UNC extended-length": {
+		dir:   `\\?\UNC\` + unc[2:],
+		wName: base,
+		share: true,
+	}, "UNC share root": {
+		dir:   filepath.VolumeName(unc) + `\`,
+		wName: ".",
+		share: true,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := os.Stat(unc); tt.share && err != nil {
+				t.Skipf("administrative share not available: %v", err)
+			}
+			fsys, name, err := corpusFS(tt.dir)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.wName, name)
+			fi, err := fs.Stat(fsys, name)
+			req.NoError(err)
+			req.True(fi.IsDir())
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_startProfiling(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		dir := t.TempDir()
+		cpu := filepath.Join(dir, "cpu.pprof")
+		mem := filepath.Join(dir, "mem.pprof")
+		req := require.New(t)
+		stop, err := startProfiling(cpu, mem)
+		req.NoError(err)
+		req.NoError(stop())
+		for _, name := range []string{cpu, mem} {
+			fi, err := os.Stat(name)
+			req.NoError(err)
+			req.NotZero(fi.Size())
+		}
+	})
+	t.Run("none", func(t *testing.T) {
+		stop, err := startProfiling("", "")
+		req := require.New(t)
+		req.NoError(err)
+		req.NoError(stop())
+	})
+	t.Run("bad cpu path", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "absent", "cpu.pprof")
+		_, err := startProfiling(name, "")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+	t.Run("bad mem path", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "absent", "mem.pprof")
+		stop, err := startProfiling("", name)
+		req := require.New(t)
+		req.NoError(err)
+		req.ErrorIs(stop(), os.ErrNotExist)
+	})
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// A provenance records where a corpus entry was imported from.
+type provenance struct {
+	// Source is the path of the corpus the entry was imported from.
+	Source string `json:"source"`
+	// Name is the name of the entry in the source corpus.
+	Name string `json:"name,omitempty"`
+	// Format is the format of the source corpus.
+	Format string `json:"format"`
+	// Time is when the entry was imported.
+	Time time.Time `json:"time"`
+}
+
+// String returns a description of p, e.g.:
+//
+//	imported from corpus.json (json-dump) as 1 at 2024-01-02T03:04:05Z
+func (p provenance) String() string {
+	s := fmt.Sprintf("imported from %s (%s)", p.Source, p.Format)
+	if p.Name != "" {
+		s += " as " + p.Name
+	}
+	return s + " at " + p.Time.Format(time.RFC3339)
+}
+
+// provenances of entries, by the names of their files.
+type provenances map[string]provenance
+
+// provenancePath returns the path of the sidecar file with provenances
+// of the entries of the corpus directory dir, kept next to it, as the
+// one of tags is, see [tagsPath].
+func provenancePath(dir string) string {
+	return filepath.Clean(dir) + provenanceSuffix
+}
+
+const provenanceSuffix = ".fuzzdump-provenance.json"
+
+// readProvenances of the entries of the corpus directory dir. If there
+// is no sidecar file with them, none are known.
+func readProvenances(dir string) (provenances, error) {
+	p := provenances{}
+	b, err := os.ReadFile(provenancePath(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("reading %q: %w", provenancePath(dir), err)
+	}
+	return p, nil
+}
+
+// recordProvenances of the files imported to the corpus directory dst
+// from src in the format from, adding them to those already recorded.
+// The files are expected to have been written as [writeV1Dir] does.
+func recordProvenances(dst, src, from string, files []corpus.File) error {
+	p, err := readProvenances(dst)
+	if err != nil {
+		return err
+	}
+	if src != "-" {
+		if src, err = filepath.Abs(src); err != nil {
+			return err
+		}
+	}
+	t := now().UTC().Truncate(time.Second)
+	for _, f := range files {
+		name, _, err := encodeV1(f.Entry)
+		if err != nil {
+			return err
+		}
+		p[name] = provenance{Source: src, Name: f.Name, Format: from, Time: t}
+	}
+	return writeFile(provenancePath(dst), func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(p)
+	})
+}
+
+// comment returns the function to comment the dumped entries with their
+// provenances.
+func (p provenances) comment() func(name string) string {
+	return func(name string) string {
+		if v, ok := p[name]; ok {
+			return v.String()
+		}
+		return ""
+	}
+}
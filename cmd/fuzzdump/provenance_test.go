@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_provenance(t *testing.T) {
+	defer func(v func() time.Time) { now = v }(now)
+	now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("", 3600)) }
+
+	src := filepath.Join(t.TempDir(), "corpus.json")
+	require.NoError(t, os.WriteFile(src, []byte(
+		`{"name":"first","values":[{"type":"int","value":1}]}`+"\n"), 0o644))
+	dst := writeCorpus(t, map[string]string{"seed": "int(2)"})
+	req := require.New(t)
+	req.NoError(convertMain(io.Discard, io.Discard, []string{"-provenance", src, dst}))
+
+	p, err := readProvenances(dst)
+	req.NoError(err)
+	req.Len(p, 1)
+	for _, v := range p {
+		req.Equal(provenance{
+			Source: src,
+			Name:   "first",
+			Format: formatJSONDump,
+			Time:   time.Date(2024, 1, 2, 2, 4, 5, 0, time.UTC),
+		}, v)
+	}
+
+	stdOut := &bytes.Buffer{}
+	req.NoError(dumpMain(stdOut, io.Discard, []string{"-provenance", "-canonical", dst}))
+	req.Equal("{\n"+
+		"\t// imported from "+src+" (json-dump) as first at 2024-01-02T02:04:05Z\n"+
+		"\tint(1),\n"+
+		"\tint(2),\n"+
+		"}\n", stdOut.String())
+
+	stdOut.Reset()
+	req.NoError(dumpMain(stdOut, io.Discard, []string{"-canonical", dst}))
+	req.Equal("{\n\tint(1),\n\tint(2),\n}\n", stdOut.String(), "no comments by default")
+}
+
+func Test_convertMain_provenanceFormat(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"a": "int(1)"})
+	dst := filepath.Join(t.TempDir(), "out.json")
+	err := convertMain(io.Discard, io.Discard, []string{"-provenance", "-to", "json", src, dst})
+	req := require.New(t)
+	req.ErrorIs(err, errProvenanceFormat)
+	_, err = os.Stat(dst)
+	req.ErrorIs(err, os.ErrNotExist)
+}
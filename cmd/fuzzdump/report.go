@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// A runReport is a machine-readable report of a fuzzdump run, written
+// with -report, whatever the command and its output format.
+type runReport struct {
+	// Operation is the command that was run, e.g. "dump" or "convert".
+	Operation string `json:"operation"`
+	// Inputs are the arguments it was given.
+	Inputs   []string        `json:"inputs"`
+	ExitCode int             `json:"exitCode"`
+	Summary  reportSummary   `json:"summary"`
+	Problems []reportProblem `json:"problems"`
+}
+
+// reportSummary counts the problems in a [runReport].
+type reportSummary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+}
+
+// A reportProblem is an error or a warning in a [runReport].
+type reportProblem struct {
+	// Severity is "warning" for problems with individual corpus entries
+	// that did not stop the run, otherwise "error".
+	Severity string `json:"severity"`
+	// Category names the kind of problem, e.g. "malformed-entry".
+	Category string `json:"category"`
+	// Dir is the corpus directory, when several were dumped together.
+	Dir string `json:"dir,omitempty"`
+	// File is the name of the corpus entry file, if the problem is with
+	// one.
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// newRunReport returns the report of running the operation with inputs
+// that ended with err.
+func newRunReport(operation string, inputs []string, err error) runReport {
+	r := runReport{
+		Operation: operation,
+		Inputs:    inputs,
+		ExitCode:  exitCodeFor(err),
+		Problems:  []reportProblem{},
+	}
+	r.add("", err)
+	for _, p := range r.Problems {
+		if p.Severity == "warning" {
+			r.Summary.Warnings++
+		} else {
+			r.Summary.Errors++
+		}
+	}
+	return r
+}
+
+// add the problems that err reports to r, as occurred in dir.
+func (r *runReport) add(dir string, err error) {
+	var (
+		corpusErrs fuzzdump.CorpusErrors
+		dirsErr    *dirsError
+	)
+	switch {
+	case err == nil:
+	case errors.As(err, &dirsErr):
+		for _, e := range dirsErr.errs {
+			r.add(e.dir, e.err)
+		}
+	case errors.As(err, &corpusErrs):
+		for _, e := range corpusErrs {
+			r.add(dir, e)
+		}
+	default:
+		p := reportProblem{
+			Severity: "error",
+			Category: errorCategory(err),
+			Dir:      dir,
+			Message:  err.Error(),
+		}
+		if exitCodeFor(err) == fuzzdump.ExitSoft {
+			p.Severity = "warning"
+		}
+		var fileErr *fuzzdump.FileError
+		if errors.As(err, &fileErr) {
+			p.File = fileErr.Name
+		}
+		r.Problems = append(r.Problems, p)
+	}
+}
+
+// errorCategory returns the name of the kind of problem err reports.
+func errorCategory(err error) string {
+	for _, c := range errorCategories {
+		if errors.Is(err, c.err) {
+			return c.name
+		}
+	}
+	return "error"
+}
+
+// errorCategories in the order they are checked in.
+var errorCategories = []struct {
+	name string
+	err  error
+}{
+	{"short-entry", fuzzdump.ErrShortEntry},
+	{"missing-version", fuzzdump.ErrMissingVersion},
+	{"trailing-garbage", fuzzdump.ErrTrailingGarbage},
+	{"unsupported-version", fuzzdump.ErrUnsupportedVersion},
+	{"malformed-entry", fuzzdump.ErrMalformedEntry},
+	{"malformed-value", fuzzdump.ErrMalformedValue},
+	{"inconsistent-arg-count", fuzzdump.ErrInconsistentArgCount},
+	{"empty-corpus", fuzzdump.ErrEmptyCorpus},
+	{"threshold-exceeded", errThreshold},
+	{"coercion", errCoerce},
+	{"locked", errLocked},
+	{"read-only", errReadOnly},
+	{"not-found", fs.ErrNotExist},
+}
+
+// writeRunReport writes r to the named file as JSON.
+func writeRunReport(name string, r runReport) error {
+	return writeFile(name, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(r)
+	})
+}
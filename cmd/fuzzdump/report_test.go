@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_realMain_report(t *testing.T) {
+	good := writeCorpus(t, map[string]string{"a": "int(1)"})
+	bad := writeCorpus(t, map[string]string{"a": "int(1)", "b": "int(2"})
+	tests := map[string]struct {
+		args []string
+		want runReport
+	}{"clean dump": {
+		args: []string{good},
+		want: runReport{
+			Operation: "dump",
+			Inputs:    []string{good},
+			Problems:  []reportProblem{},
+		},
+	}, "invalid entry": {
+		args: []string{"-canonical", bad},
+		want: runReport{
+			Operation: "dump",
+			Inputs:    []string{"-canonical", bad},
+			ExitCode:  fuzzdump.ExitSoft,
+			Summary:   reportSummary{Warnings: 1},
+			Problems: []reportProblem{{
+				Severity: "warning",
+				Category: "malformed-value",
+				File:     "b",
+				Message:  `reading "b": line 2, column 6: malformed value: missing ',' before newline in argument list: "int(2"`,
+			}},
+		},
+	}, "multiple dirs": {
+		args: []string{"-canonical", good, bad},
+		want: runReport{
+			Operation: "dump",
+			Inputs:    []string{"-canonical", good, bad},
+			ExitCode:  fuzzdump.ExitSoft,
+			Summary:   reportSummary{Warnings: 1},
+			Problems: []reportProblem{{
+				Severity: "warning",
+				Category: "malformed-value",
+				Dir:      bad,
+				File:     "b",
+				Message:  `reading "b": line 2, column 6: malformed value: missing ',' before newline in argument list: "int(2"`,
+			}},
+		},
+	}, "threshold": {
+		args: []string{"check", "-max-invalid", "0", bad},
+		want: runReport{
+			Operation: "check",
+			Inputs:    []string{"-max-invalid", "0", bad},
+			ExitCode:  fuzzdump.ExitThreshold,
+			Summary:   reportSummary{Errors: 1},
+			Problems: []reportProblem{{
+				Severity: "error",
+				Category: "threshold-exceeded",
+				Message:  "corpus thresholds exceeded: 1 invalid (max 0)",
+			}},
+		},
+	}, "read-only": {
+		args: []string{"-read-only", "tag", good, "slow", "a"},
+		want: runReport{
+			Operation: "tag",
+			Inputs:    []string{good, "slow", "a"},
+			ExitCode:  fuzzdump.ExitHard,
+			Summary:   reportSummary{Errors: 1},
+			Problems: []reportProblem{{
+				Severity: "error",
+				Category: "read-only",
+				Message:  "refused to write in read-only mode: tag",
+			}},
+		},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			report := filepath.Join(t.TempDir(), "report.json")
+			args := append([]string{"-report=" + report}, tt.args...)
+			err := realMain(io.Discard, io.Discard, args)
+			req := require.New(t)
+			req.Equal(tt.want.ExitCode, exitCodeFor(err))
+
+			b, err := os.ReadFile(report)
+			req.NoError(err)
+			var got runReport
+			req.NoError(json.Unmarshal(b, &got))
+			req.Equal(tt.want, got)
+		})
+	}
+}
+
+func Test_parseGlobalFlags(t *testing.T) {
+	tests := map[string]struct {
+		args  []string
+		want  globalFlags
+		wRest []string
+		wErr  error
+	}{"none": {
+		args:  []string{"-canonical", "dir"},
+		wRest: []string{"-canonical", "dir"},
+	}, "all": {
+		args:  []string{"--report", "r.json", "-read-only", "show", "dir"},
+		want:  globalFlags{readOnly: true, report: "r.json"},
+		wRest: []string{"show", "dir"},
+	}, "report with equals": {
+		args:  []string{"-report=r.json"},
+		want:  globalFlags{report: "r.json"},
+		wRest: []string{},
+	}, "report without file": {
+		args: []string{"-report"},
+		wErr: errReportArg,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, rest, err := parseGlobalFlags(tt.args)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.want, got)
+			req.Equal(tt.wRest, rest)
+		})
+	}
+}
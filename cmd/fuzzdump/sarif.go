@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"path/filepath"
+)
+
+// SARIF 2.1.0 log structure, as much of it as a check report needs.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+)
+
+// SARIF rule IDs of the problems a check reports.
+const (
+	ruleInvalidEntry = "invalid-entry"
+	ruleThreshold    = "threshold-exceeded"
+)
+
+var sarifRules = []sarifRule{{
+	ID:               ruleInvalidEntry,
+	ShortDescription: sarifMessage{"Corpus entry file is malformed or has malformed values"},
+}, {
+	ID:               ruleThreshold,
+	ShortDescription: sarifMessage{"Corpus exceeds a size or validity threshold"},
+}}
+
+// writeSARIFReport writes the problems found in s to w as a SARIF log,
+// with each invalid entry reported at its file, and each threshold
+// exceeded, at the corpus directory.
+func writeSARIFReport(w io.Writer, s stats) error {
+	dir := filepath.ToSlash(s.dir)
+	results := make([]sarifResult, 0, len(s.invalid)+len(s.breaches))
+	for _, f := range s.invalid {
+		results = append(results,
+			newSARIFResult(ruleInvalidEntry, f.err.Error(), path.Join(dir, f.name)))
+	}
+	for _, b := range s.breaches {
+		results = append(results, newSARIFResult(ruleThreshold, b, dir))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{sarifDriver{
+				Name:           cmdName,
+				InformationURI: "https://github.com/antichris/go-fuzzdump",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	})
+}
+
+func newSARIFResult(rule, msg, uri string) sarifResult {
+	return sarifResult{
+		RuleID:  rule,
+		Level:   "error",
+		Message: sarifMessage{msg},
+		Locations: []sarifLocation{{
+			sarifPhysicalLocation{sarifArtifactLocation{uri}},
+		}},
+	}
+}
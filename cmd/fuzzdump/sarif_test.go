@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeSARIFReport(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"1": "int(1)", "2": "int(("})
+	stdOut := &bytes.Buffer{}
+	err := checkMain(stdOut, io.Discard, []string{"-format", "sarif", "-max-invalid", "0", dir})
+	req := require.New(t)
+	req.ErrorIs(err, errThreshold)
+
+	var log sarifLog
+	req.NoError(json.Unmarshal(stdOut.Bytes(), &log))
+	req.Equal("2.1.0", log.Version)
+	req.Len(log.Runs, 1)
+	run := log.Runs[0]
+	req.Equal(cmdName, run.Tool.Driver.Name)
+	req.Len(run.Results, 2)
+
+	uri := filepath.ToSlash(dir)
+	wURIs := []string{path.Join(uri, "2"), uri}
+	wRules := []string{ruleInvalidEntry, ruleThreshold}
+	for i, r := range run.Results {
+		req.Equal(wRules[i], r.RuleID)
+		req.Equal("error", r.Level)
+		req.Equal(wURIs[i], r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	req.Equal("1 invalid (max 0)", run.Results[1].Message.Text)
+}
+
+func Test_writeSARIFReport_clean(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"1": "int(1)"})
+	stdOut := &bytes.Buffer{}
+	err := checkMain(stdOut, io.Discard, []string{"-format", "sarif", dir})
+	req := require.New(t)
+	req.NoError(err)
+	var log sarifLog
+	req.NoError(json.Unmarshal(stdOut.Bytes(), &log))
+	req.NotNil(log.Runs[0].Results)
+	req.Empty(log.Runs[0].Results)
+}
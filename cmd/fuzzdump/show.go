@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
+)
+
+// showMain dumps the single entry of a fuzz test corpus directory whose
+// file name or content hash starts with a given prefix, or that is at a
+// given index, or extracts its file.
+func showMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("show")
+	var (
+		index = fl.Int("index", -1,
+			"show the entry at index `n` (from 0) of those sorted by file name")
+		extractTo = fl.String("extract-to", "",
+			"copy the entry file to `path` (or into it, if a directory) instead")
+	)
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	wantArgs := 2
+	if *index >= 0 {
+		wantArgs = 1
+	}
+	if len(args) != wantArgs || args[0] == "" || args[wantArgs-1] == "" {
+		return errShowArgs
+	}
+	fsys, dir, err := corpusFS(args[0])
+	if err != nil {
+		return err
+	}
+	var (
+		name string
+		data []byte
+	)
+	if *index >= 0 {
+		name, data, err = entryAt(fsys, dir, *index)
+	} else {
+		name, data, err = findEntry(fsys, dir, args[1])
+	}
+	if err != nil {
+		return err
+	}
+	if *extractTo != "" {
+		return extractEntry(*extractTo, name, data)
+	}
+	e, err := corpus.Unmarshal(data)
+	if err != nil {
+		return &fuzzdump.FileError{Name: name, Err: err}
+	}
+	p := format.NewPrinter(w, len(e))
+	if err := p.Begin(); err != nil {
+		return err
+	}
+	if err := p.Entry(e); err != nil {
+		return err
+	}
+	return p.End()
+}
+
+// findEntry returns the name and data of the single entry file in dir in
+// fsys whose name, or the name Go would give it by its contents, starts
+// with prefix.
+func findEntry(fsys fs.FS, dir, prefix string) (name string, data []byte, err error) {
+	names, err := fileNames(fsys, dir)
+	if err != nil {
+		return
+	}
+	var matches []string
+	for _, n := range names {
+		b, err := fs.ReadFile(fsys, path.Join(dir, n))
+		if err != nil {
+			return "", nil, err
+		}
+		if strings.HasPrefix(n, prefix) || strings.HasPrefix(corpus.FileName(b), prefix) {
+			matches = append(matches, n)
+			name, data = n, b
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("%w: %q", errNoEntry, prefix)
+	case 1:
+		return
+	}
+	return "", nil, fmt.Errorf("%w: %q matches %s",
+		errAmbiguousEntry, prefix, strings.Join(matches, ", "))
+}
+
+// entryAt returns the name and data of the entry file at index i of
+// those in dir in fsys, sorted by name.
+func entryAt(fsys fs.FS, dir string, i int) (name string, data []byte, err error) {
+	names, err := fileNames(fsys, dir)
+	if err != nil {
+		return
+	}
+	if i >= len(names) {
+		return "", nil, fmt.Errorf("%w: %d of %d", errNoIndex, i, len(names))
+	}
+	name = names[i]
+	data, err = fs.ReadFile(fsys, path.Join(dir, name))
+	return
+}
+
+// extractEntry writes the data of the named entry file to dst, or to a
+// file of the same name in dst, if that is a directory.
+func extractEntry(dst, name string, data []byte) error {
+	if fi, err := wfs.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, name)
+	}
+	return writeFile(dst, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+var (
+	errShowArgs       = errors.New("directory path argument and entry name prefix (or -index) required")
+	errNoEntry        = errors.New("no entry matches")
+	errAmbiguousEntry = errors.New("more than one entry matches")
+	errNoIndex        = errors.New("entry index out of range")
+)
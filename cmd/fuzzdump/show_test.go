@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_showMain(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{
+		"abc1": "int(1)",
+		"abd2": "int(2)",
+		// Named 901ae842a79a0767 by its contents.
+		"x":   "int(42)",
+		"bad": "int(3",
+	})
+	tests := map[string]struct {
+		args     []string
+		wOut     string
+		wErr     error
+		wErrText string
+	}{"by name": {
+		args: []string{dir, "abc"},
+		wOut: "{\n\tint(1),\n}\n",
+	}, "by hash": {
+		args: []string{dir, "901ae8"},
+		wOut: "{\n\tint(42),\n}\n",
+	}, "no match": {
+		args:     []string{dir, "fff"},
+		wErr:     errNoEntry,
+		wErrText: errNoEntry.Error() + `: "fff"`,
+	}, "ambiguous": {
+		args:     []string{dir, "ab"},
+		wErr:     errAmbiguousEntry,
+		wErrText: errAmbiguousEntry.Error() + `: "ab" matches abc1, abd2`,
+	}, "by index": {
+		args: []string{"-index", "3", dir},
+		wOut: "{\n\tint(42),\n}\n",
+	}, "index out of range": {
+		args:     []string{"-index", "4", dir},
+		wErr:     errNoIndex,
+		wErrText: errNoIndex.Error() + ": 4 of 4",
+	}, "index and prefix": {
+		args: []string{"-index", "0", dir, "ab"},
+		wErr: errShowArgs,
+	}, "no args": {
+		wErr: errShowArgs,
+	}, "no prefix": {
+		args: []string{dir, ""},
+		wErr: errShowArgs,
+	}, "absent dir": {
+		args: []string{filepath.Join(dir, "absent"), "ab"},
+		wErr: os.ErrNotExist,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut := &bytes.Buffer{}
+			err := showMain(stdOut, io.Discard, tt.args)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			if tt.wErrText != "" {
+				req.EqualError(err, tt.wErrText)
+			}
+			req.Equal(tt.wOut, stdOut.String())
+		})
+	}
+}
+
+func Test_showMain_extractTo(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"abc1": "int(1)", "abd2": "int(2)"})
+	want := []byte("go test fuzz v1\nint(2)\n")
+	tests := map[string]struct {
+		dst   func(tmp string) string
+		wPath func(tmp string) string
+	}{"to file": {
+		dst:   func(tmp string) string { return filepath.Join(tmp, "repro") },
+		wPath: func(tmp string) string { return filepath.Join(tmp, "repro") },
+	}, "into dir": {
+		dst:   func(tmp string) string { return tmp },
+		wPath: func(tmp string) string { return filepath.Join(tmp, "abd2") },
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			tmp := t.TempDir()
+			stdOut := &bytes.Buffer{}
+			err := showMain(stdOut, io.Discard,
+				[]string{"-extract-to", tt.dst(tmp), "-index", "1", dir})
+			req := require.New(t)
+			req.NoError(err)
+			req.Empty(stdOut.String())
+			got, err := os.ReadFile(tt.wPath(tmp))
+			req.NoError(err)
+			req.Equal(want, got)
+		})
+	}
+}
+
+func Test_showMain_extractTo_memFS(t *testing.T) {
+	m := &corpusdir.MemFS{}
+	setWFS(t, m)
+	dir := writeCorpus(t, map[string]string{"abc1": "int(1)", "abd2": "int(2)"})
+	req := require.New(t)
+	req.NoError(m.MkdirAll("/repro"))
+	req.NoError(showMain(io.Discard, io.Discard, []string{"-extract-to", "/repro", "-index", "1", dir}))
+	got, err := fs.ReadFile(m, "repro/abd2")
+	req.NoError(err)
+	req.Equal("go test fuzz v1\nint(2)\n", string(got))
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/antichris/go-fuzzdump/corpusdir"
+)
+
+// snapshotMain writes a snapshot of a fuzz test corpus directory: the
+// entry files as they are, along with their names, modification times
+// and tags, to a single file that restore writes back.
+func snapshotMain(w, _ io.Writer, args []string) (err error) {
+	fl := newFlagSet("snapshot")
+	output := fl.String("o", "",
+		"write the snapshot to `file` (- for the standard output)")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 1 || args[0] == "" || *output == "" {
+		return errSnapshotArgs
+	}
+	src := args[0]
+	// A snapshot taken halfway through a change would be of no use.
+	if err = checkUnlocked(src); err != nil {
+		return
+	}
+	names, err := fileNames(os.DirFS(src), ".")
+	if err != nil {
+		return
+	}
+	tags, err := readTags(src)
+	if err != nil {
+		return
+	}
+	m := snapshotManifest{
+		Format:  snapshotFormat,
+		Version: snapshotVersion,
+		Created: now().UTC(),
+		Corpus:  filepath.Base(filepath.Clean(src)),
+		Entries: make([]snapshotEntry, len(names)),
+	}
+	data := make([][]byte, len(names))
+	for i, name := range names {
+		path := filepath.Join(src, name)
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if data[i], err = os.ReadFile(path); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data[i])
+		m.Entries[i] = snapshotEntry{
+			Name:    name,
+			Size:    int64(len(data[i])),
+			ModTime: fi.ModTime().UTC(),
+			SHA256:  hex.EncodeToString(sum[:]),
+			Tags:    tags[name],
+		}
+	}
+	return writeDumpFile(w, *output, func(w io.Writer) error {
+		z := zip.NewWriter(w)
+		manifest, err := json.MarshalIndent(m, "", "\t")
+		if err != nil {
+			return err
+		}
+		if err = addSnapshotFile(z, snapshotManifestName, m.Created, manifest); err != nil {
+			return err
+		}
+		for i, e := range m.Entries {
+			if err = addSnapshotFile(z, snapshotEntriesDir+e.Name, e.ModTime, data[i]); err != nil {
+				return err
+			}
+		}
+		return z.Close()
+	})
+}
+
+// addSnapshotFile adds a file with the given name, modification time,
+// and data to the zip archive z.
+func addSnapshotFile(z *zip.Writer, name string, mtime time.Time, data []byte) error {
+	f, err := z.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: mtime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// restoreMain restores a corpus directory from a snapshot written by
+// snapshot.
+func restoreMain(w, _ io.Writer, args []string) (err error) {
+	fl := newFlagSet("restore")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return errRestoreArgs
+	}
+	src, dst := args[0], args[1]
+	var b []byte
+	if src == "-" {
+		b, err = io.ReadAll(stdIn)
+	} else {
+		b, err = os.ReadFile(src)
+	}
+	if err != nil {
+		return
+	}
+	// Nothing is restored unless the whole snapshot is intact.
+	m, data, err := readSnapshot(b)
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", src, err)
+	}
+	unlock, err := lockCorpus(dst)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	switch entries, err := os.ReadDir(dst); {
+	case err == nil && len(entries) > 0:
+		return fmt.Errorf("%w: %q", errRestoreExists, dst)
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	err = writeDir(dst, func(stage *corpusdir.Stage) error {
+		for i, e := range m.Entries {
+			if err := stage.WriteFile(e.Name, data[i]); err != nil {
+				return err
+			}
+			if c, ok := wfs.(corpusdir.ChtimesFS); ok {
+				if err := c.Chtimes(filepath.Join(stage.Dir(), e.Name), e.ModTime); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	tags, err := readTags(dst)
+	if err != nil {
+		return
+	}
+	for _, e := range m.Entries {
+		for _, tag := range e.Tags {
+			tags.add(e.Name, tag)
+		}
+	}
+	return writeTags(dst, tags)
+}
+
+// readSnapshot returns the manifest of the snapshot b and the data of
+// its entries, in the order of the manifest, checking that each entry
+// is there, with the size and checksum in the manifest.
+func readSnapshot(b []byte) (m snapshotManifest, data [][]byte, err error) {
+	z, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return m, nil, fmt.Errorf("%w: %v", errBadSnapshot, err)
+	}
+	manifest, err := readZipFile(z, snapshotManifestName)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(manifest, &m); err != nil {
+		return m, nil, fmt.Errorf("%w: %v", errBadSnapshot, err)
+	}
+	if m.Format != snapshotFormat {
+		return m, nil, fmt.Errorf("%w: format %q", errBadSnapshot, m.Format)
+	}
+	if m.Version != snapshotVersion {
+		return m, nil, fmt.Errorf("%w: %d", errSnapshotVersion, m.Version)
+	}
+	data = make([][]byte, len(m.Entries))
+	seen := map[string]bool{}
+	for i, e := range m.Entries {
+		if !validEntryName(e.Name) || seen[e.Name] {
+			return m, nil, fmt.Errorf("%w: entry name %q", errBadSnapshot, e.Name)
+		}
+		seen[e.Name] = true
+		if data[i], err = readZipFile(z, snapshotEntriesDir+e.Name); err != nil {
+			return
+		}
+		sum := sha256.Sum256(data[i])
+		if int64(len(data[i])) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+			return m, nil, fmt.Errorf("%w: %q", errSnapshotChecksum, e.Name)
+		}
+	}
+	return
+}
+
+// readZipFile returns the contents of the named file in z.
+func readZipFile(z *zip.Reader, name string) ([]byte, error) {
+	f, err := z.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errBadSnapshot, err)
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// validEntryName reports whether name can be that of an entry file
+// directly in a corpus directory, on any platform.
+func validEntryName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\:`) && filepath.Base(name) == name
+}
+
+// A snapshotManifest describes a snapshot and the corpus it was taken
+// of.
+type snapshotManifest struct {
+	Format  string          `json:"format"`
+	Version int             `json:"version"`
+	Created time.Time       `json:"created"`
+	Corpus  string          `json:"corpus"` // The base name of its directory.
+	Entries []snapshotEntry `json:"entries"`
+}
+
+// A snapshotEntry describes an entry file in a snapshot.
+type snapshotEntry struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	SHA256  string    `json:"sha256"`
+	Tags    []string  `json:"tags,omitempty"`
+}
+
+const (
+	snapshotFormat       = "fuzzdump-snapshot"
+	snapshotVersion      = 1
+	snapshotManifestName = "manifest.json"
+	snapshotEntriesDir   = "entries/"
+)
+
+var (
+	errSnapshotArgs     = errors.New("corpus directory and output file (-o) arguments required")
+	errRestoreArgs      = errors.New("snapshot file and destination directory arguments required")
+	errBadSnapshot      = errors.New("not a valid fuzzdump snapshot")
+	errSnapshotVersion  = errors.New("unsupported snapshot version")
+	errSnapshotChecksum = errors.New("snapshot entry does not match its checksum")
+	errRestoreExists    = errors.New("destination directory is not empty")
+)
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_snapshotMain_restoreMain(t *testing.T) {
+	src := writeCorpus(t, map[string]string{
+		"abc1": "int(1)",
+		"abd2": "int(2)",
+		"bad":  "int(", // Kept as it is.
+	})
+	req := require.New(t)
+	mtime := time.Date(2022, 3, 15, 12, 30, 0, 123, time.UTC)
+	req.NoError(os.Chtimes(filepath.Join(src, "abc1"), mtime, mtime))
+	req.NoError(tagMain(io.Discard, io.Discard, []string{src, "slow", "abc"}))
+
+	snapshot := filepath.Join(t.TempDir(), "snap.fdz")
+	req.NoError(snapshotMain(io.Discard, io.Discard, []string{"-o", snapshot, src}))
+	dst := filepath.Join(t.TempDir(), "FuzzRestored")
+	req.NoError(restoreMain(io.Discard, io.Discard, []string{snapshot, dst}))
+
+	for _, name := range []string{"abc1", "abd2", "bad"} {
+		want, err := os.ReadFile(filepath.Join(src, name))
+		req.NoError(err)
+		got, err := os.ReadFile(filepath.Join(dst, name))
+		req.NoError(err)
+		req.Equal(string(want), string(got), name)
+	}
+	fi, err := os.Stat(filepath.Join(dst, "abc1"))
+	req.NoError(err)
+	req.True(mtime.Equal(fi.ModTime()), fi.ModTime())
+	tags, err := readTags(dst)
+	req.NoError(err)
+	req.Equal(entryTags{"abc1": {"slow"}}, tags)
+
+	req.ErrorIs(restoreMain(io.Discard, io.Discard, []string{snapshot, dst}),
+		errRestoreExists)
+}
+
+func Test_snapshotMain_stdout(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"1": "int(1)"})
+	defer func(v func() time.Time) { now = v }(now)
+	now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	stdOut := &bytes.Buffer{}
+	req := require.New(t)
+	req.NoError(snapshotMain(stdOut, io.Discard, []string{"-o", "-", src}))
+	m, data, err := readSnapshot(stdOut.Bytes())
+	req.NoError(err)
+	req.Equal(snapshotFormat, m.Format)
+	req.Equal(snapshotVersion, m.Version)
+	req.Equal(filepath.Base(src), m.Corpus)
+	req.Equal("2024-01-02T03:04:05Z", m.Created.Format(time.RFC3339))
+	req.Len(m.Entries, 1)
+	req.Equal("1", m.Entries[0].Name)
+	req.Equal([][]byte{[]byte("go test fuzz v1\nint(1)\n")}, data)
+}
+
+func Test_readSnapshot_errors(t *testing.T) {
+	entry := []byte("go test fuzz v1\nint(1)\n")
+	const sum = "319194200cbebc1b28cd91ec5abfe2914d02e370e20c0c2495f6a9c559ba26a3"
+	manifest := func(name string, size int64, sum string) *snapshotManifest {
+		return &snapshotManifest{
+			Format:  snapshotFormat,
+			Version: snapshotVersion,
+			Entries: []snapshotEntry{{Name: name, Size: size, SHA256: sum}},
+		}
+	}
+	tests := map[string]struct {
+		manifest *snapshotManifest
+		files    map[string][]byte
+		wErr     error
+	}{"no manifest": {
+		wErr: errBadSnapshot,
+	}, "bad format": {
+		manifest: &snapshotManifest{Format: "other", Version: snapshotVersion},
+		wErr:     errBadSnapshot,
+	}, "bad version": {
+		manifest: &snapshotManifest{Format: snapshotFormat, Version: 2},
+		wErr:     errSnapshotVersion,
+	}, "missing entry": {
+		manifest: manifest("1", int64(len(entry)), sum),
+		wErr:     errBadSnapshot,
+	}, "path in name": {
+		manifest: manifest("../1", int64(len(entry)), sum),
+		files:    map[string][]byte{"entries/../1": entry},
+		wErr:     errBadSnapshot,
+	}, "size mismatch": {
+		manifest: manifest("1", 1, sum),
+		files:    map[string][]byte{"entries/1": entry},
+		wErr:     errSnapshotChecksum,
+	}, "checksum mismatch": {
+		manifest: manifest("1", int64(len(entry)), "00"),
+		files:    map[string][]byte{"entries/1": entry},
+		wErr:     errSnapshotChecksum,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			req := require.New(t)
+			b := &bytes.Buffer{}
+			z := zip.NewWriter(b)
+			if tt.manifest != nil {
+				data, err := json.Marshal(tt.manifest)
+				req.NoError(err)
+				req.NoError(addSnapshotFile(z, snapshotManifestName, time.Time{}, data))
+			}
+			for name, data := range tt.files {
+				req.NoError(addSnapshotFile(z, name, time.Time{}, data))
+			}
+			req.NoError(z.Close())
+			_, _, err := readSnapshot(b.Bytes())
+			req.ErrorIs(err, tt.wErr)
+		})
+	}
+	t.Run("not a zip", func(t *testing.T) {
+		_, _, err := readSnapshot([]byte("nope"))
+		require.ErrorIs(t, err, errBadSnapshot)
+	})
+}
+
+func Test_snapshotMain_errors(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"1": "int(1)"})
+	for n, args := range map[string][]string{
+		"no args":   nil,
+		"no output": {dir},
+		"two dirs":  {"-o", "-", dir, dir},
+	} {
+		t.Run(n, func(t *testing.T) {
+			err := snapshotMain(io.Discard, io.Discard, args)
+			require.ErrorIs(t, err, errSnapshotArgs)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// statsMain reports the statistics of a fuzz test corpus directory, for
+// a glance at its health without dumping it, or those of each of several,
+// and of all of them together.
+func statsMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("stats")
+	recursive := fl.Bool("r", false,
+		"report on every fuzz test corpus directory in the trees under the directories")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) == 0 {
+		return errNoDirArg
+	}
+	for _, a := range args {
+		if a == "" {
+			return errNoDirArg
+		}
+	}
+	if *recursive {
+		var err error
+		if args, err = findCorpora(args); err != nil {
+			return err
+		}
+	}
+	if len(args) > 1 || *recursive {
+		return statsDirs(w, args)
+	}
+	fsys, dir, err := corpusFS(args[0])
+	if err != nil {
+		return err
+	}
+	s, err := fuzzdump.Stats(fsys, dir)
+	if exitCodeFor(err) >= fuzzdump.ExitHard {
+		return err
+	}
+	if wErr := writeStats(w, s, "unknown"); wErr != nil {
+		return wErr
+	}
+	return err
+}
+
+// statsDirs reports the statistics of each of the corpus directories
+// dirs, in its own section headed by a comment naming the directory, and
+// those of all of them together in a last one, along with how many of
+// their entries are shared.
+func statsDirs(w io.Writer, dirs []string) error {
+	names := make([]string, len(dirs))
+	for i, d := range dirs {
+		names[i] = filepath.ToSlash(d)
+	}
+	a, err := fuzzdump.StatsDirs(osPathFS{}, names...)
+	mixed := false
+	for i, t := range a.Targets {
+		var b bytes.Buffer
+		if wErr := writeStats(&b, t.CorpusStats, "unknown"); wErr != nil {
+			return wErr
+		}
+		fmt.Fprintf(&b, "shared      %d\n", t.Shared)
+		if wErr := writeSection(w, i == 0, dirs[i], &b); wErr != nil {
+			return wErr
+		}
+		mixed = mixed || t.ArgTypes != nil
+	}
+	var b bytes.Buffer
+	none := "unknown"
+	if mixed {
+		none = "mixed"
+	}
+	if wErr := writeStats(&b, a.Total, none); wErr != nil {
+		return wErr
+	}
+	fmt.Fprintf(&b, "shared      %d\n", a.Shared)
+	if wErr := writeSection(w, false, "total", &b); wErr != nil {
+		return wErr
+	}
+	return err
+}
+
+// writeStats writes s to w, a statistic per line, with none for the
+// arguments, if their types are not known.
+func writeStats(w io.Writer, s fuzzdump.CorpusStats, none string) error {
+	sig := none
+	if s.ArgTypes != nil {
+		sig = fmt.Sprintf("%d (%s)", len(s.ArgTypes), strings.Join(s.ArgTypes, ", "))
+	}
+	_, err := fmt.Fprintf(w, "entries     %d\n"+
+		"invalid     %d\n"+
+		"duplicates  %d\n"+
+		"arguments   %s\n"+
+		"size        min %d B, max %d B, mean %.1f B, total %d B\n",
+		s.Entries, s.Invalid, s.Duplicates, sig,
+		s.MinSize, s.MaxSize, s.MeanSize(), s.TotalSize)
+	return err
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_statsMain(t *testing.T) {
+	tests := map[string]struct {
+		values map[string]string
+		args   []string
+		wOut   string
+		wErr   error
+	}{"nominal": {
+		values: map[string]string{
+			"1": "int(1)\nstring(\"a\")",
+			"2": "int(0x1)\nstring(\"a\")",
+			"3": "int(",
+		},
+		wOut: "entries     3\n" +
+			"invalid     1\n" +
+			"duplicates  1\n" +
+			"arguments   2 (int, string)\n" +
+			"size        min 21 B, max 37 B, mean 31.0 B, total 93 B\n",
+		wErr: fuzzdump.ErrMalformedValue,
+	}, "no dir": {
+		args: []string{},
+		wErr: errNoDirArg,
+	}, "missing dir": {
+		args: []string{filepath.Join(t.TempDir(), "absent")},
+		wErr: os.ErrNotExist,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			args := tt.args
+			if args == nil {
+				args = []string{writeCorpus(t, tt.values)}
+			}
+			stdOut := &bytes.Buffer{}
+			err := statsMain(stdOut, io.Discard, args)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, stdOut.String())
+		})
+	}
+}
+
+func Test_statsMain_dirs(t *testing.T) {
+	a := writeCorpus(t, map[string]string{"1": "int(1)", "2": "int(2)"})
+	b := writeCorpus(t, map[string]string{"1": "int(0x1)"})
+	stdOut := &bytes.Buffer{}
+	req := require.New(t)
+	req.NoError(statsMain(stdOut, io.Discard, []string{a, b}))
+	req.Equal("// "+a+"\n"+
+		"entries     2\n"+
+		"invalid     0\n"+
+		"duplicates  0\n"+
+		"arguments   1 (int)\n"+
+		"size        min 23 B, max 23 B, mean 23.0 B, total 46 B\n"+
+		"shared      1\n"+
+		"\n// "+b+"\n"+
+		"entries     1\n"+
+		"invalid     0\n"+
+		"duplicates  0\n"+
+		"arguments   1 (int)\n"+
+		"size        min 25 B, max 25 B, mean 25.0 B, total 25 B\n"+
+		"shared      1\n"+
+		"\n// total\n"+
+		"entries     3\n"+
+		"invalid     0\n"+
+		"duplicates  0\n"+
+		"arguments   1 (int)\n"+
+		"size        min 23 B, max 25 B, mean 23.7 B, total 71 B\n"+
+		"shared      1\n",
+		stdOut.String())
+
+	err := statsMain(io.Discard, io.Discard, []string{"-r", t.TempDir()})
+	req.ErrorIs(err, fuzzdump.ErrNoCorpora)
+
+	c := writeCorpus(t, map[string]string{"1": "string(\"a\")"})
+	stdOut.Reset()
+	err = statsMain(stdOut, io.Discard, []string{a, c, filepath.Join(t.TempDir(), "absent")})
+	req.ErrorIs(err, os.ErrNotExist)
+	req.Contains(stdOut.String(), "// total\nentries     3\ninvalid     0\n"+
+		"duplicates  0\narguments   mixed\n")
+}
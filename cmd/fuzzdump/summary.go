@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// summarizeDir writes a one-line summary of the corpus directory dir in
+// fsys to w: the number of entries, the bytes they take, how many of
+// them are invalid, and the argument types of the valid ones.
+//
+// The invalid entries are reported in [fuzzdump.CorpusErrors], as
+// [fuzzdump.DumpDir] would report them. So is a directory without any
+// entries, as [fuzzdump.ErrEmptyCorpus], unless allowEmpty is true.
+func summarizeDir(w io.Writer, fsys fs.FS, dir string, allowEmpty bool) error {
+	s, err := readStats(fsys, dir)
+	if allowEmpty && errors.Is(err, fs.ErrNotExist) {
+		s, err = stats{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	sig := "unknown"
+	if s.types != nil {
+		sig = "(" + strings.Join(s.types, ", ") + ")"
+	}
+	if _, err := fmt.Fprintf(w, "%d entries, %d bytes, %d invalid, signature %s\n",
+		s.entries, s.bytes, len(s.invalid), sig); err != nil {
+		return err
+	}
+	var errs fuzzdump.CorpusErrors
+	for _, f := range s.invalid {
+		errs = append(errs, &fuzzdump.FileError{Name: f.name, Err: f.err})
+	}
+	if s.types == nil && !(allowEmpty && s.entries == 0) {
+		errs = append(errs, fuzzdump.ErrEmptyCorpus)
+	}
+	return errs.AsError()
+}
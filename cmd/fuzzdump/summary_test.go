@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dumpMain_summaryOnly(t *testing.T) {
+	tests := map[string]struct {
+		dir   func(t *testing.T) string
+		flags []string
+		wOut  string
+		wErr  error
+		wCode int
+	}{"nominal": {
+		dir: func(t *testing.T) string {
+			return writeCorpus(t, map[string]string{
+				"1": "int(1)\nstring(\"a\")",
+				"2": "int(2)\nstring(\"b\")",
+			})
+		},
+		wOut:  "2 entries, 70 bytes, 0 invalid, signature (int, string)\n",
+		wCode: fuzzdump.ExitSuccess,
+	}, "invalid": {
+		dir: func(t *testing.T) string {
+			return writeCorpus(t, map[string]string{"1": "int(1)", "2": "int(("})
+		},
+		wOut:  "2 entries, 45 bytes, 1 invalid, signature (int)\n",
+		wErr:  fuzzdump.ErrMalformedValue,
+		wCode: fuzzdump.ExitSoft,
+	}, "empty": {
+		dir:   func(t *testing.T) string { return t.TempDir() },
+		wOut:  "0 entries, 0 bytes, 0 invalid, signature unknown\n",
+		wErr:  fuzzdump.ErrEmptyCorpus,
+		wCode: fuzzdump.ExitEmptyCorpus,
+	}, "allowed missing": {
+		dir:   func(t *testing.T) string { return filepath.Join(t.TempDir(), "absent") },
+		flags: []string{"-allow-empty"},
+		wOut:  "0 entries, 0 bytes, 0 invalid, signature unknown\n",
+		wCode: fuzzdump.ExitSuccess,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			stdOut := &bytes.Buffer{}
+			args := append([]string{"-summary-only"}, tt.flags...)
+			err := dumpMain(stdOut, io.Discard, append(args, tt.dir(t)))
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.wCode, exitCodeFor(err))
+			req.Equal(tt.wOut, stdOut.String())
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// tagMain lists, adds or removes the tags of the entries of a fuzz test
+// corpus directory.
+func tagMain(w, _ io.Writer, args []string) error {
+	fl := newFlagSet("tag")
+	remove := fl.Bool("d", false, "remove the tag instead of adding it")
+	if done, err := parseFlags(fl, w, args); done || err != nil {
+		return err
+	}
+	args = fl.Args()
+	if len(args) == 0 || args[0] == "" || len(args) == 2 {
+		return errTagArgs
+	}
+	dir := args[0]
+	if len(args) > 1 {
+		unlock, err := lockCorpus(dir)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	tags, err := readTags(dir)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		for _, name := range sortedKeys(tags) {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(tags[name], ",")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tag := args[1]
+	if tag == "" || strings.Contains(tag, ",") {
+		return fmt.Errorf("%w: %q", errBadTag, tag)
+	}
+	fsys, name, err := corpusFS(dir)
+	if err != nil {
+		return err
+	}
+	for _, prefix := range args[2:] {
+		entry, _, err := findEntry(fsys, name, prefix)
+		if err != nil {
+			return err
+		}
+		if *remove {
+			tags.remove(entry, tag)
+		} else {
+			tags.add(entry, tag)
+		}
+	}
+	return writeTags(dir, tags)
+}
+
+// entryTags are the tags of entries, by the names of their files.
+type entryTags map[string][]string
+
+// add tag to the named entry, unless it has it already.
+func (t entryTags) add(name, tag string) {
+	for _, v := range t[name] {
+		if v == tag {
+			return
+		}
+	}
+	t[name] = append(t[name], tag)
+	sort.Strings(t[name])
+}
+
+// remove tag from the named entry.
+func (t entryTags) remove(name, tag string) {
+	var kept []string
+	for _, v := range t[name] {
+		if v != tag {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == 0 {
+		delete(t, name)
+		return
+	}
+	t[name] = kept
+}
+
+// tagged returns the names of the entries that have any of tags.
+func (t entryTags) tagged(tags []string) map[string]bool {
+	names := map[string]bool{}
+	for name, have := range t {
+		for _, v := range have {
+			for _, tag := range tags {
+				if v == tag {
+					names[name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// tagsPath returns the path of the sidecar file with the tags of the
+// entries of the corpus directory dir. It is kept next to the directory,
+// rather than in it, as Go takes every file there for a corpus entry.
+func tagsPath(dir string) string {
+	return filepath.Clean(dir) + tagsSuffix
+}
+
+const tagsSuffix = ".fuzzdump-tags.json"
+
+// readTags of the entries of the corpus directory dir. If there is no
+// sidecar file with tags, there are none.
+func readTags(dir string) (entryTags, error) {
+	tags := entryTags{}
+	b, err := os.ReadFile(tagsPath(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return tags, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &tags); err != nil {
+		return nil, fmt.Errorf("reading %q: %w", tagsPath(dir), err)
+	}
+	return tags, nil
+}
+
+// writeTags of the entries of the corpus directory dir to its sidecar
+// file, or remove the file, if there are no tags.
+func writeTags(dir string, tags entryTags) error {
+	name := tagsPath(dir)
+	if len(tags) == 0 {
+		if err := wfs.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return writeFile(name, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(tags)
+	})
+}
+
+// filterTagged returns those of files (read from src) that have any of
+// tags.
+func filterTagged(files []corpus.File, src string, tags []string) ([]corpus.File, error) {
+	t, err := readTags(src)
+	if err != nil {
+		return nil, err
+	}
+	names := t.tagged(tags)
+	var kept []corpus.File
+	for _, f := range files {
+		if names[f.Name] {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
+
+// newTaggedFS returns fsys with the entries of dir in it (at path in the
+// host file system) limited to those that have any of tags.
+func newTaggedFS(fsys fs.FS, dir, path string, tags []string) (fs.FS, error) {
+	t, err := readTags(path)
+	if err != nil {
+		return nil, err
+	}
+	return selectedFS{fsys, dir, t.tagged(tags)}, nil
+}
+
+var (
+	errTagArgs = errors.New("directory path argument, and a tag with entry name prefixes, if any, required")
+	errBadTag  = errors.New("tag must be non-empty and not contain commas")
+)
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tagMain(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{
+		"abc1": "int(1)",
+		"abd2": "int(2)",
+		"x":    "int(42)",
+	})
+	req := require.New(t)
+	tag := func(args ...string) string {
+		t.Helper()
+		if len(args) > 0 && args[0] == "-d" {
+			args = append([]string{"-d", dir}, args[1:]...)
+		} else {
+			args = append([]string{dir}, args...)
+		}
+		stdOut := &bytes.Buffer{}
+		req.NoError(tagMain(stdOut, io.Discard, args))
+		return stdOut.String()
+	}
+	req.Empty(tag())
+	tag("slow", "abc", "x")
+	tag("regression-1234", "abd")
+	tag("slow", "x") // Not added twice.
+	req.Equal("abc1\tslow\nabd2\tregression-1234\nx\tslow\n", tag())
+
+	dump := func(args ...string) string {
+		t.Helper()
+		stdOut := &bytes.Buffer{}
+		req.NoError(dumpMain(stdOut, io.Discard, append(args, dir)))
+		return stdOut.String()
+	}
+	req.Equal("{\n\tint(1),\n\tint(42),\n}\n", dump("-tags", "slow"))
+	req.Equal("{\n\tint(1),\n\tint(2),\n\tint(42),\n}\n",
+		dump("-tags", "slow,regression-1234"))
+
+	tag("-d", "slow", "abc")
+	tag("-d", "regression-1234", "abd")
+	req.Equal("x\tslow\n", tag())
+	tag("-d", "slow", "x")
+	req.Empty(tag())
+	// The sidecar file is gone with the last tag.
+	_, err := os.Stat(tagsPath(dir))
+	req.ErrorIs(err, os.ErrNotExist)
+}
+
+func Test_tagMain_errors(t *testing.T) {
+	dir := writeCorpus(t, map[string]string{"abc1": "int(1)", "abd2": "int(2)"})
+	tests := map[string]struct {
+		args []string
+		wErr error
+	}{"no args": {
+		wErr: errTagArgs,
+	}, "no prefix": {
+		args: []string{dir, "slow"},
+		wErr: errTagArgs,
+	}, "empty tag": {
+		args: []string{dir, "", "abc"},
+		wErr: errBadTag,
+	}, "comma in tag": {
+		args: []string{dir, "a,b", "abc"},
+		wErr: errBadTag,
+	}, "ambiguous": {
+		args: []string{dir, "slow", "ab"},
+		wErr: errAmbiguousEntry,
+	}, "absent dir": {
+		args: []string{filepath.Join(dir, "absent"), "slow", "ab"},
+		wErr: os.ErrNotExist,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := tagMain(io.Discard, io.Discard, tt.args)
+			require.ErrorIs(t, err, tt.wErr)
+		})
+	}
+	// Nothing is written when tagging fails.
+	_, err := os.Stat(tagsPath(dir))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_convertMain_tags(t *testing.T) {
+	src := writeCorpus(t, map[string]string{"abc1": "int(1)", "abd2": "int(2)"})
+	req := require.New(t)
+	req.NoError(writeTags(src, entryTags{"abd2": {"slow"}}))
+	dst := filepath.Join(t.TempDir(), "dst")
+	req.NoError(convertMain(io.Discard, io.Discard, []string{"-tags", "slow", src, dst}))
+	entries, err := os.ReadDir(dst)
+	req.NoError(err)
+	req.Len(entries, 1)
+	got, err := os.ReadFile(filepath.Join(dst, entries[0].Name()))
+	req.NoError(err)
+	req.Equal("go test fuzz v1\nint(2)\n", string(got))
+}
@@ -0,0 +1,116 @@
+package fuzzdump
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// DumpDirColumns writes the entries from a fuzz test corpus directory
+// to w as a table: a line per entry, with its values aligned into
+// columns padded with spaces, e.g.:
+//
+//	int(8)     string("foo")  bool(true)
+//	int(1024)  string("")     bool(false)
+//	int(13)    string("bar")  bool(true)
+//
+// This makes the patterns in the values across the corpus obvious, as
+// long as they are short: a value wider than 32 characters does not
+// widen its column, but overflows it, shifting the rest of its line.
+//
+// Comments, such as those of [WithEntryComments], are written on lines
+// of their own ahead of their entries. Every entry is held in memory
+// until all of them are read, to know how wide the columns have to be.
+// Errors are reported as with [DumpDir], and so are opts applied, save
+// for those that concern the framing and the argument labels of the
+// dump format.
+func DumpDirColumns(w io.Writer, fsys fs.FS, dir string, opts ...Option) error {
+	o := newOptions(opts)
+	o.retain = true
+	return dump(w, fsys, dir, o, func(w io.Writer, argCount int) entryPrinter {
+		return &columnPrinter{w: w, widths: make([]int, argCount)}
+	})
+}
+
+// maxColumnWidth is the widest, in characters, that [DumpDirColumns]
+// pads the columns to.
+const maxColumnWidth = 32
+
+// A columnPrinter renders the entries of a dump as a table, see
+// [DumpDirColumns].
+type columnPrinter struct {
+	w       io.Writer
+	rows    []columnRow
+	widths  []int    // Of the columns, up to maxColumnWidth.
+	omitted int      // Entries to be noted omitted before the next one.
+	comment []string // Lines of the comment on the next entry.
+}
+
+// A columnRow holds an entry of a columnPrinter, along with the lines
+// to write ahead of it.
+type columnRow struct {
+	notes []string
+	cells corpus.Entry
+}
+
+func (p *columnPrinter) Begin() error { return nil }
+
+func (p *columnPrinter) Entry(e corpus.Entry) error {
+	var notes []string
+	if p.omitted > 0 {
+		notes = append(notes, strings.TrimSuffix(omittedNote(p.omitted), "\n"))
+		p.omitted = 0
+	}
+	for _, l := range p.comment {
+		notes = append(notes, "// "+l)
+	}
+	p.comment = nil
+	for i, c := range e {
+		if n := utf8.RuneCount(c); i < len(p.widths) &&
+			n > p.widths[i] && n <= maxColumnWidth {
+			p.widths[i] = n
+		}
+	}
+	p.rows = append(p.rows, columnRow{notes, e})
+	return nil
+}
+
+func (p *columnPrinter) Omit(n int) { p.omitted += n }
+
+func (p *columnPrinter) Comment(text string) {
+	p.comment = nil
+	if text != "" {
+		p.comment = strings.Split(text, "\n")
+	}
+}
+
+// End writes all the entries, now that the widths of the columns are
+// known.
+func (p *columnPrinter) End() error {
+	b := &strings.Builder{}
+	for _, r := range p.rows {
+		for _, l := range r.notes {
+			b.WriteString(l + "\n")
+		}
+		for i, c := range r.cells {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.Write(c)
+			if i < len(r.cells)-1 && i < len(p.widths) {
+				if pad := p.widths[i] - utf8.RuneCount(c); pad > 0 {
+					b.WriteString(strings.Repeat(" ", pad))
+				}
+			}
+		}
+		b.WriteByte('\n')
+	}
+	if p.omitted > 0 {
+		b.WriteString(omittedNote(p.omitted))
+	}
+	_, err := io.WriteString(p.w, b.String())
+	return writeErr(err)
+}
@@ -0,0 +1,67 @@
+package fuzzdump_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDirColumns(t *testing.T) {
+	const dir = "corpus"
+	long := `string("` + strings.Repeat("x", 40) + `")`
+	tests := map[string]struct {
+		files map[string]string
+		opts  []Option
+		want  string
+	}{"aligned": {
+		files: map[string]string{
+			"1": "int(8)\nstring(\"foo\")\nbool(true)",
+			"2": "int(1024)\nstring(\"\")\nbool(false)",
+		},
+		want: "int(8)     string(\"foo\")  bool(true)\n" +
+			"int(1024)  string(\"\")     bool(false)\n",
+	}, "overflow": {
+		files: map[string]string{
+			"1": "int(1)\n" + long + "\nbool(true)",
+			"2": "int(2)\nstring(\"ab\")\nbool(false)",
+		},
+		want: "int(1)  " + long + "  bool(true)\n" +
+			"int(2)  string(\"ab\")  bool(false)\n",
+	}, "single arg": {
+		files: map[string]string{"1": "int(1)", "2": "int(1000)"},
+		want:  "int(1)\nint(1000)\n",
+	}, "comments": {
+		files: map[string]string{
+			"1": "int(1)\nint(2)",
+			"2": "int(300)\nint(4)",
+		},
+		opts: []Option{WithEntryComments(func(name string) string {
+			return "file " + name
+		})},
+		want: "// file 1\nint(1)    int(2)\n// file 2\nint(300)  int(4)\n",
+	}, "preview": {
+		files: map[string]string{
+			"1": "int(1)", "2": "int(2)", "3": "int(3)", "4": "int(4)", "5": "int(5)",
+		},
+		opts: []Option{WithPreview(1)},
+		want: "int(1)\n// ... 3 entries omitted\nint(5)\n",
+	}, "empty": {
+		opts: []Option{WithAllowEmpty()},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			fsys := fstest.MapFS{dir: {Mode: fs.ModeDir}}
+			for name, s := range tt.files {
+				fsys[dir+"/"+name] = corpusFile(s)
+			}
+			w := &strings.Builder{}
+			req := require.New(t)
+			req.NoError(DumpDirColumns(w, fsys, dir, tt.opts...))
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
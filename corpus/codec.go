@@ -0,0 +1,127 @@
+package corpus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// A Decoder reads a corpus entry from an input stream.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads the whole input stream and returns the entry it holds.
+//
+// Only the structure of the entry is validated, its values are not
+// decoded. An input that lacks the version header or values is reported
+// as [ErrMalformedEntry], one with a different header, as
+// [ErrUnsupportedVersion], either in an [EntryError].
+func (d *Decoder) Decode() (Entry, error) {
+	b, err := io.ReadAll(d.r)
+	if err != nil {
+		return nil, err
+	}
+	return Unmarshal(b)
+}
+
+// Unmarshal returns the entry that data holds, as [Decoder.Decode] does.
+func Unmarshal(data []byte) (Entry, error) {
+	return UnmarshalVersions(data, Version1)
+}
+
+// UnmarshalVersions returns the entry that data holds, like [Unmarshal]
+// does, but accepting any of the given version headers instead of just
+// [Version1], e.g., for a toolchain patched to write a different one.
+func UnmarshalVersions(data []byte, versions ...string) (e Entry, err error) {
+	s := bytes.Split(data, []byte("\n"))
+	if len(s) < 2 {
+		// Not enough lines, so no point checking the version.
+		return nil, &EntryError{Line: len(s), Err: ErrMalformedEntry}
+	}
+	if v := strings.TrimSuffix(string(s[0]), "\r"); !contains(versions, v) {
+		return nil, &EntryError{Line: 1, Excerpt: excerpt([]byte(v)), Err: ErrUnsupportedVersion}
+	}
+	for _, v := range s[1:] {
+		line := bytes.TrimSpace(v)
+		if len(line) == 0 {
+			continue
+		}
+		e = append(e, line)
+	}
+	if len(e) < 1 {
+		// Missing where the first value would be.
+		return nil, &EntryError{Line: 2, Err: ErrMalformedEntry}
+	}
+	return
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadFile reads the entry from the named file in fsys.
+func ReadFile(fsys fs.FS, name string) (Entry, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return Unmarshal(b)
+}
+
+// An Encoder writes corpus entries to an output stream.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes e to the output stream in version 1 encoding, as the Go
+// toolchain does. An entry without values is reported as
+// [ErrMalformedEntry].
+func (enc *Encoder) Encode(e Entry) error {
+	b, err := Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.Write(b)
+	return err
+}
+
+// Marshal returns the version 1 encoding of e, as [Encoder.Encode]
+// writes it.
+func Marshal(e Entry) ([]byte, error) {
+	if len(e) == 0 {
+		return nil, ErrMalformedEntry
+	}
+	b := &bytes.Buffer{}
+	b.WriteString(Version1 + "\n")
+	for _, l := range e {
+		b.Write(l)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// FileName returns the name that the Go toolchain gives to a corpus
+// entry file with the given data: a prefix of its SHA-256 hash in
+// hexadecimal.
+func FileName(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+}
@@ -0,0 +1,155 @@
+package corpus_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshal(t *testing.T) {
+	type bs = []byte
+	tests := map[string]struct {
+		data     string
+		want     Entry
+		wErr     error
+		wErrText string
+	}{"empty": {
+		wErr:     ErrMalformedEntry,
+		wErrText: "line 1: " + ErrMalformedEntry.Error(),
+	}, "version only": {
+		data: Version1,
+		wErr: ErrMalformedEntry,
+	}, "bad version": {
+		data:     "foo\n",
+		wErr:     ErrUnsupportedVersion,
+		wErrText: `line 1: ` + ErrUnsupportedVersion.Error() + `: "foo"`,
+	}, "no values": {
+		data:     Version1 + "\n\n \n",
+		wErr:     ErrMalformedEntry,
+		wErrText: "line 2: " + ErrMalformedEntry.Error(),
+	}, "nominal": {
+		data: Version1 + "\n\nint(1)\n  string(\"foo\")\n",
+		want: Entry{bs("int(1)"), bs(`string("foo")`)},
+	}, "CRLF": {
+		data: Version1 + "\r\nint(1)\r\n",
+		want: Entry{bs("int(1)")},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := Unmarshal([]byte(tt.data))
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				if tt.wErrText != "" {
+					req.EqualError(err, tt.wErrText)
+				}
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshalVersions(t *testing.T) {
+	const custom = "acme fuzz v1"
+	tests := map[string]struct {
+		data     string
+		versions []string
+		wErr     error
+	}{"custom": {
+		data:     custom + "\nint(1)\n",
+		versions: []string{Version1, custom},
+	}, "v1": {
+		data:     Version1 + "\nint(1)\n",
+		versions: []string{Version1, custom},
+	}, "not accepted": {
+		data:     Version1 + "\nint(1)\n",
+		versions: []string{custom},
+		wErr:     ErrUnsupportedVersion,
+	}, "none accepted": {
+		data: Version1 + "\nint(1)\n",
+		wErr: ErrUnsupportedVersion,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := UnmarshalVersions([]byte(tt.data), tt.versions...)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(Entry{[]byte("int(1)")}, got)
+		})
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		got, err := NewDecoder(strings.NewReader(Version1 + "\nint(1)\n")).Decode()
+		req := require.New(t)
+		req.NoError(err)
+		req.Equal(Entry{[]byte("int(1)")}, got)
+	})
+	t.Run("read error", func(t *testing.T) {
+		_, err := NewDecoder(errReader{}).Decode()
+		require.ErrorIs(t, err, errSnap)
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	fsys := fstest.MapFS{"1": {Data: []byte(Version1 + "\nint(1)\n")}}
+	t.Run("nominal", func(t *testing.T) {
+		got, err := ReadFile(fsys, "1")
+		req := require.New(t)
+		req.NoError(err)
+		req.Equal(Entry{[]byte("int(1)")}, got)
+	})
+	t.Run("absent", func(t *testing.T) {
+		_, err := ReadFile(fsys, "2")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	e, err := NewEntry(42, "foo")
+	req := require.New(t)
+	req.NoError(err)
+
+	b := &bytes.Buffer{}
+	req.NoError(NewEncoder(b).Encode(e))
+	req.Equal(Version1+"\nint(42)\nstring(\"foo\")\n", b.String())
+
+	got, err := NewDecoder(b).Decode()
+	req.NoError(err)
+	req.Equal(e, got)
+
+	t.Run("no values", func(t *testing.T) {
+		err := NewEncoder(io.Discard).Encode(nil)
+		require.ErrorIs(t, err, ErrMalformedEntry)
+	})
+	t.Run("write error", func(t *testing.T) {
+		err := NewEncoder(errWriter{}).Encode(e)
+		require.ErrorIs(t, err, errSnap)
+	})
+}
+
+var errSnap = errors.New("snap")
+
+// errReader returns errSnap on all Read calls.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errSnap }
+
+// errWriter returns errSnap on all Write calls.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errSnap }
@@ -0,0 +1,82 @@
+// Package corpus implements reading and writing Go fuzz test corpus
+// entries, as stored by the Go toolchain in the files of a corpus
+// directory, e.g. testdata/fuzz/FuzzMyFunc.
+//
+// A corpus entry file has the version 1 encoding header on its first
+// line, followed by the values of the fuzz arguments, one per line,
+// each as a Go conversion expression, e.g.:
+//
+//	go test fuzz v1
+//	int(42)
+//	string("foo")
+package corpus
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+)
+
+// Version1 is the first line of a file with version 1 encoding.
+const Version1 = "go test fuzz v1"
+
+// A Value is a decoded fuzz argument value, of one of the types
+// supported by Go fuzzing, e.g. an int or a []byte.
+type Value = any
+
+// An Entry is a corpus entry: the encoded values of the fuzz arguments,
+// one per line, e.g. `int(42)`.
+//
+// The lines are kept as they were read, so that an entry can be dumped
+// or written back exactly. Use [Entry.Values] to decode them.
+type Entry [][]byte
+
+// NewEntry returns the entry encoding vals.
+func NewEntry(vals ...Value) (e Entry, err error) {
+	e = make(Entry, len(vals))
+	for i, v := range vals {
+		if e[i], err = EncodeValue(v); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// Values returns the values that the lines of e represent.
+//
+// A line that cannot be decoded is reported as [ErrMalformedValue] in
+// an [EntryError].
+func (e Entry) Values() (vals []Value, err error) {
+	vals = make([]Value, len(e))
+	for i, l := range e {
+		if vals[i], err = DecodeValue(l); err != nil {
+			return nil, valueError(i, l, err)
+		}
+	}
+	return
+}
+
+// valueError returns the [EntryError] reporting that line, the value at
+// index i of an entry, cannot be decoded, for the reason given by err.
+func valueError(i int, line []byte, err error) error {
+	e := &EntryError{
+		Line:    i + 2, // After the version header, numbered from 1.
+		Excerpt: excerpt(line),
+	}
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		e.Column, err = list[0].Pos.Column, errors.New(list[0].Msg)
+	}
+	e.Err = fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	return e
+}
+
+// Normalize decodes each of the lines of e and encodes them anew,
+// returning the resulting entry.
+func (e Entry) Normalize() (Entry, error) {
+	vals, err := e.Values()
+	if err != nil {
+		return nil, err
+	}
+	return NewEntry(vals...)
+}
@@ -0,0 +1,51 @@
+package corpus_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		got, err := NewEntry(uint8('a'), []byte("b"))
+		req := require.New(t)
+		req.NoError(err)
+		req.Equal(Entry{[]byte("byte('a')"), []byte(`[]byte("b")`)}, got)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewEntry(1, struct{}{})
+		require.EqualError(t, err, "unsupported value type struct {}")
+	})
+}
+
+func TestEntry_Values(t *testing.T) {
+	t.Run("nominal", func(t *testing.T) {
+		got, err := Entry{[]byte("int(1)"), []byte("bool(true)")}.Values()
+		req := require.New(t)
+		req.NoError(err)
+		req.Equal([]Value{1, true}, got)
+	})
+	t.Run("malformed", func(t *testing.T) {
+		_, err := Entry{[]byte("int(1)"), []byte("int(")}.Values()
+		req := require.New(t)
+		req.ErrorIs(err, ErrMalformedValue)
+		var e *EntryError
+		req.True(errors.As(err, &e))
+		req.Equal(3, e.Line)
+		req.Equal(5, e.Column)
+		req.Equal("int(", e.Excerpt)
+		req.EqualError(err, `line 3, column 5: malformed value: expected ')', found 'EOF': "int("`)
+	})
+	t.Run("long", func(t *testing.T) {
+		long := `string("x` + strings.Repeat("\u00e9", 20) + `)`
+		_, err := Entry{[]byte(long)}.Values()
+		var e *EntryError
+		req := require.New(t)
+		req.True(errors.As(err, &e))
+		req.Equal(long[:39]+"...", e.Excerpt) // Not cut mid-character.
+	})
+}
@@ -0,0 +1,77 @@
+package corpus
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrMalformedEntry is returned when a corpus entry does not have a
+// supported format.
+const ErrMalformedEntry Error = "must include version and at least one value"
+
+// ErrUnsupportedVersion is returned when a corpus entry does not have a
+// supported version header.
+const ErrUnsupportedVersion Error = "unsupported encoding version"
+
+// ErrMalformedValue is returned when a value in a corpus entry cannot
+// be decoded.
+const ErrMalformedValue Error = "malformed value"
+
+// Error is a plain string type and can be used to define a constant.
+type Error string
+
+// Implements the [error] interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// An EntryError reports an error with a corpus entry at the position in
+// its encoding that it concerns, so that [errors.As] tells where in the
+// entry file the problem is, e.g.:
+//
+//	line 3, column 6: malformed value: missing ',' before newline in argument list: "int(2"
+//
+// The lines are numbered as [Marshal] would encode the entry, which, for
+// a file that Go wrote, is the same as in the file.
+type EntryError struct {
+	Line    int    // Number of the line, from 1.
+	Column  int    // Of the byte in the line, from 1, or 0, if not known.
+	Excerpt string // Of the line, shortened, if it is long.
+	Err     error
+}
+
+// Implements the [error] interface.
+func (e *EntryError) Error() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "line %d", e.Line)
+	if e.Column > 0 {
+		fmt.Fprintf(b, ", column %d", e.Column)
+	}
+	fmt.Fprintf(b, ": %v", e.Err)
+	if e.Excerpt != "" {
+		fmt.Fprintf(b, ": %q", e.Excerpt)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+// Implements the interface required by [errors.Unwrap].
+func (e *EntryError) Unwrap() error { return e.Err }
+
+// excerpt returns line, cut short after maxExcerpt bytes, if it is any
+// longer, on a UTF-8 character boundary.
+func excerpt(line []byte) string {
+	if len(line) <= maxExcerpt {
+		return string(line)
+	}
+	n := maxExcerpt
+	for n > 0 && !utf8.RuneStart(line[n]) {
+		n--
+	}
+	return string(line[:n]) + "..."
+}
+
+// maxExcerpt is the longest, in bytes, that an [EntryError] quotes a
+// line.
+const maxExcerpt = 40
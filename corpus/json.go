@@ -0,0 +1,191 @@
+package corpus
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// A File is a corpus entry along with the name of the file it is stored
+// in, as represented in a JSON dump, and the metadata of the file, where
+// it has been read from one.
+//
+// The metadata is left out of a JSON dump, for that to only change when
+// the entries do.
+type File struct {
+	Name  string `json:"name"`
+	Entry Entry  `json:"values"`
+	// Size of the file in bytes.
+	Size int64 `json:"-"`
+	// ModTime is the modification time of the file.
+	ModTime time.Time `json:"-"`
+	// Hash is the SHA-256 hash of the entry, as encoded in a version 1
+	// entry file, which is what the Go toolchain names its files by.
+	Hash [sha256.Size]byte `json:"-"`
+}
+
+// MarshalJSON implements the [json.Marshaler] interface, encoding e as
+// an array of its typed values, e.g.:
+//
+//	[{"type":"int","value":42},{"type":"string","value":"foo"}]
+//
+// The encoding is lossless: integers are written as exact JSON numbers,
+// []byte values and strings that are not valid UTF-8 in base64, and the
+// floats that JSON has no numbers for, as "NaN", "+Inf" or "-Inf" (or
+// with their "bits" in hexadecimal, for a NaN with an unusual bit
+// pattern).
+//
+// A line of e that cannot be decoded is reported as [ErrMalformedValue].
+func (e Entry) MarshalJSON() ([]byte, error) {
+	vals, err := e.Values()
+	if err != nil {
+		return nil, err
+	}
+	jvs := make([]jsonValue, len(vals))
+	for i, v := range vals {
+		if jvs[i], err = newJSONValue(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(jvs)
+}
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface, decoding
+// the values that [Entry.MarshalJSON] encodes. The "byte" and "rune"
+// type names are accepted as well.
+//
+// A value that cannot be decoded is reported as [ErrMalformedValue].
+func (e *Entry) UnmarshalJSON(b []byte) error {
+	var jvs []jsonValue
+	if err := json.Unmarshal(b, &jvs); err != nil {
+		return err
+	}
+	vals := make([]Value, len(jvs))
+	for i, jv := range jvs {
+		v, err := jv.value()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrMalformedValue, jv.Type, err)
+		}
+		vals[i] = v
+	}
+	entry, err := NewEntry(vals...)
+	if err != nil {
+		return err
+	}
+	*e = entry
+	return nil
+}
+
+// jsonValue is the JSON representation of a single value.
+type jsonValue struct {
+	Type   string          `json:"type"`
+	Value  json.RawMessage `json:"value,omitempty"`
+	Base64 string          `json:"base64,omitempty"`
+	Bits   string          `json:"bits,omitempty"`
+}
+
+func newJSONValue(v Value) (jv jsonValue, err error) {
+	jv.Type = fmt.Sprintf("%T", v)
+	var lit string
+	switch t := v.(type) {
+	case int, int8, int16, int32, int64:
+		lit = fmt.Sprint(t)
+	case uint, uint8, uint16, uint32, uint64:
+		lit = fmt.Sprint(t)
+	case float32:
+		if b := math.Float32bits(t); math.IsNaN(float64(t)) &&
+			b != math.Float32bits(float32(math.NaN())) {
+			jv.Bits = hexBits(uint64(b))
+			return
+		}
+		lit = jsonFloat(float64(t), 32)
+	case float64:
+		if b := math.Float64bits(t); math.IsNaN(t) &&
+			b != math.Float64bits(math.NaN()) {
+			jv.Bits = hexBits(b)
+			return
+		}
+		lit = jsonFloat(t, 64)
+	case bool:
+		lit = strconv.FormatBool(t)
+	case string:
+		if !utf8.ValidString(t) {
+			jv.Base64 = base64.StdEncoding.EncodeToString([]byte(t))
+			return
+		}
+		jv.Value, err = json.Marshal(t)
+		return
+	case []byte:
+		jv.Type = "[]byte"
+		jv.Value, err = json.Marshal(t)
+		return
+	default:
+		return jv, fmt.Errorf("unsupported value type %T", v)
+	}
+	jv.Value = json.RawMessage(lit)
+	return
+}
+
+// jsonFloat returns f as a JSON number, or as a JSON string if it is
+// not finite.
+func jsonFloat(f float64, bitSize int) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.Quote(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+func (jv jsonValue) value() (Value, error) {
+	switch jv.Type {
+	case "string":
+		if jv.Base64 != "" {
+			b, err := base64.StdEncoding.DecodeString(jv.Base64)
+			return string(b), err
+		}
+		var s string
+		err := json.Unmarshal(jv.Value, &s)
+		return s, err
+	case "[]byte":
+		var b []byte
+		err := json.Unmarshal(jv.Value, &b)
+		return b, err
+	case "bool":
+		var b bool
+		err := json.Unmarshal(jv.Value, &b)
+		return b, err
+	case "float32", "float64":
+		if jv.Bits != "" {
+			typ := float64Bits
+			if jv.Type == "float32" {
+				typ = float32Bits
+			}
+			return parseFloatBits(jv.Bits, typ)
+		}
+		lit := string(jv.Value)
+		if s, err := strconv.Unquote(lit); err == nil {
+			switch s {
+			case "NaN", "+Inf", "-Inf":
+				lit = s
+			default:
+				return nil, fmt.Errorf("NaN, +Inf or -Inf required, got %q", s)
+			}
+		}
+		return parseFloat(lit, jv.Type)
+	case "int", "int8", "int16", "int32", "int64", "rune",
+		"uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		if len(jv.Value) == 0 || jv.Value[0] == '"' {
+			return nil, fmt.Errorf("number required for %s", jv.Type)
+		}
+		var n json.Number
+		if err := json.Unmarshal(jv.Value, &n); err != nil {
+			return nil, err
+		}
+		return parseInt(n.String(), jv.Type)
+	}
+	return nil, fmt.Errorf("unsupported type %s", jv.Type)
+}
@@ -0,0 +1,123 @@
+package corpus_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntry_MarshalJSON(t *testing.T) {
+	tests := map[string]struct {
+		v    Value
+		want string
+	}{
+		"int":            {v: -42, want: `{"type":"int","value":-42}`},
+		"int8":           {v: int8(1), want: `{"type":"int8","value":1}`},
+		"int16":          {v: int16(1), want: `{"type":"int16","value":1}`},
+		"int32":          {v: int32(-1), want: `{"type":"int32","value":-1}`},
+		"int64":          {v: int64(math.MinInt64), want: `{"type":"int64","value":-9223372036854775808}`},
+		"uint":           {v: uint(1), want: `{"type":"uint","value":1}`},
+		"uint8":          {v: uint8('a'), want: `{"type":"uint8","value":97}`},
+		"uint16":         {v: uint16(1), want: `{"type":"uint16","value":1}`},
+		"uint32":         {v: uint32(1), want: `{"type":"uint32","value":1}`},
+		"uint64":         {v: uint64(math.MaxUint64), want: `{"type":"uint64","value":18446744073709551615}`},
+		"float32":        {v: float32(0.1), want: `{"type":"float32","value":0.1}`},
+		"float64":        {v: 1e21, want: `{"type":"float64","value":1e+21}`},
+		"float64 -0":     {v: math.Copysign(0, -1), want: `{"type":"float64","value":-0}`},
+		"float64 +Inf":   {v: math.Inf(1), want: `{"type":"float64","value":"+Inf"}`},
+		"float32 -Inf":   {v: float32(math.Inf(-1)), want: `{"type":"float32","value":"-Inf"}`},
+		"float64 NaN":    {v: math.NaN(), want: `{"type":"float64","value":"NaN"}`},
+		"bool":           {v: true, want: `{"type":"bool","value":true}`},
+		"string":         {v: "foo\n", want: `{"type":"string","value":"foo\n"}`},
+		"invalid string": {v: "\xff", want: `{"type":"string","base64":"/w=="}`},
+		"bytes":          {v: []byte("foo"), want: `{"type":"[]byte","value":"Zm9v"}`},
+		"float32 bits": {
+			v:    math.Float32frombits(0x7fc00001),
+			want: `{"type":"float32","bits":"0x7fc00001"}`,
+		},
+		"float64 bits": {
+			v:    math.Float64frombits(0x7ff8000000000002),
+			want: `{"type":"float64","bits":"0x7ff8000000000002"}`,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			e, err := NewEntry(tt.v)
+			req := require.New(t)
+			req.NoError(err)
+			got, err := json.Marshal(e)
+			req.NoError(err)
+			req.Equal("["+tt.want+"]", string(got))
+
+			// The encoding must be lossless.
+			var back Entry
+			req.NoError(json.Unmarshal(got, &back))
+			req.Equal(e, back)
+		})
+	}
+	t.Run("malformed", func(t *testing.T) {
+		_, err := json.Marshal(Entry{[]byte("int(")})
+		require.ErrorIs(t, err, ErrMalformedValue)
+	})
+}
+
+func TestEntry_UnmarshalJSON(t *testing.T) {
+	tests := map[string]struct {
+		json string
+		want string
+		wErr string
+	}{
+		"byte":          {json: `{"type":"byte","value":97}`, want: "byte('a')"},
+		"rune":          {json: `{"type":"rune","value":97}`, want: "rune('a')"},
+		"no type":       {json: `{}`, wErr: "unsupported type"},
+		"unknown type":  {json: `{"type":"complex64"}`, wErr: "unsupported type complex64"},
+		"int float":     {json: `{"type":"int","value":1.5}`, wErr: "invalid syntax"},
+		"int overflow":  {json: `{"type":"int8","value":128}`, wErr: "out of range"},
+		"int string":    {json: `{"type":"int","value":"1"}`, wErr: "number required for int"},
+		"float string":  {json: `{"type":"float64","value":"1"}`, wErr: "NaN, +Inf or -Inf required"},
+		"float missing": {json: `{"type":"float64"}`, wErr: "malformed value"},
+		"bad bits":      {json: `{"type":"float32","bits":"x"}`, wErr: "invalid syntax"},
+		"bad base64":    {json: `{"type":"string","base64":"!"}`, wErr: "illegal base64"},
+		"bool number":   {json: `{"type":"bool","value":1}`, wErr: "cannot unmarshal"},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var e Entry
+			err := json.Unmarshal([]byte("["+tt.json+"]"), &e)
+			req := require.New(t)
+			if tt.wErr != "" {
+				req.ErrorContains(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(Entry{[]byte(tt.want)}, e)
+		})
+	}
+}
+
+func TestEntry_UnmarshalJSON_notArray(t *testing.T) {
+	var e Entry
+	err := json.Unmarshal([]byte(`{}`), &e)
+	require.ErrorContains(t, err, "cannot unmarshal")
+}
+
+func TestFile_json(t *testing.T) {
+	f := File{Name: "foo", Entry: Entry{[]byte("int(1)"), []byte("bool(false)")}}
+	b, err := json.Marshal(f)
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal(`{"name":"foo","values":[{"type":"int","value":1},`+
+		`{"type":"bool","value":false}]}`, string(b))
+	var back File
+	req.NoError(json.Unmarshal(b, &back))
+	req.Equal(f, back)
+}
+
+func TestFileName(t *testing.T) {
+	// As named by the Go toolchain.
+	data := []byte(Version1 + "\nint(42)\n")
+	require.Equal(t, "901ae842a79a0767", FileName(data))
+}
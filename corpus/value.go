@@ -0,0 +1,286 @@
+package corpus
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DecodeValue decodes a single corpus value line, such as `int(42)` or
+// `[]byte("foo")`, into the Go value it represents.
+//
+// It accepts the same syntax as the Go toolchain does when reading a
+// version 1 encoded corpus entry, so the concrete type of the returned
+// value is one of the types supported by Go fuzzing.
+func DecodeValue(line []byte) (v Value, err error) {
+	expr, err := parser.ParseExpr(string(line))
+	if err != nil {
+		return
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, errors.New("expected call expression")
+	}
+	if l := len(call.Args); l != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", l)
+	}
+	arg := call.Args[0]
+
+	typ, err := valueTypeName(call.Fun)
+	if err != nil {
+		return
+	}
+	switch typ {
+	case "[]byte":
+		s, err := stringLit(arg)
+		return []byte(s), err
+	case "string":
+		return stringLit(arg)
+	case "bool":
+		return boolLit(arg)
+	}
+
+	lit, kind, err := numericLit(arg)
+	if err != nil {
+		return
+	}
+	switch typ {
+	case "byte", "rune":
+		if kind == token.INT {
+			return parseInt(lit, typ)
+		}
+		if kind != token.CHAR {
+			return nil, fmt.Errorf("character literal required for %s", typ)
+		}
+		return parseChar(lit, typ)
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		if kind != token.INT {
+			return nil, fmt.Errorf("integer literal required for %s", typ)
+		}
+		return parseInt(lit, typ)
+	case "float32", "float64":
+		if kind != token.FLOAT && kind != token.INT {
+			return nil, fmt.Errorf("float literal required for %s", typ)
+		}
+		return parseFloat(lit, typ)
+	case float32Bits, float64Bits:
+		if kind != token.INT {
+			return nil, fmt.Errorf("integer literal required for %s", typ)
+		}
+		return parseFloatBits(lit, typ)
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// valueTypeName returns the name of the type (or the math conversion
+// function) in the fun part of a corpus value call expression.
+func valueTypeName(fun ast.Expr) (string, error) {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name, nil
+	case *ast.ArrayType:
+		if e, ok := f.Elt.(*ast.Ident); ok && f.Len == nil &&
+			(e.Name == "byte" || e.Name == "uint8") {
+			return "[]byte", nil
+		}
+	case *ast.SelectorExpr:
+		if x, ok := f.X.(*ast.Ident); ok && x.Name == "math" {
+			switch n := "math." + f.Sel.Name; n {
+			case float32Bits, float64Bits:
+				return n, nil
+			}
+		}
+	}
+	return "", errors.New("expected []byte or primitive type")
+}
+
+// Names of the math functions used to encode floats with unusual NaN
+// bit patterns.
+const (
+	float32Bits = "math.Float32frombits"
+	float64Bits = "math.Float64frombits"
+)
+
+func stringLit(arg ast.Expr) (string, error) {
+	if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		return strconv.Unquote(lit.Value)
+	}
+	return "", errors.New("string literal required")
+}
+
+func boolLit(arg ast.Expr) (bool, error) {
+	if id, ok := arg.(*ast.Ident); ok {
+		switch id.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, errors.New("true or false required for bool")
+}
+
+// numericLit returns the literal value and its kind from arg, which
+// must be a basic literal, optionally negated, or one of the special
+// float identifiers (NaN and, optionally signed, Inf).
+func numericLit(arg ast.Expr) (lit string, kind token.Token, err error) {
+	switch a := arg.(type) {
+	case *ast.BasicLit:
+		return a.Value, a.Kind, nil
+	case *ast.Ident:
+		if a.Name == "NaN" {
+			return a.Name, token.FLOAT, nil
+		}
+	case *ast.UnaryExpr:
+		if a.Op != token.SUB && a.Op != token.ADD {
+			break
+		}
+		switch x := a.X.(type) {
+		case *ast.BasicLit:
+			if a.Op == token.SUB {
+				return a.Op.String() + x.Value, x.Kind, nil
+			}
+		case *ast.Ident:
+			if x.Name == "Inf" {
+				return a.Op.String() + x.Name, token.FLOAT, nil
+			}
+		}
+	}
+	return "", token.ILLEGAL, errors.New("literal value required for primitive type")
+}
+
+func parseChar(lit, typ string) (any, error) {
+	if len(lit) < 2 {
+		return nil, errors.New("malformed character literal")
+	}
+	r, _, tail, err := strconv.UnquoteChar(lit[1:len(lit)-1], '\'')
+	if err != nil {
+		return nil, err
+	}
+	if tail != "" {
+		return nil, errors.New("more than one character in literal")
+	}
+	if typ == "rune" {
+		return r, nil
+	}
+	if r > math.MaxUint8 {
+		return nil, errors.New("character out of range for byte")
+	}
+	return byte(r), nil
+}
+
+func parseInt(lit, typ string) (v any, err error) {
+	switch typ {
+	case "int":
+		i, err := strconv.ParseInt(lit, 0, strconv.IntSize)
+		return int(i), err
+	case "int8":
+		i, err := strconv.ParseInt(lit, 0, 8)
+		return int8(i), err
+	case "int16":
+		i, err := strconv.ParseInt(lit, 0, 16)
+		return int16(i), err
+	case "int32", "rune":
+		i, err := strconv.ParseInt(lit, 0, 32)
+		return int32(i), err
+	case "int64":
+		return strconv.ParseInt(lit, 0, 64)
+	case "uint":
+		u, err := strconv.ParseUint(lit, 0, strconv.IntSize)
+		return uint(u), err
+	case "uint8", "byte":
+		u, err := strconv.ParseUint(lit, 0, 8)
+		return uint8(u), err
+	case "uint16":
+		u, err := strconv.ParseUint(lit, 0, 16)
+		return uint16(u), err
+	case "uint32":
+		u, err := strconv.ParseUint(lit, 0, 32)
+		return uint32(u), err
+	}
+	return strconv.ParseUint(lit, 0, 64)
+}
+
+func parseFloat(lit, typ string) (any, error) {
+	if typ == "float32" {
+		f, err := strconv.ParseFloat(lit, 32)
+		return float32(f), err
+	}
+	return strconv.ParseFloat(lit, 64)
+}
+
+func parseFloatBits(lit, typ string) (any, error) {
+	if typ == float32Bits {
+		u, err := strconv.ParseUint(lit, 0, 32)
+		return math.Float32frombits(uint32(u)), err
+	}
+	u, err := strconv.ParseUint(lit, 0, 64)
+	return math.Float64frombits(u), err
+}
+
+// EncodeValue encodes v as a corpus value line.
+//
+// The result matches what the Go toolchain writes to a version 1
+// encoded corpus entry, but it is produced using [strconv] directly, so
+// the same value is always rendered the same way.
+func EncodeValue(v Value) (line []byte, err error) {
+	switch t := v.(type) {
+	case int:
+		return typedLine("int", strconv.FormatInt(int64(t), 10)), nil
+	case int8:
+		return typedLine("int8", strconv.FormatInt(int64(t), 10)), nil
+	case int16:
+		return typedLine("int16", strconv.FormatInt(int64(t), 10)), nil
+	case int32:
+		// Only valid runes have a quoted representation.
+		if utf8.ValidRune(t) {
+			return typedLine("rune", strconv.QuoteRune(t)), nil
+		}
+		return typedLine("int32", strconv.FormatInt(int64(t), 10)), nil
+	case int64:
+		return typedLine("int64", strconv.FormatInt(t, 10)), nil
+	case uint:
+		return typedLine("uint", strconv.FormatUint(uint64(t), 10)), nil
+	case uint8:
+		return typedLine("byte", strconv.QuoteRune(rune(t))), nil
+	case uint16:
+		return typedLine("uint16", strconv.FormatUint(uint64(t), 10)), nil
+	case uint32:
+		return typedLine("uint32", strconv.FormatUint(uint64(t), 10)), nil
+	case uint64:
+		return typedLine("uint64", strconv.FormatUint(t, 10)), nil
+	case float32:
+		if b := math.Float32bits(t); math.IsNaN(float64(t)) &&
+			b != math.Float32bits(float32(math.NaN())) {
+			return typedLine(float32Bits, hexBits(uint64(b))), nil
+		}
+		return typedLine("float32", strconv.FormatFloat(float64(t), 'g', -1, 32)), nil
+	case float64:
+		if b := math.Float64bits(t); math.IsNaN(t) &&
+			b != math.Float64bits(math.NaN()) {
+			return typedLine(float64Bits, hexBits(b)), nil
+		}
+		return typedLine("float64", strconv.FormatFloat(t, 'g', -1, 64)), nil
+	case bool:
+		return typedLine("bool", strconv.FormatBool(t)), nil
+	case string:
+		return typedLine("string", strconv.Quote(t)), nil
+	case []byte:
+		return typedLine("[]byte", strconv.Quote(string(t))), nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+func typedLine(typ, lit string) []byte {
+	return []byte(typ + "(" + lit + ")")
+}
+
+func hexBits(b uint64) string {
+	return "0x" + strconv.FormatUint(b, 16)
+}
@@ -0,0 +1,172 @@
+package corpus_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeValue(t *testing.T) {
+	tests := map[string]struct {
+		line string
+		want any
+		wErr string
+	}{
+		"int":            {line: "int(-42)", want: -42},
+		"int8":           {line: "int8(0x7f)", want: int8(127)},
+		"int16":          {line: "int16(1_000)", want: int16(1000)},
+		"int32":          {line: "int32(-1)", want: int32(-1)},
+		"int64":          {line: "int64(1)", want: int64(1)},
+		"uint":           {line: "uint(1)", want: uint(1)},
+		"uint8":          {line: "uint8(255)", want: uint8(255)},
+		"uint16":         {line: "uint16(1)", want: uint16(1)},
+		"uint32":         {line: "uint32(1)", want: uint32(1)},
+		"uint64":         {line: "uint64(1)", want: uint64(1)},
+		"byte char":      {line: `byte('\x00')`, want: byte(0)},
+		"byte int":       {line: "byte(200)", want: byte(200)},
+		"rune char":      {line: "rune('ö')", want: 'ö'},
+		"rune int":       {line: "rune(-1)", want: int32(-1)},
+		"float32":        {line: "float32(1.5)", want: float32(1.5)},
+		"float64":        {line: "float64(-0.25)", want: -0.25},
+		"float64 int":    {line: "float64(3)", want: 3.0},
+		"float64 +Inf":   {line: "float64(+Inf)", want: math.Inf(1)},
+		"float64 -Inf":   {line: "float64(-Inf)", want: math.Inf(-1)},
+		"bool":           {line: "bool(true)", want: true},
+		"bool false":     {line: "bool(false)", want: false},
+		"string":         {line: `string("foo\n")`, want: "foo\n"},
+		"raw string":     {line: "string(`foo`)", want: "foo"},
+		"bytes":          {line: `[]byte("\xff")`, want: []byte{0xff}},
+		"uint8 bytes":    {line: `[]uint8("a")`, want: []byte("a")},
+		"float32 bits":   {line: "math.Float32frombits(0x3f800000)", want: float32(1)},
+		"float64 bits":   {line: "math.Float64frombits(0x3ff0000000000000)", want: 1.0},
+		"syntax":         {line: "int(", wErr: "expected"},
+		"not a call":     {line: "42", wErr: "expected call expression"},
+		"no args":        {line: "int()", wErr: "expected 1 argument, got 0"},
+		"unknown type":   {line: "complex128(1)", wErr: "unsupported type complex128"},
+		"array":          {line: `[1]byte("a")`, wErr: "expected []byte or primitive type"},
+		"selector":       {line: "math.Sqrt(2)", wErr: "expected []byte or primitive type"},
+		"bool literal":   {line: "bool(1)", wErr: "true or false required for bool"},
+		"string int":     {line: "string(1)", wErr: "string literal required"},
+		"int float":      {line: "int(1.5)", wErr: "integer literal required for int"},
+		"int overflow":   {line: "int8(128)", wErr: "out of range"},
+		"uint negative":  {line: "uint(-1)", wErr: "invalid syntax"},
+		"float string":   {line: `float64("1")`, wErr: "float literal required for float64"},
+		"byte string":    {line: `byte("a")`, wErr: "character literal required for byte"},
+		"byte range":     {line: "byte('ő')", wErr: "character out of range for byte"},
+		"bits float":     {line: "math.Float64frombits(1.5)", wErr: "integer literal required"},
+		"negated ident":  {line: "int(-x)", wErr: "literal value required"},
+		"complement":     {line: "int(^1)", wErr: "literal value required"},
+		"positive int":   {line: "int(+1)", wErr: "literal value required"},
+		"not a type":     {line: "foo.bar(1)", wErr: "expected []byte or primitive type"},
+		"unquoted char":  {line: `rune('ab')`, wErr: "illegal rune literal"},
+		"float32 ranged": {line: "float32(1e100)", wErr: "out of range"},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := DecodeValue([]byte(tt.line))
+			req := require.New(t)
+			if tt.wErr != "" {
+				req.ErrorContains(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+	t.Run("NaN", func(t *testing.T) {
+		got, err := DecodeValue([]byte("float64(NaN)"))
+		req := require.New(t)
+		req.NoError(err)
+		req.True(math.IsNaN(got.(float64)))
+	})
+}
+
+func TestEncodeValue(t *testing.T) {
+	tests := map[string]struct {
+		v    any
+		want string
+	}{
+		"int":          {v: -42, want: "int(-42)"},
+		"int8":         {v: int8(1), want: "int8(1)"},
+		"int16":        {v: int16(1), want: "int16(1)"},
+		"rune":         {v: 'ö', want: "rune('ö')"},
+		"int32":        {v: int32(-1), want: "int32(-1)"},
+		"int64":        {v: int64(1), want: "int64(1)"},
+		"uint":         {v: uint(1), want: "uint(1)"},
+		"byte":         {v: byte('a'), want: "byte('a')"},
+		"uint16":       {v: uint16(1), want: "uint16(1)"},
+		"uint32":       {v: uint32(1), want: "uint32(1)"},
+		"uint64":       {v: uint64(1), want: "uint64(1)"},
+		"float32":      {v: float32(0.1), want: "float32(0.1)"},
+		"float64":      {v: 1e21, want: "float64(1e+21)"},
+		"float64 -0":   {v: math.Copysign(0, -1), want: "float64(-0)"},
+		"float64 +Inf": {v: math.Inf(1), want: "float64(+Inf)"},
+		"float64 NaN":  {v: math.NaN(), want: "float64(NaN)"},
+		"float32 NaN":  {v: float32(math.NaN()), want: "float32(NaN)"},
+		"bool":         {v: true, want: "bool(true)"},
+		"string":       {v: "foo\n", want: `string("foo\n")`},
+		"bytes":        {v: []byte{0xff}, want: `[]byte("\xff")`},
+		"float32 bits": {
+			v:    math.Float32frombits(0x7fc00001),
+			want: "math.Float32frombits(0x7fc00001)",
+		},
+		"float64 bits": {
+			v:    math.Float64frombits(0x7ff8000000000002),
+			want: "math.Float64frombits(0x7ff8000000000002)",
+		},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := EncodeValue(tt.v)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.want, string(got))
+
+			// Every formatted value must decode to the original one.
+			v, err := DecodeValue(got)
+			req.NoError(err)
+			if f, ok := tt.v.(float64); ok && math.IsNaN(f) {
+				req.Equal(math.Float64bits(f), math.Float64bits(v.(float64)))
+				return
+			}
+			if f, ok := tt.v.(float32); ok && math.IsNaN(float64(f)) {
+				req.Equal(math.Float32bits(f), math.Float32bits(v.(float32)))
+				return
+			}
+			req.Equal(tt.v, v)
+		})
+	}
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := EncodeValue(complex(1, 1))
+		require.EqualError(t, err, "unsupported value type complex128")
+	})
+}
+
+func TestEntry_Normalize(t *testing.T) {
+	type bs = []byte
+	tests := map[string]struct {
+		lines Entry
+		want  Entry
+		wErr  error
+	}{"nominal": {
+		lines: Entry{bs("int( 0x10 )"), bs("byte(0x61)"), bs("[]uint8(`a`)")},
+		want:  Entry{bs("int(16)"), bs("byte('a')"), bs(`[]byte("a")`)},
+	}, "malformed": {
+		lines: Entry{bs("int(16)"), bs("foo")},
+		wErr:  ErrMalformedValue,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := tt.lines.Normalize()
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tt.want, got)
+		})
+	}
+}
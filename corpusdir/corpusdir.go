@@ -0,0 +1,60 @@
+// Package corpusdir writes Go fuzz test corpus directories, e.g., to
+// seed a fuzz test with inputs generated by other tools.
+//
+// Unlike the rest of this module, this package writes to the file
+// system, so it is kept separate from the read-only ones.
+package corpusdir
+
+import (
+	"path/filepath"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WriteDir writes the entries to the corpus directory dir, creating it,
+// if necessary, each to a file of its own, named as the Go toolchain
+// names it, so that go test uses them as seed inputs. An entry that is
+// already in dir is written over with the same contents, the other
+// files in dir are left as they are.
+//
+// Nothing is written if any of the entries cannot be encoded, such as
+// one without any values, reported as [corpus.ErrMalformedEntry].
+func WriteDir(dir string, entries []corpus.Entry) error {
+	return WriteDirFS(OS, dir, entries)
+}
+
+// WriteDirFS writes the entries to the corpus directory dir in fsys, as
+// [WriteDir] does to the local file system.
+func WriteDirFS(fsys FS, dir string, entries []corpus.Entry) error {
+	data := make([][]byte, len(entries))
+	for i, e := range entries {
+		var err error
+		if data[i], err = corpus.Marshal(e); err != nil {
+			return err
+		}
+	}
+	if err := fsys.MkdirAll(dir); err != nil {
+		return err
+	}
+	for _, d := range data {
+		name := filepath.Join(dir, corpus.FileName(d))
+		if err := WriteFile(fsys, name, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFile writes data to the named file in fsys, creating it, if
+// necessary, or truncating it otherwise.
+func WriteFile(fsys FS, name string, data []byte) error {
+	f, err := fsys.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package corpusdir_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	. "github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDir(t *testing.T) {
+	entry := func(vals ...corpus.Value) corpus.Entry {
+		e, err := corpus.NewEntry(vals...)
+		require.NoError(t, err)
+		return e
+	}
+	tests := map[string]struct {
+		entries []corpus.Entry
+		wFiles  int
+		wErr    error
+	}{"entries": {
+		entries: []corpus.Entry{entry(1, "a"), entry(2, "b"), entry(1, "a")},
+		wFiles:  3, // Including the one already there.
+	}, "none": {
+		wFiles: 1,
+	}, "invalid": {
+		entries: []corpus.Entry{entry(1, "a"), {}},
+		wFiles:  1,
+		wErr:    corpus.ErrMalformedEntry,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "testdata", "fuzz", "FuzzFoo")
+			req := require.New(t)
+			req.NoError(os.MkdirAll(dir, 0o755))
+			req.NoError(os.WriteFile(filepath.Join(dir, "seed"),
+				[]byte("go test fuzz v1\nint(3)\nstring(\"c\")\n"), 0o644))
+
+			err := WriteDir(dir, tt.entries)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			files, err := os.ReadDir(dir)
+			req.NoError(err)
+			req.Len(files, tt.wFiles)
+
+			c, err := fuzzdump.ReadDir(os.DirFS(dir), ".")
+			req.NoError(err, "valid corpus")
+			for _, f := range c {
+				data, err := corpus.Marshal(f.Entry)
+				req.NoError(err)
+				if f.Name != "seed" {
+					req.Equal(corpus.FileName(data), f.Name, "named as by Go")
+				}
+			}
+		})
+	}
+}
+
+func TestWriteDir_new(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "FuzzFoo")
+	e, err := corpus.NewEntry([]byte("x"))
+	req := require.New(t)
+	req.NoError(err)
+	req.NoError(WriteDir(dir, []corpus.Entry{e}))
+	data := []byte("go test fuzz v1\n[]byte(\"x\")\n")
+	got, err := os.ReadFile(filepath.Join(dir, corpus.FileName(data)))
+	req.NoError(err)
+	req.Equal(data, got)
+}
+
+func TestWriteDirFS(t *testing.T) {
+	e, err := corpus.NewEntry(int(1), "a")
+	req := require.New(t)
+	req.NoError(err)
+	fsys := &MemFS{}
+	req.NoError(WriteDirFS(fsys, filepath.Join("testdata", "fuzz", "FuzzFoo"), []corpus.Entry{e}))
+	c, err := fuzzdump.ReadDir(fsys, "testdata/fuzz/FuzzFoo")
+	req.NoError(err)
+	req.Len(c, 1)
+	req.Equal(e, c[0].Entry)
+}
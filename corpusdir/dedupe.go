@@ -0,0 +1,60 @@
+package corpusdir
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// Dedupe removes the entry files from the corpus directory dir in fsys
+// that hold the same values as another, as [fuzzdump.Duplicates] finds
+// them, keeping the first of each group, and returns how many it
+// removed.
+//
+// The directory is read from the local file system, if fsys is [OS],
+// otherwise fsys has to implement [fs.FS] too, as [MemFS] does, or
+// [ErrNotReadable] is returned.
+//
+// The invalid entries are left in place, and reported in
+// [fuzzdump.CorpusErrors] after the duplicates of the valid ones have
+// been removed. Nothing is removed, if any other error occurs while
+// reading the directory.
+func Dedupe(fsys FS, dir string) (removed int, err error) {
+	rfs, err := readable(fsys, dir)
+	if err != nil {
+		return
+	}
+	dups, err := fuzzdump.Duplicates(rfs, ".")
+	var errs fuzzdump.CorpusErrors
+	if e := errs.Capture(err); e != nil {
+		return 0, e
+	}
+	for _, names := range dups {
+		for _, name := range names[1:] {
+			if err := fsys.Remove(filepath.Join(dir, name)); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, errs.AsError()
+}
+
+// ErrNotReadable is returned when a directory cannot be read from an FS
+// that does not implement [fs.FS].
+var ErrNotReadable = errors.New("file system cannot be read")
+
+// readable returns the file system to read the directory dir in fsys
+// from, with dir as its root.
+func readable(fsys FS, dir string) (fs.FS, error) {
+	switch f := fsys.(type) {
+	case osFS:
+		return os.DirFS(dir), nil
+	case fs.FS:
+		return fs.Sub(f, memName(dir))
+	}
+	return nil, ErrNotReadable
+}
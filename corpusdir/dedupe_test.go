@@ -0,0 +1,65 @@
+package corpusdir_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+	. "github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupe(t *testing.T) {
+	files := map[string]string{
+		"a": "go test fuzz v1\nint(1)\n",
+		"b": "go test fuzz v1\nint(0x1)\n",
+		"c": "go test fuzz v1\nint(2)\n",
+		"d": "go test fuzz v1\nint(1)\n",
+		"e": "go test fuzz v1\nint(\n",
+	}
+	t.Run("OS", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "FuzzFoo")
+		req := require.New(t)
+		req.NoError(os.Mkdir(dir, 0o755))
+		for name, data := range files {
+			req.NoError(os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+		}
+		removed, err := Dedupe(OS, dir)
+		req.ErrorIs(err, fuzzdump.ErrMalformedValue)
+		req.Equal(2, removed)
+		entries, err := os.ReadDir(dir)
+		req.NoError(err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		req.Equal([]string{"a", "c", "e"}, names)
+	})
+	t.Run("MemFS", func(t *testing.T) {
+		m := &MemFS{}
+		req := require.New(t)
+		req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+		for name, data := range files {
+			if name != "e" {
+				req.NoError(WriteFile(m, "/fuzz/FuzzFoo/"+name, []byte(data)))
+			}
+		}
+		removed, err := Dedupe(m, "/fuzz/FuzzFoo")
+		req.NoError(err)
+		req.Equal(2, removed)
+		req.Equal([]string{"fuzz", "fuzz/FuzzFoo", "fuzz/FuzzFoo/a", "fuzz/FuzzFoo/c"}, m.Names())
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, err := Dedupe(&MemFS{}, "absent")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("not readable", func(t *testing.T) {
+		_, err := Dedupe(writeOnlyFS{OS}, t.TempDir())
+		require.ErrorIs(t, err, ErrNotReadable)
+	})
+}
+
+// writeOnlyFS is an FS that cannot be read.
+type writeOnlyFS struct{ FS }
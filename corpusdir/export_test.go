@@ -0,0 +1,6 @@
+package corpusdir
+
+var (
+	XerrNotDir   = errNotDir
+	XerrNotEmpty = errNotEmpty
+)
@@ -0,0 +1,263 @@
+package corpusdir
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+)
+
+// A FS is a file system that corpus directories are written to, with
+// just the operations that writing them takes, so that they can be
+// written to other stores than the local file system, e.g., to a MemFS
+// in tests. The names are paths native to the operating system, as with
+// package os.
+type FS interface {
+	// Create creates the named file, or truncates it, if it exists, for
+	// writing. The file is written once the writer is closed.
+	Create(name string) (io.WriteCloser, error)
+	// Rename renames (moves) a file or a directory, replacing the file
+	// newname, if that exists.
+	Rename(oldname, newname string) error
+	// Remove removes the named file or empty directory.
+	Remove(name string) error
+	// MkdirAll creates the named directory, along with any parents that
+	// do not exist yet.
+	MkdirAll(name string) error
+	// Stat returns the information on the named file.
+	Stat(name string) (fs.FileInfo, error)
+}
+
+// A ChtimesFS is an FS that can also set the modification times of
+// files, for them to be kept, e.g., when restoring a backup.
+type ChtimesFS interface {
+	FS
+	// Chtimes sets the modification time of the named file.
+	Chtimes(name string, mtime time.Time) error
+}
+
+// OS is the FS of the local file system. It is a ChtimesFS.
+var OS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) Create(name string) (io.WriteCloser, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+}
+
+func (osFS) Rename(oldname, newname string) error  { return os.Rename(oldname, newname) }
+func (osFS) Remove(name string) error              { return os.Remove(name) }
+func (osFS) MkdirAll(name string) error            { return os.MkdirAll(name, 0o755) }
+func (osFS) Stat(name string) (fs.FileInfo, error) { return os.Lstat(name) }
+
+func (osFS) Chtimes(name string, mtime time.Time) error {
+	return os.Chtimes(name, mtime, mtime)
+}
+
+// A MemFS is an FS in memory, e.g., for tests. It is also an [fs.FS],
+// for what it holds to be read, e.g., with [fuzzdump.ReadDir], by the
+// slash-separated paths of its files, without a leading slash. It is a
+// ChtimesFS. The zero MemFS is empty, and ready to use.
+//
+// A MemFS is safe for concurrent use by multiple goroutines.
+type MemFS struct {
+	mu    sync.Mutex
+	files fstest.MapFS
+}
+
+// Open implements the [fs.FS] interface.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// A copy, for the file to be read while m is being written to.
+	files := make(fstest.MapFS, len(m.files))
+	for k, v := range m.files {
+		f := *v
+		files[k] = &f
+	}
+	return files.Open(name)
+}
+
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	n := memName(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkParent("create", name, n); err != nil {
+		return nil, err
+	}
+	if f, ok := m.files[n]; ok && f.Mode.IsDir() {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: errIsDir}
+	}
+	return &memFile{m: m, name: n}, nil
+}
+
+func (m *MemFS) Rename(oldname, newname string) error {
+	o, n := memName(oldname), memName(newname)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[o]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fs.ErrNotExist}
+	}
+	if err := m.checkParent("rename", newname, n); err != nil {
+		return err
+	}
+	if t, ok := m.files[n]; ok {
+		if f.Mode.IsDir() != t.Mode.IsDir() || m.hasChildren(n) {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fs.ErrExist}
+		}
+	}
+	for k, v := range m.files {
+		if k == o || strings.HasPrefix(k, o+"/") {
+			delete(m.files, k)
+			m.files[n+strings.TrimPrefix(k, o)] = v
+		}
+	}
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	n := memName(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[n]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if m.hasChildren(n) {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.files, n)
+	return nil
+}
+
+func (m *MemFS) MkdirAll(name string) error {
+	n := memName(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var dirs []string
+	for d := n; d != "."; d = path.Dir(d) {
+		dirs = append(dirs, d)
+	}
+	// From the top down, so that no directory is made under a file.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		d := dirs[i]
+		if f, ok := m.files[d]; ok {
+			if !f.Mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: name, Err: errNotDir}
+			}
+			continue
+		}
+		m.set(d, &fstest.MapFile{Mode: fs.ModeDir | 0o755, ModTime: time.Now()})
+	}
+	return nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	n := memName(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[n]; !ok && n != "." {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return m.files.Stat(n)
+}
+
+// Chtimes implements the [ChtimesFS] interface.
+func (m *MemFS) Chtimes(name string, mtime time.Time) error {
+	n := memName(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[n]
+	if !ok {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrNotExist}
+	}
+	f.ModTime = mtime
+	return nil
+}
+
+// Names returns the paths of all the files and directories in m, sorted.
+func (m *MemFS) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.files))
+	for n := range m.files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// set the file with the path n in m.
+func (m *MemFS) set(n string, f *fstest.MapFile) {
+	if m.files == nil {
+		m.files = fstest.MapFS{}
+	}
+	m.files[n] = f
+}
+
+// checkParent returns an error of op on name, unless the parent
+// directory of its path n in m exists.
+func (m *MemFS) checkParent(op, name, n string) error {
+	d := path.Dir(n)
+	if d == "." {
+		return nil
+	}
+	if f, ok := m.files[d]; !ok || !f.Mode.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+// hasChildren reports whether the directory with the path n in m holds
+// any files.
+func (m *MemFS) hasChildren(n string) bool {
+	for k := range m.files {
+		if strings.HasPrefix(k, n+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// memName returns the path in a MemFS of the file with the native name.
+func memName(name string) string {
+	n := strings.TrimLeft(path.Clean(filepath.ToSlash(name)), "/")
+	if n == "" {
+		return "."
+	}
+	return n
+}
+
+// A memFile is a file being written to a MemFS.
+type memFile struct {
+	bytes.Buffer
+	m    *MemFS
+	name string
+}
+
+// Close writes the file to its MemFS.
+func (f *memFile) Close() error {
+	f.m.mu.Lock()
+	defer f.m.mu.Unlock()
+	if err := f.m.checkParent("close", f.name, f.name); err != nil {
+		return err
+	}
+	f.m.set(f.name, &fstest.MapFile{
+		Data: append([]byte(nil), f.Bytes()...), Mode: 0o644, ModTime: time.Now(),
+	})
+	return nil
+}
+
+var (
+	errIsDir    = errors.New("is a directory")
+	errNotDir   = errors.New("not a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
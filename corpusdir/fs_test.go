@@ -0,0 +1,121 @@
+package corpusdir_test
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+
+	. "github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemFS(t *testing.T) {
+	tests := map[string]struct {
+		do     func(m *MemFS) error
+		wErr   error
+		wNames []string
+	}{"write": {
+		do: func(m *MemFS) error {
+			if err := m.MkdirAll("/a/b"); err != nil {
+				return err
+			}
+			return WriteFile(m, "/a/b/f", []byte("x"))
+		},
+		wNames: []string{"a", "a/b", "a/b/f"},
+	}, "write without dir": {
+		do:   func(m *MemFS) error { return WriteFile(m, "a/f", nil) },
+		wErr: fs.ErrNotExist,
+	}, "rename dir": {
+		do: func(m *MemFS) error {
+			m.MkdirAll("a/b")
+			WriteFile(m, "a/b/f", nil)
+			return m.Rename("a/b", "a/c")
+		},
+		wNames: []string{"a", "a/c", "a/c/f"},
+	}, "rename over file": {
+		do: func(m *MemFS) error {
+			WriteFile(m, "f", []byte("old"))
+			WriteFile(m, "g", []byte("new"))
+			return m.Rename("g", "f")
+		},
+		wNames: []string{"f"},
+	}, "rename over non-empty dir": {
+		do: func(m *MemFS) error {
+			m.MkdirAll("a")
+			m.MkdirAll("b/c")
+			return m.Rename("a", "b")
+		},
+		wErr:   fs.ErrExist,
+		wNames: []string{"a", "b", "b/c"},
+	}, "rename absent": {
+		do:   func(m *MemFS) error { return m.Rename("a", "b") },
+		wErr: fs.ErrNotExist,
+	}, "remove": {
+		do: func(m *MemFS) error {
+			m.MkdirAll("a")
+			WriteFile(m, "a/f", nil)
+			if err := m.Remove("a/f"); err != nil {
+				return err
+			}
+			return m.Remove("a")
+		},
+		wNames: []string{},
+	}, "remove non-empty dir": {
+		do: func(m *MemFS) error {
+			m.MkdirAll("a/b")
+			return m.Remove("a")
+		},
+		wErr:   XerrNotEmpty,
+		wNames: []string{"a", "a/b"},
+	}, "remove absent": {
+		do:   func(m *MemFS) error { return m.Remove("a") },
+		wErr: fs.ErrNotExist,
+	}, "mkdir over file": {
+		do: func(m *MemFS) error {
+			WriteFile(m, "f", nil)
+			return m.MkdirAll("f/a")
+		},
+		wErr:   XerrNotDir,
+		wNames: []string{"f"},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := &MemFS{}
+			err := tt.do(m)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			if tt.wNames != nil {
+				req.Equal(tt.wNames, m.Names())
+			}
+		})
+	}
+}
+
+func TestMemFS_read(t *testing.T) {
+	m := &MemFS{}
+	req := require.New(t)
+	req.NoError(m.MkdirAll("a"))
+	req.NoError(WriteFile(m, "a/f", []byte("data")))
+	b, err := fs.ReadFile(m, "a/f")
+	req.NoError(err)
+	req.Equal("data", string(b))
+	fi, err := m.Stat("/a/f")
+	req.NoError(err)
+	req.EqualValues(4, fi.Size())
+	fi, err = m.Stat("a")
+	req.NoError(err)
+	req.True(fi.IsDir())
+	_, err = m.Stat("b")
+	req.ErrorIs(err, fs.ErrNotExist)
+
+	mtime := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+	req.NoError(m.Chtimes("a/f", mtime))
+	fi, err = fs.Stat(m, "a/f")
+	req.NoError(err)
+	req.Equal(mtime, fi.ModTime())
+	req.ErrorIs(m.Chtimes("b", mtime), fs.ErrNotExist)
+}
@@ -0,0 +1,257 @@
+package corpusdir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// Merge copies the entry files from the fuzz test corpora at the roots
+// of srcs to the corpus directory dst, creating it, if necessary, e.g.,
+// to collect the seeds found by fuzzing in several places into one
+// corpus. Each file is named as the Go toolchain names it, by the
+// SHA-256 hash of its contents, so an entry that is in several of the
+// corpora, or already in dst, is written just once, and the other files
+// in dst are left as they are.
+//
+// The files are written as a single transaction, see [WriteStaged], so a
+// merge that fails never leaves dst half merged. It takes no advisory
+// lock of dst, which the merge command of fuzzdump does.
+//
+// The invalid entries, and those with a different number of arguments
+// than the first valid one in dst, or in srcs, if dst has none, are not
+// copied, but reported in [fuzzdump.CorpusErrors] after the rest have
+// been, each in a [fuzzdump.FileError] naming it as "srcN/name", where N
+// is the index of its corpus in srcs. Nothing is copied, if any other
+// error occurs while reading the corpora.
+func Merge(dst string, srcs ...fs.FS) error {
+	return MergeFS(OS, dst, srcs...)
+}
+
+// MergeFS copies the entry files from the fuzz test corpora at the roots
+// of srcs to the corpus directory dst in fsys, as [Merge] does to the
+// local file system. Unless dst does not exist yet, fsys has to be
+// readable, as with [Dedupe].
+func MergeFS(fsys FS, dst string, srcs ...fs.FS) error {
+	return MergeWith(fsys, dst, KeepBoth, srcs...)
+}
+
+// A Strategy decides which of the entry files that hold the same values,
+// but are encoded differently, e.g. int(1) and int(0x1), and so named
+// differently, to keep when merging corpora with [MergeWith].
+type Strategy int
+
+const (
+	// KeepBoth copies every entry file, as [Merge] does.
+	KeepBoth Strategy = iota
+	// PreferExisting keeps the entry files already in the destination,
+	// and otherwise the first of the sources, by their order and names.
+	PreferExisting
+	// PreferNewer keeps the entry file modified last, replacing those in
+	// the destination, and keeps its modification time, if the FS is a
+	// [ChtimesFS]. Of the files modified at the same time, it keeps the
+	// one PreferExisting would.
+	PreferNewer
+)
+
+// MergeWith copies the entry files from the fuzz test corpora at the
+// roots of srcs to the corpus directory dst in fsys, as [MergeFS] does,
+// keeping just one of the entry files with the same values, as the
+// strategy s decides, unless it is [KeepBoth].
+//
+// The files that [PreferNewer] replaces in dst are removed once the rest
+// have been written, and are left in place, if that fails.
+func MergeWith(fsys FS, dst string, s Strategy, srcs ...fs.FS) error {
+	argCount, existing, err := readMergeDir(fsys, dst, s != KeepBoth)
+	if err != nil {
+		return err
+	}
+	var (
+		errs  fuzzdump.CorpusErrors
+		data  = map[string][]byte{} // By the names of their files in dst.
+		names []string
+		// The files to keep, by the keys of their values, in the order
+		// of the keys, with the names in dst of those to be replaced.
+		keep     = map[string]*mergeFile{}
+		keys     []string
+		replaced = map[string][]string{}
+	)
+	for _, f := range existing {
+		if _, ok := keep[f.key]; !ok {
+			keep[f.key] = f
+			keys = append(keys, f.key)
+		}
+		replaced[f.key] = append(replaced[f.key], f.name)
+	}
+	for i, src := range srcs {
+		files, err := fs.ReadDir(src, ".")
+		if err != nil {
+			return &fuzzdump.FileError{Name: srcName(i, "."), Err: err}
+		}
+		for _, f := range files {
+			if !f.Type().IsRegular() {
+				continue
+			}
+			b, err := fs.ReadFile(src, f.Name())
+			if err != nil {
+				return &fuzzdump.FileError{Name: srcName(i, f.Name()), Err: err}
+			}
+			n, key, err := entryKey(b)
+			if err == nil && argCount != 0 && n != argCount {
+				err = fmt.Errorf("%w: want %d, got %d",
+					fuzzdump.ErrInconsistentArgCount, argCount, n)
+			}
+			if err != nil {
+				errs = append(errs, &fuzzdump.FileError{Name: srcName(i, f.Name()), Err: err})
+				continue
+			}
+			argCount = n
+			name := corpus.FileName(b)
+			if s == KeepBoth {
+				if _, ok := data[name]; !ok {
+					data[name] = b
+					names = append(names, name)
+				}
+				continue
+			}
+			info, err := f.Info()
+			if err != nil {
+				return &fuzzdump.FileError{Name: srcName(i, f.Name()), Err: err}
+			}
+			k, ok := keep[key]
+			if !ok {
+				keys = append(keys, key)
+			}
+			if !ok || s == PreferNewer && info.ModTime().After(k.mtime) {
+				keep[key] = &mergeFile{name: name, data: b, mtime: info.ModTime()}
+			}
+		}
+	}
+	var remove []string
+	mtimes := map[string]time.Time{} // Of the files to write, to keep.
+	for _, key := range keys {
+		f := keep[key]
+		if f.data == nil {
+			continue // Already in dst.
+		}
+		if _, ok := data[f.name]; !ok {
+			data[f.name] = f.data
+			names = append(names, f.name)
+		}
+		mtimes[f.name] = f.mtime
+		for _, n := range replaced[key] {
+			if n != f.name {
+				remove = append(remove, n)
+			}
+		}
+	}
+	err = WriteStaged(fsys, dst, func(st *Stage) error {
+		for _, name := range names {
+			if _, err := fsys.Stat(filepath.Join(dst, name)); err == nil {
+				continue // Already there, with the same contents.
+			}
+			if err := st.WriteFile(name, data[name]); err != nil {
+				return err
+			}
+			if c, ok := fsys.(ChtimesFS); ok && s == PreferNewer {
+				if err := c.Chtimes(filepath.Join(st.Dir(), name), mtimes[name]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range remove {
+		if err := fsys.Remove(filepath.Join(dst, name)); err != nil {
+			return err
+		}
+	}
+	return errs.AsError()
+}
+
+// A mergeFile is a valid entry file to merge, or one already in the
+// destination, if its data is nil.
+type mergeFile struct {
+	name  string
+	key   string
+	data  []byte
+	mtime time.Time
+}
+
+// srcName returns the name of the named file of the corpus at index i of
+// the sources of [MergeFS], for its errors to report it by.
+func srcName(i int, name string) string {
+	return path.Join("src"+strconv.Itoa(i), name)
+}
+
+// readMergeDir returns the number of the arguments of the first valid
+// entry, by file name, in the corpus directory dir in fsys, or 0, if
+// there is none, or dir does not exist, along with all of its valid
+// entry files, if all is true.
+func readMergeDir(fsys FS, dir string, all bool) (argCount int, files []*mergeFile, err error) {
+	if _, err := fsys.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return 0, nil, nil
+	}
+	rfs, err := readable(fsys, dir)
+	if err != nil {
+		return
+	}
+	entries, err := fs.ReadDir(rfs, ".")
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		b, err := fs.ReadFile(rfs, e.Name())
+		if err != nil {
+			return 0, nil, &fuzzdump.FileError{Name: e.Name(), Err: err}
+		}
+		n, key, err := entryKey(b)
+		if err != nil {
+			continue
+		}
+		if argCount == 0 {
+			argCount = n
+		}
+		if !all {
+			break
+		}
+		info, err := e.Info()
+		if err != nil {
+			return 0, nil, &fuzzdump.FileError{Name: e.Name(), Err: err}
+		}
+		files = append(files, &mergeFile{name: e.Name(), key: key, mtime: info.ModTime()})
+	}
+	return argCount, files, nil
+}
+
+// entryKey returns the number of the values of the corpus entry that
+// data holds, once they are all decoded, and a key that is the same for
+// the entries with the same values, however encoded.
+func entryKey(data []byte) (argCount int, key string, err error) {
+	e, err := corpus.Unmarshal(data)
+	if err != nil {
+		return
+	}
+	vals, err := e.Values()
+	if err != nil {
+		return
+	}
+	if e, err = corpus.NewEntry(vals...); err != nil {
+		return
+	}
+	return len(vals), string(bytes.Join(e, []byte("\n"))), nil
+}
@@ -0,0 +1,241 @@
+package corpusdir_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	. "github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFS(t *testing.T) {
+	const (
+		one   = "go test fuzz v1\nint(1)\n"
+		two   = "go test fuzz v1\nint(2)\n"
+		three = "go test fuzz v1\nint(3)\n"
+	)
+	name := func(data string) string {
+		return "fuzz/FuzzFoo/" + corpus.FileName([]byte(data))
+	}
+	tests := map[string]struct {
+		srcs  []fs.FS
+		want  []string
+		wErrs []error
+	}{"nominal": {
+		srcs: []fs.FS{
+			fstest.MapFS{"ci-1": {Data: []byte(one)}, "ci-2": {Data: []byte(two)}},
+			fstest.MapFS{"local": {Data: []byte(two)}, "sub/x": {Data: []byte(three)}},
+		},
+		want: []string{name(one), name(two)},
+	}, "invalid": {
+		srcs: []fs.FS{
+			fstest.MapFS{
+				"1": {Data: []byte(one)},
+				"2": {Data: []byte("garbage")},
+				"3": {Data: []byte("go test fuzz v1\nint(\n")},
+				"4": {Data: []byte("go test fuzz v1\nint(4)\nint(5)\n")},
+			},
+		},
+		want:  []string{name(one)},
+		wErrs: []error{fuzzdump.ErrMalformedEntry, fuzzdump.ErrMalformedValue, fuzzdump.ErrInconsistentArgCount},
+	}, "none": {}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := &MemFS{}
+			err := MergeFS(m, "/fuzz/FuzzFoo", tt.srcs...)
+			req := require.New(t)
+			if tt.wErrs == nil {
+				req.NoError(err)
+			}
+			for _, wErr := range tt.wErrs {
+				req.ErrorIs(err, wErr)
+			}
+			req.Equal(append([]string{"fuzz", "fuzz/FuzzFoo"}, tt.want...), m.Names())
+		})
+	}
+	t.Run("existing", func(t *testing.T) {
+		m := &MemFS{}
+		req := require.New(t)
+		req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+		req.NoError(WriteFile(m, "/"+name(one), []byte(one)))
+		req.NoError(WriteFile(m, "/fuzz/FuzzFoo/other", []byte(three)))
+		req.NoError(MergeFS(m, "/fuzz/FuzzFoo", fstest.MapFS{
+			"1": {Data: []byte(one)},
+			"2": {Data: []byte(two)},
+		}))
+		req.ElementsMatch([]string{"fuzz", "fuzz/FuzzFoo", name(one), name(two), "fuzz/FuzzFoo/other"}, m.Names())
+	})
+	t.Run("argument count of dst", func(t *testing.T) {
+		m := &MemFS{}
+		req := require.New(t)
+		req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+		req.NoError(WriteFile(m, "/fuzz/FuzzFoo/pair", []byte("go test fuzz v1\nint(1)\nint(2)\n")))
+		err := MergeFS(m, "/fuzz/FuzzFoo", fstest.MapFS{}, fstest.MapFS{"1": {Data: []byte(one)}})
+		req.ErrorIs(err, fuzzdump.ErrInconsistentArgCount)
+		req.ErrorContains(err, `"src1/1"`)
+		req.Equal([]string{"fuzz", "fuzz/FuzzFoo", "fuzz/FuzzFoo/pair"}, m.Names())
+	})
+	t.Run("unreadable dst", func(t *testing.T) {
+		m := &MemFS{}
+		req := require.New(t)
+		req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+		err := MergeFS(writeOnlyFS{m}, "/fuzz/FuzzFoo", fstest.MapFS{"1": {Data: []byte(one)}})
+		req.ErrorIs(err, ErrNotReadable)
+	})
+	t.Run("failed write", func(t *testing.T) {
+		m := &MemFS{}
+		req := require.New(t)
+		req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+		req.NoError(WriteFile(m, "/fuzz/FuzzFoo/other", []byte(three)))
+		err := MergeFS(&failingFS{MemFS: m, after: 1}, "/fuzz/FuzzFoo", fstest.MapFS{
+			"1": {Data: []byte(one)},
+			"2": {Data: []byte(two)},
+		})
+		req.ErrorIs(err, errCreate)
+		req.Equal([]string{"fuzz", "fuzz/FuzzFoo", "fuzz/FuzzFoo/other"}, m.Names(),
+			"nothing merged, no staging left behind")
+	})
+	t.Run("missing source", func(t *testing.T) {
+		m := &MemFS{}
+		err := MergeFS(m, "/fuzz/FuzzFoo", os.DirFS(filepath.Join(t.TempDir(), "absent")))
+		req := require.New(t)
+		req.ErrorIs(err, fs.ErrNotExist)
+		req.Empty(m.Names())
+	})
+}
+
+func TestMerge(t *testing.T) {
+	const data = "go test fuzz v1\nint(1)\n"
+	dst := filepath.Join(t.TempDir(), "FuzzFoo")
+	req := require.New(t)
+	req.NoError(Merge(dst, fstest.MapFS{"a": {Data: []byte(data)}}))
+	b, err := os.ReadFile(filepath.Join(dst, corpus.FileName([]byte(data))))
+	req.NoError(err)
+	req.Equal(data, string(b))
+}
+
+func TestMergeWith(t *testing.T) {
+	const (
+		one    = "go test fuzz v1\nint(1)\n"
+		oneHex = "go test fuzz v1\nint(0x1)\n"
+		oneOct = "go test fuzz v1\nint(01)\n"
+		two    = "go test fuzz v1\nint(2)\n"
+	)
+	var (
+		old   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		newer = old.Add(time.Hour)
+	)
+	name := func(data string) string {
+		return "fuzz/FuzzFoo/" + corpus.FileName([]byte(data))
+	}
+	tests := map[string]struct {
+		s      Strategy
+		mtime  time.Time // Of the existing entry file, one.
+		srcs   []fs.FS
+		want   []string
+		wMtime time.Time // Of the file of the entry with the value 1.
+	}{"keep both": {
+		s:     KeepBoth,
+		mtime: old,
+		srcs:  []fs.FS{fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: newer}}},
+		want:  []string{name(one), name(oneHex)},
+	}, "prefer existing": {
+		s:      PreferExisting,
+		mtime:  old,
+		srcs:   []fs.FS{fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: newer}}},
+		want:   []string{name(one)},
+		wMtime: old,
+	}, "prefer existing source": {
+		s: PreferExisting,
+		srcs: []fs.FS{
+			fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: old}},
+			fstest.MapFS{"a": {Data: []byte(oneOct), ModTime: newer}},
+		},
+		want: []string{name(oneHex)},
+	}, "prefer newer": {
+		s:      PreferNewer,
+		mtime:  old,
+		srcs:   []fs.FS{fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: newer}}},
+		want:   []string{name(oneHex)},
+		wMtime: newer,
+	}, "prefer newer existing": {
+		s:      PreferNewer,
+		mtime:  newer,
+		srcs:   []fs.FS{fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: old}}},
+		want:   []string{name(one)},
+		wMtime: newer,
+	}, "prefer newer tie": {
+		s:      PreferNewer,
+		mtime:  old,
+		srcs:   []fs.FS{fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: old}}},
+		want:   []string{name(one)},
+		wMtime: old,
+	}, "prefer newer source": {
+		s: PreferNewer,
+		srcs: []fs.FS{
+			fstest.MapFS{"a": {Data: []byte(oneHex), ModTime: old}},
+			fstest.MapFS{
+				"a": {Data: []byte(oneOct), ModTime: newer},
+				"b": {Data: []byte(two), ModTime: old},
+			},
+		},
+		want:   []string{name(oneOct), name(two)},
+		wMtime: newer,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := &MemFS{}
+			req := require.New(t)
+			if !tt.mtime.IsZero() {
+				req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+				req.NoError(WriteFile(m, "/"+name(one), []byte(one)))
+				req.NoError(m.Chtimes("/"+name(one), tt.mtime))
+			}
+			req.NoError(MergeWith(m, "/fuzz/FuzzFoo", tt.s, tt.srcs...))
+			req.ElementsMatch(append([]string{"fuzz", "fuzz/FuzzFoo"}, tt.want...), m.Names())
+			if !tt.wMtime.IsZero() {
+				fi, err := m.Stat("/" + tt.want[0])
+				req.NoError(err)
+				req.Equal(tt.wMtime, fi.ModTime())
+			}
+		})
+	}
+	t.Run("failed write", func(t *testing.T) {
+		m := &MemFS{}
+		req := require.New(t)
+		req.NoError(m.MkdirAll("/fuzz/FuzzFoo"))
+		req.NoError(WriteFile(m, "/"+name(one), []byte(one)))
+		req.NoError(m.Chtimes("/"+name(one), old))
+		err := MergeWith(&failingFS{MemFS: m}, "/fuzz/FuzzFoo", PreferNewer, fstest.MapFS{
+			"a": {Data: []byte(oneHex), ModTime: newer},
+		})
+		req.ErrorIs(err, errCreate)
+		req.Equal([]string{"fuzz", "fuzz/FuzzFoo", name(one)}, m.Names(),
+			"nothing replaced")
+	})
+}
+
+// failingFS fails to create any more files once it has created as many
+// as after.
+type failingFS struct {
+	*MemFS
+	after int
+}
+
+func (f *failingFS) Create(name string) (io.WriteCloser, error) {
+	if f.after == 0 {
+		return nil, errCreate
+	}
+	f.after--
+	return f.MemFS.Create(name)
+}
+
+var errCreate = errors.New("create failed")
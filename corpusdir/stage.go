@@ -0,0 +1,165 @@
+package corpusdir
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"path/filepath"
+)
+
+// WriteStaged writes files to the dst directory in fsys, creating it, if
+// necessary, with fn, as a single transaction.
+//
+// The files are written to a temporary staging directory next to dst
+// first, and only moved into dst when fn succeeded. If dst does not
+// exist, the whole staging directory is renamed to it at once. Otherwise
+// its files are moved into dst one by one, those they replace moved
+// aside to a temporary backup directory next to dst first, and if that
+// fails, the files already moved are removed again, and those replaced
+// moved back. Either way, a failed write never leaves dst half written,
+// unless restoring it fails too.
+func WriteStaged(fsys FS, dst string, fn func(s *Stage) error) (err error) {
+	dst = filepath.Clean(dst)
+	if err = fsys.MkdirAll(filepath.Dir(dst)); err != nil {
+		return
+	}
+	dir, err := tempName(dst)
+	if err != nil {
+		return
+	}
+	if err = fsys.MkdirAll(dir); err != nil {
+		return
+	}
+	s := &Stage{fsys: fsys, dir: dir, written: map[string]bool{}}
+	defer s.remove()
+	if err = fn(s); err != nil {
+		return
+	}
+	if _, sErr := fsys.Stat(dst); errors.Is(sErr, fs.ErrNotExist) {
+		return fsys.Rename(dir, dst)
+	}
+	b := &backup{fsys: fsys, dst: dst}
+	defer b.remove()
+	for _, n := range s.names {
+		if err = b.move(filepath.Join(dir, n), n); err != nil {
+			b.restore()
+			return
+		}
+	}
+	return
+}
+
+// A backup holds the files that a [WriteStaged] transaction replaced in
+// its destination, for them to be restored, if it fails.
+type backup struct {
+	fsys  FS
+	dst   string
+	dir   string   // Created once the first file is replaced.
+	names []string // Of the files moved into dst, in the order moved.
+	saved map[string]bool
+}
+
+// move the file src into b.dst by the given name, moving the file it
+// replaces, if any, aside first.
+func (b *backup) move(src, name string) error {
+	dst := filepath.Join(b.dst, name)
+	// A directory is left for the rename to fail to replace.
+	if fi, err := b.fsys.Stat(dst); err == nil && !fi.IsDir() {
+		if err := b.save(name); err != nil {
+			return err
+		}
+	}
+	if err := b.fsys.Rename(src, dst); err != nil {
+		return err
+	}
+	b.names = append(b.names, name)
+	return nil
+}
+
+// save the named file of b.dst in b.dir, creating it, if necessary.
+func (b *backup) save(name string) (err error) {
+	if b.dir == "" {
+		dir, err := tempName(b.dst)
+		if err != nil {
+			return err
+		}
+		if err = b.fsys.MkdirAll(dir); err != nil {
+			return err
+		}
+		b.dir, b.saved = dir, map[string]bool{}
+	}
+	if err = b.fsys.Rename(filepath.Join(b.dst, name), filepath.Join(b.dir, name)); err != nil {
+		return
+	}
+	b.saved[name] = true
+	return
+}
+
+// restore b.dst as it was before any files were moved into it, as far as
+// that can be done.
+func (b *backup) restore() {
+	for i := len(b.names) - 1; i >= 0; i-- {
+		b.fsys.Remove(filepath.Join(b.dst, b.names[i]))
+	}
+	for name := range b.saved {
+		if b.fsys.Rename(filepath.Join(b.dir, name), filepath.Join(b.dst, name)) == nil {
+			delete(b.saved, name)
+		}
+	}
+}
+
+// remove b.dir, along with any files still in it.
+func (b *backup) remove() {
+	if b.dir == "" {
+		return
+	}
+	for name := range b.saved {
+		b.fsys.Remove(filepath.Join(b.dir, name))
+	}
+	b.fsys.Remove(b.dir)
+}
+
+// A Stage is a temporary staging directory that holds the files written
+// to it before they are moved into their destination, see [WriteStaged].
+type Stage struct {
+	fsys    FS
+	dir     string
+	names   []string // Of the files written, in the order first written.
+	written map[string]bool
+}
+
+// Dir returns the path of the staging directory, e.g., to set the
+// modification times of the files written to it.
+func (s *Stage) Dir() string { return s.dir }
+
+// WriteFile writes data to the named file in s.
+func (s *Stage) WriteFile(name string, data []byte) error {
+	if err := WriteFile(s.fsys, filepath.Join(s.dir, name), data); err != nil {
+		return err
+	}
+	if !s.written[name] {
+		s.written[name] = true
+		s.names = append(s.names, name)
+	}
+	return nil
+}
+
+// remove s, along with any files still in it.
+func (s *Stage) remove() {
+	for _, n := range s.names {
+		s.fsys.Remove(filepath.Join(s.dir, n))
+	}
+	s.fsys.Remove(s.dir)
+}
+
+// tempName returns a name for a temporary file or directory next to the
+// named one, hidden, and unlikely to be taken.
+func tempName(name string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	dir, base := filepath.Split(name)
+	return filepath.Join(dir, "."+base+"."+hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,119 @@
+package corpusdir_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump/corpusdir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteStaged(t *testing.T) {
+	write := func(names ...string) func(s *Stage) error {
+		return func(s *Stage) error {
+			for _, name := range names {
+				if err := s.WriteFile(name, []byte(name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	errFn := errors.New("failed")
+	tests := map[string]struct {
+		setup  []string
+		fn     func(s *Stage) error
+		wErr   error
+		wNames []string
+	}{"new dir": {
+		fn:     write("a", "b", "a"),
+		wNames: []string{"fuzz", "fuzz/dst", "fuzz/dst/a", "fuzz/dst/b"},
+	}, "existing dir": {
+		setup:  []string{"old"},
+		fn:     write("a"),
+		wNames: []string{"fuzz", "fuzz/dst", "fuzz/dst/a", "fuzz/dst/old"},
+	}, "failed, new dir": {
+		fn: func(s *Stage) error {
+			write("a")(s)
+			return errFn
+		},
+		wErr:   errFn,
+		wNames: []string{"fuzz"},
+	}, "failed, existing dir": {
+		setup: []string{"old"},
+		fn: func(s *Stage) error {
+			write("a")(s)
+			return errFn
+		},
+		wErr:   errFn,
+		wNames: []string{"fuzz", "fuzz/dst", "fuzz/dst/old"},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := &MemFS{}
+			req := require.New(t)
+			if tt.setup != nil {
+				req.NoError(m.MkdirAll("/fuzz/dst"))
+			}
+			for _, name := range tt.setup {
+				req.NoError(WriteFile(m, "/fuzz/dst/"+name, nil))
+			}
+			err := WriteStaged(m, "/fuzz/dst", tt.fn)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.wNames, m.Names(), "no staging left behind")
+		})
+	}
+}
+
+func TestWriteStaged_restore(t *testing.T) {
+	tests := map[string]struct {
+		fail string // Name of the file in dst that cannot be replaced.
+	}{"after an overwrite": {
+		fail: "b",
+	}, "overwriting": {
+		fail: "a",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := &MemFS{}
+			req := require.New(t)
+			req.NoError(m.MkdirAll("/fuzz/dst"))
+			req.NoError(WriteFile(m, "/fuzz/dst/a", []byte("old")))
+			req.NoError(WriteFile(m, "/fuzz/dst/c", []byte("old")))
+			fsys := &renameFailingFS{MemFS: m, fail: "/fuzz/dst/" + tt.fail}
+			err := WriteStaged(fsys, "/fuzz/dst", func(s *Stage) error {
+				for _, name := range []string{"a", "b", "c"} {
+					if err := s.WriteFile(name, []byte("new")); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			req.ErrorIs(err, errRename)
+			req.Equal([]string{"fuzz", "fuzz/dst", "fuzz/dst/a", "fuzz/dst/c"}, m.Names(),
+				"no staging or backup left behind")
+			for _, name := range []string{"a", "c"} {
+				b, err := fs.ReadFile(m, "fuzz/dst/"+name)
+				req.NoError(err)
+				req.Equal("old", string(b), name)
+			}
+		})
+	}
+}
+
+// renameFailingFS fails to rename a file to the one named fail once.
+type renameFailingFS struct {
+	*MemFS
+	fail string
+}
+
+func (f *renameFailingFS) Rename(oldname, newname string) error {
+	if newname == f.fail {
+		f.fail = ""
+		return errRename
+	}
+	return f.MemFS.Rename(oldname, newname)
+}
+
+var errRename = errors.New("rename failed")
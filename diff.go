@@ -0,0 +1,87 @@
+package fuzzdump
+
+import (
+	"bytes"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// Diff compares the fuzz test corpora at the roots of a and b by the
+// values of their entries, and returns those only in b as added, and
+// those only in a as removed, e.g., to review what a fuzzing session
+// has contributed to a corpus before committing it. The entries are
+// compared by their decoded values, so an entry with the values encoded
+// differently, e.g. int(1) and int(0x1), is in both.
+//
+// The entries are in the order of their file names, and hold the lines
+// of their files as they are, along with their metadata, as with
+// [ReadDir]. An empty corpus is compared as having no entries.
+//
+// The invalid entries of either corpus are reported in [CorpusErrors],
+// along with the differences of the valid ones. Any other error is
+// returned as it is.
+func Diff(a, b fs.FS) (added, removed Corpus, err error) {
+	var errs CorpusErrors
+	aFiles, aKeys, aSet, err := diffEntries(a, &errs)
+	if err != nil {
+		return
+	}
+	bFiles, bKeys, bSet, err := diffEntries(b, &errs)
+	if err != nil {
+		return
+	}
+	for i, f := range bFiles {
+		if !aSet[bKeys[i]] {
+			added = append(added, f)
+		}
+	}
+	for i, f := range aFiles {
+		if !bSet[aKeys[i]] {
+			removed = append(removed, f)
+		}
+	}
+	return added, removed, errs.AsError()
+}
+
+// diffEntries returns the valid entries of the corpus at the root of
+// fsys, and the keys of their values, both as a slice in the order of
+// the entries and as a set, appending the validation errors to errs.
+func diffEntries(fsys fs.FS, errs *CorpusErrors) (c Corpus, keys []string, set map[string]bool, err error) {
+	files, err := getFiles(fsys, ".")
+	if err != nil {
+		return
+	}
+	set = map[string]bool{}
+	for _, f := range files {
+		name := f.Name()
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, nil, nil, readErr(err, name)
+		}
+		e, err := corpus.Unmarshal(b)
+		var n corpus.Entry
+		if err == nil {
+			n, err = e.Normalize()
+		}
+		if err != nil {
+			errs.append(readErr(err, name))
+			continue
+		}
+		fi, err := f.Info()
+		if err != nil {
+			return nil, nil, nil, readErr(err, name)
+		}
+		key := string(bytes.Join(n, []byte("\n")))
+		c = append(c, corpus.File{
+			Name:    name,
+			Entry:   e,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+			Hash:    entryHash(e),
+		})
+		keys = append(keys, key)
+		set[key] = true
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package fuzzdump_test
+
+import (
+	"crypto/sha256"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		a, b     fstest.MapFS
+		wAdded   []string
+		wRemoved []string
+		wErrs    []error
+	}{"nominal": {
+		a: fstest.MapFS{
+			"1": corpusFile("int(1)"),
+			"2": corpusFile("int(2)"),
+		},
+		b: fstest.MapFS{
+			"1": corpusFile("int(1)"),
+			"3": corpusFile("int(0x2)"), // Same values as "2" in a.
+			"4": corpusFile("int(4)"),
+			"5": corpusFile("int(5)"),
+		},
+		wAdded: []string{"4", "5"},
+	}, "removed": {
+		a: fstest.MapFS{
+			"1": corpusFile("int(1)"),
+			"2": corpusFile("int(2)"),
+		},
+		b:        fstest.MapFS{"1": corpusFile("int(1)")},
+		wRemoved: []string{"2"},
+	}, "empty": {
+		a:      fstest.MapFS{".": {Mode: fs.ModeDir}},
+		b:      fstest.MapFS{"1": corpusFile("int(1)")},
+		wAdded: []string{"1"},
+	}, "invalid": {
+		a: fstest.MapFS{
+			"1": corpusFile("int("),
+			"2": corpusFile("int(2)"),
+		},
+		b: fstest.MapFS{
+			"1": {Data: []byte("garbage")},
+			"3": corpusFile("int(3)"),
+		},
+		wAdded:   []string{"3"},
+		wRemoved: []string{"2"},
+		wErrs:    []error{ErrMalformedValue, ErrMalformedEntry},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			added, removed, err := Diff(tt.a, tt.b)
+			req := require.New(t)
+			if tt.wErrs == nil {
+				req.NoError(err)
+			}
+			for _, wErr := range tt.wErrs {
+				req.ErrorIs(err, wErr)
+			}
+			req.Equal(tt.wAdded, fileNames(added))
+			req.Equal(tt.wRemoved, fileNames(removed))
+		})
+	}
+	t.Run("lines as they are", func(t *testing.T) {
+		var (
+			data  = []byte(corpus.Version1 + "\nint(0x1)\n")
+			mtime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		)
+		added, _, err := Diff(fstest.MapFS{}, fstest.MapFS{
+			"1": {Data: data, ModTime: mtime},
+		})
+		req := require.New(t)
+		req.NoError(err)
+		req.Equal(Corpus{{
+			Name:    "1",
+			Entry:   corpus.Entry{[]byte("int(0x1)")},
+			Size:    int64(len(data)),
+			ModTime: mtime,
+			Hash:    sha256.Sum256(data),
+		}}, added)
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, _, err := Diff(fstest.MapFS{"1": corpusFile("int(1)")}, missingFS{})
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+// fileNames returns the names of the files of c, or nil if there are
+// none.
+func fileNames(c Corpus) (names []string) {
+	for _, f := range c {
+		names = append(names, f.Name)
+	}
+	return
+}
+
+// missingFS is a file system that has no root directory.
+type missingFS struct{}
+
+func (missingFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
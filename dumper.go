@@ -0,0 +1,123 @@
+package fuzzdump
+
+import (
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// A Dumper reads a fuzz test corpus directory once, and then dumps it,
+// or returns its entries, any number of times from memory, without
+// reading or parsing its files again, e.g., for a server answering
+// requests about the same corpus.
+//
+// A Dumper is safe for concurrent use by multiple goroutines. Those that
+// need the corpus while it is being read wait for that to finish, rather
+// than read it again.
+type Dumper struct {
+	fsys fs.FS
+	dir  string
+	o    options
+
+	mu       sync.Mutex
+	read     bool // Whether the fields below hold the corpus read.
+	argCount int  // Of the entries, or -1, if none began.
+	entries  []namedEntry
+	files    Corpus // The entries, with the metadata of their files.
+	err      error  // Of reading the corpus, if any.
+}
+
+// NewDumper returns a Dumper of dir in fsys. The corpus is read when it
+// is needed first.
+//
+// The opts apply as to [DumpDir]. Those that concern reading the corpus,
+// such as [WithCanonical], or [WithMin], apply just when it is read.
+func NewDumper(fsys fs.FS, dir string, opts ...Option) *Dumper {
+	return &Dumper{fsys: fsys, dir: dir, o: newOptions(opts)}
+}
+
+// Dump writes the entries of the corpus to w, as [DumpDir] would, with
+// the same errors.
+func (d *Dumper) Dump(w io.Writer) error {
+	return dumpFrom(w, d.source, d.o, d.o.newPrinter)
+}
+
+// Corpus returns the entries of the corpus, as [ReadDir] would, with the
+// same errors. The entries are shared by all the callers, and must not
+// be modified.
+func (d *Dumper) Corpus() (Corpus, error) {
+	_, _, err := d.load()
+	var errs CorpusErrors
+	if e := errs.Capture(err); e != nil {
+		return nil, e
+	}
+	if err != nil && d.o.strict {
+		return nil, err
+	}
+	return d.files, err
+}
+
+// Reset drops the corpus held in memory, for it to be read again when it
+// is needed next, e.g., after its files have changed.
+func (d *Dumper) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.read, d.entries, d.files, d.err = false, nil, nil, nil
+}
+
+// source passes the entries of the corpus to emit, see [source].
+func (d *Dumper) source(_ options, begin func(argCount int) error, emit emitter) error {
+	argCount, entries, err := d.load()
+	if argCount < 0 {
+		return err
+	}
+	if err := begin(argCount); err != nil {
+		return err
+	}
+	for _, v := range entries {
+		if err := emit(v.name, v.lines); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// load reads the corpus, unless it has been read already, and returns
+// the argument count and the entries read, along with the error of
+// reading them. The corpus is held in memory unless it could not be read
+// at all, e.g., due to an I/O error, or [ErrEmptyCorpus], so it is read
+// again the next time.
+func (d *Dumper) load() (argCount int, entries []namedEntry, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.read {
+		return d.argCount, d.entries, d.err
+	}
+	o, files := d.o.listing()
+	o.retain = true
+	argCount = -1
+	begin := func(n int) error {
+		argCount = n
+		return nil
+	}
+	emit := func(name string, lines corpus.Entry) error {
+		entries = append(entries, namedEntry{name, lines})
+		return nil
+	}
+	err = readDir(d.fsys, d.dir, o, begin, emit)
+	if o.sorted() {
+		o.sortEntries(entries)
+	}
+	var errs CorpusErrors
+	if errs.Capture(err) != nil {
+		return
+	}
+	c, cErr := newCorpus(files, entries)
+	if cErr != nil {
+		return -1, nil, cErr
+	}
+	d.read, d.argCount, d.entries, d.files, d.err = true, argCount, entries, c, err
+	return
+}
@@ -0,0 +1,97 @@
+package fuzzdump_test
+
+import (
+	"io/fs"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumper(t *testing.T) {
+	const dir = "corpus"
+	mfs := fstest.MapFS{
+		dir + "/a": corpusFile("int(3)"),
+		dir + "/b": corpusFile("int(1)"),
+		dir + "/c": corpusFile("int(2"),
+	}
+	fsys := &countingFS{FS: mfs}
+	d := NewDumper(fsys, dir, WithCanonical(), WithFileNames())
+	const want = "{\n\t// b\n\tint(1),\n\t// a\n\tint(3),\n}\n"
+
+	var wg sync.WaitGroup
+	outs := make([]string, 8)
+	errs := make([]error, len(outs))
+	for i := range outs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := &strings.Builder{}
+			errs[i] = d.Dump(w)
+			outs[i] = w.String()
+		}(i)
+	}
+	wg.Wait()
+	req := require.New(t)
+	for i := range outs {
+		req.ErrorIs(errs[i], ErrMalformedValue)
+		req.Equal(want, outs[i])
+	}
+	opens := fsys.opens.Load()
+	req.Equal(int64(4), opens, "the directory and each file opened once")
+
+	c, err := d.Corpus()
+	req.ErrorIs(err, ErrMalformedValue)
+	req.Len(c, 2)
+	req.Equal("b", c[0].Name)
+	req.Equal(opens, fsys.opens.Load())
+
+	d.Reset()
+	mfs[dir+"/d"] = corpusFile("int(0)")
+	w := &strings.Builder{}
+	req.ErrorIs(d.Dump(w), ErrMalformedValue)
+	req.Equal("{\n\t// d\n\tint(0),\n\t// b\n\tint(1),\n\t// a\n\tint(3),\n}\n", w.String())
+}
+
+func TestDumper_criticalError(t *testing.T) {
+	const dir = "corpus"
+	fsys := &countingFS{FS: failingFS{fstest.MapFS{
+		dir + "/a": corpusFile("int(1)"),
+		dir + "/b": corpusFile("int(2)"),
+	}, dir + "/b"}}
+	d := NewDumper(fsys, dir)
+	req := require.New(t)
+	for i := 0; i < 2; i++ {
+		_, err := d.Corpus()
+		req.ErrorIs(err, errSnap)
+	}
+	req.Equal(int64(6), fsys.opens.Load(), "read again after an I/O error")
+}
+
+func TestDumper_empty(t *testing.T) {
+	d := NewDumper(fstest.MapFS{}, "absent", WithAllowEmpty())
+	w := &strings.Builder{}
+	req := require.New(t)
+	req.NoError(d.Dump(w))
+	req.Equal("{\n}\n", w.String())
+
+	d = NewDumper(fstest.MapFS{}, "absent")
+	w.Reset()
+	req.ErrorIs(d.Dump(w), fs.ErrNotExist)
+	req.Empty(w.String())
+}
+
+// countingFS counts the files opened.
+type countingFS struct {
+	fs.FS
+	opens atomic.Int64
+}
+
+func (f *countingFS) Open(name string) (fs.File, error) {
+	f.opens.Add(1)
+	return f.FS.Open(name)
+}
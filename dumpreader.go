@@ -0,0 +1,47 @@
+package fuzzdump
+
+import (
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// NewDumpReader returns a reader of the dump of a fuzz test corpus
+// directory in fsys, as [DumpDir] writes it with opts, e.g., for an HTTP
+// handler to serve it with [io.Copy] at the pace of the client:
+//
+//	r := fuzzdump.NewDumpReader(fsys, dir)
+//	defer r.Close()
+//	_, err := io.Copy(w, r)
+//
+// The corpus is not read until the first call to Read, and then no
+// further ahead of the reads than [DumpDir] writes at once. Once the
+// whole dump has been read, Read returns the error [DumpDir] returned,
+// if any, instead of [io.EOF].
+//
+// Close stops dumping, and has to be called when done reading before
+// the end of the dump, so that the corpus is not held open.
+func NewDumpReader(fsys fs.FS, dir string, opts ...Option) io.ReadCloser {
+	pr, pw := io.Pipe()
+	return &dumpReader{pr: pr, dump: func() {
+		pw.CloseWithError(DumpDir(pw, fsys, dir, opts...))
+	}}
+}
+
+// A dumpReader reads a dump from a pipe that it writes to in a separate
+// goroutine, started at the first read.
+type dumpReader struct {
+	pr    *io.PipeReader
+	dump  func()
+	start sync.Once
+}
+
+func (r *dumpReader) Read(p []byte) (int, error) {
+	r.start.Do(func() { go r.dump() })
+	return r.pr.Read(p)
+}
+
+func (r *dumpReader) Close() error {
+	r.start.Do(func() {}) // Never start dumping once closed.
+	return r.pr.Close()
+}
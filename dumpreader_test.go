@@ -0,0 +1,80 @@
+package fuzzdump_test
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDumpReader(t *testing.T) {
+	const dir = "corpus"
+	tests := map[string]struct {
+		files map[string]*fstest.MapFile
+		opts  []Option
+		wErr  error
+	}{"nominal": {
+		files: map[string]*fstest.MapFile{
+			"1": corpusFile("int(1)\nstring(\"a\")"),
+			"2": corpusFile("int(2)\nstring(\"b\")"),
+		},
+	}, "with options": {
+		files: map[string]*fstest.MapFile{
+			"1": corpusFile("int(2)"),
+			"2": corpusFile("int(1)"),
+		},
+		opts: []Option{WithCanonical()},
+	}, "invalid": {
+		files: map[string]*fstest.MapFile{
+			"1": corpusFile("int(1)"),
+			"2": {Data: []byte("garbage")},
+		},
+		wErr: ErrShortEntry,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			fsys := fstest.MapFS{dir: {Mode: fs.ModeDir}}
+			for name, f := range tt.files {
+				fsys[dir+"/"+name] = f
+			}
+			want := &bytes.Buffer{}
+			wErr := DumpDir(want, fsys, dir, tt.opts...)
+
+			r := NewDumpReader(fsys, dir, tt.opts...)
+			got := &bytes.Buffer{}
+			// A small buffer, to read the dump in several chunks.
+			_, err := io.CopyBuffer(struct{ io.Writer }{got}, struct{ io.Reader }{r}, make([]byte, 3))
+			req := require.New(t)
+			req.NoError(r.Close())
+			req.Equal(want.String(), got.String())
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				req.Equal(wErr.Error(), err.Error())
+			} else {
+				req.NoError(err)
+			}
+		})
+	}
+	t.Run("closed", func(t *testing.T) {
+		fsys := fstest.MapFS{dir + "/1": corpusFile("int(1)")}
+		r := NewDumpReader(fsys, dir)
+		req := require.New(t)
+		req.NoError(r.Close())
+		_, err := r.Read(make([]byte, 1))
+		req.ErrorIs(err, io.ErrClosedPipe)
+	})
+	t.Run("closed early", func(t *testing.T) {
+		fsys := fstest.MapFS{dir + "/1": corpusFile("int(1)")}
+		r := NewDumpReader(fsys, dir)
+		req := require.New(t)
+		_, err := r.Read(make([]byte, 1))
+		req.NoError(err)
+		req.NoError(r.Close())
+		_, err = r.Read(make([]byte, 1))
+		req.ErrorIs(err, io.ErrClosedPipe)
+	})
+}
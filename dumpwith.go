@@ -0,0 +1,162 @@
+package fuzzdump
+
+import (
+	"io"
+	"io/fs"
+	"sort"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
+)
+
+// DumpDirWith writes the entries from a fuzz test corpus directory to w
+// in the dump format, as [DumpDir] does, styled as o sets, e.g., to fit
+// into a generated file of other conventions:
+//
+//	fuzzdump.DumpDirWith(w, fsys, dir, fuzzdump.Options{
+//		Indent:        "    ",
+//		Separators:    &format.Separators{Pre: "var seeds = []int{", Post: "}"},
+//		OmitTypeNames: true,
+//	})
+//
+// The zero Options dump the same as DumpDir. Errors are reported as with
+// DumpDir, and so are opts applied.
+func DumpDirWith(w io.Writer, fsys fs.FS, dir string, o Options, opts ...Option) error {
+	return DumpDir(w, fsys, dir, append(opts[:len(opts):len(opts)], withStyle(o))...)
+}
+
+// Options set the style of a dump with [DumpDirWith].
+type Options struct {
+	// Indent is written ahead of each value and each comment line. They
+	// are indented with a tab, if it is empty.
+	Indent string
+	// Separators, if set, are written around and between the entries
+	// instead of the braces of the dump format.
+	Separators *format.Separators
+	// OmitTrailingCommas leaves out the comma after the last value of
+	// each entry of a multiple-argument corpus, or after the last value
+	// of a single-argument one.
+	OmitTrailingCommas bool
+	// OmitTypeNames writes the values without their types, as untyped
+	// constants, e.g. 42 instead of int(42), save for the floats that
+	// have none, such as float64(NaN), which keep them.
+	OmitTypeNames bool
+	// Sort sets the order that the entries are dumped in.
+	Sort SortOrder
+	// Descending reverses the order that Sort sets.
+	Descending bool
+	// MaxEntries, if positive, is the number of entries dumped at most,
+	// the rest noted omitted in a comment at the end of the dump.
+	MaxEntries int
+	// Offset, if positive, is the number of entries left out ahead of
+	// the first one dumped, e.g., to page through a huge corpus with
+	// MaxEntries, noted omitted in a comment at the start of the dump.
+	Offset int
+	// BytesFormat sets how []byte values are rendered, e.g. as hex
+	// literals or just their lengths, which binary data is more readable
+	// as than escaped strings.
+	BytesFormat format.BytesFormat
+	// Filter, if set, skips the entries it returns false for, given the
+	// lines of each as they are to be dumped, after those that the
+	// predicates of [WithFilter] and the like skip. The entries skipped
+	// count neither towards MaxEntries nor Offset.
+	Filter func(corpus.Entry) bool
+}
+
+// A SortOrder is the order that [DumpDirWith] dumps the entries in.
+type SortOrder int
+
+const (
+	// SortByName orders entries by the names of their files, as they
+	// are read.
+	SortByName SortOrder = iota
+	// SortByContents orders entries by their contents, as [WithCanonical]
+	// does, but without normalizing their values.
+	SortByContents
+	// SortBySize orders entries by the sizes of their files, and then by
+	// their names.
+	SortBySize
+	// SortByModTime orders entries by the modification times of their
+	// files, and then by their names.
+	SortByModTime
+	// SortByHash orders entries by the SHA-256 hashes of their contents,
+	// as encoded in version 1 entry files, whatever their files are named.
+	// The order is the same as by the names of the files that the Go
+	// toolchain writes, named by those hashes.
+	SortByHash
+)
+
+// withStyle sets the style of the dump to that of s, keeping the limit,
+// offset, order, and format of []byte values that [WithLimit],
+// [WithOffset], [WithSort], and [WithBytesFormat] set, unless s sets
+// them.
+func withStyle(s Options) Option {
+	return func(o *options) {
+		if s.MaxEntries == 0 {
+			s.MaxEntries = o.style.MaxEntries
+		}
+		if s.Offset == 0 {
+			s.Offset = o.style.Offset
+		}
+		if s.Sort == SortByName && !s.Descending {
+			s.Sort, s.Descending = o.style.Sort, o.style.Descending
+		}
+		if s.BytesFormat == format.BytesRaw {
+			s.BytesFormat = o.style.BytesFormat
+		}
+		o.style = s
+	}
+}
+
+// styled sets the fields of p that o.style sets.
+func (o options) styled(p *format.Printer) {
+	p.Indent = o.style.Indent
+	p.Separators = o.style.Separators
+	p.OmitTrailingCommas = o.style.OmitTrailingCommas
+	p.OmitTypeNames = o.style.OmitTypeNames
+	p.Bytes = o.style.BytesFormat
+}
+
+// sorted reports whether the entries are to be sorted by their contents
+// (or their hashes) once they are all read, see [options.sortEntries].
+func (o options) sorted() bool {
+	return o.canonical || o.style.Sort == SortByContents || o.style.Sort == SortByHash
+}
+
+// sortFiles sorts the corpus files by their sizes or modification times,
+// or reverses their order by name, if o says so. The entries sorted
+// once they are all read, see [options.sorted], are left as they are.
+func (o options) sortFiles(files []fs.DirEntry) error {
+	var key func(fi fs.FileInfo) int64
+	switch o.style.Sort {
+	case SortByName:
+		if o.style.Descending {
+			for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+				files[i], files[j] = files[j], files[i]
+			}
+		}
+		return nil
+	case SortBySize:
+		key = func(fi fs.FileInfo) int64 { return fi.Size() }
+	case SortByModTime:
+		key = func(fi fs.FileInfo) int64 { return fi.ModTime().UnixNano() }
+	default:
+		return nil
+	}
+	keys := make(map[string]int64, len(files))
+	for _, f := range files {
+		fi, err := f.Info()
+		if err != nil {
+			return readErr(err, f.Name())
+		}
+		keys[f.Name()] = key(fi)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := keys[files[i].Name()], keys[files[j].Name()]
+		if o.style.Descending {
+			return a > b
+		}
+		return a < b
+	})
+	return nil
+}
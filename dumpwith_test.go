@@ -0,0 +1,167 @@
+package fuzzdump_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDirWith(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(3)"),
+		dir + "/2": corpusFile("int(1)"),
+		dir + "/3": corpusFile("int(2)"),
+	}
+	tests := map[string]struct {
+		o    Options
+		opts []Option
+		want string
+	}{"zero": {
+		want: "{\n\tint(3),\n\tint(1),\n\tint(2),\n}\n",
+	}, "styled": {
+		o: Options{
+			Indent:             "    ",
+			Separators:         &format.Separators{Pre: "var seeds = []int{", Post: "}"},
+			OmitTrailingCommas: true,
+			OmitTypeNames:      true,
+		},
+		want: "var seeds = []int{\n    3,\n    1,\n    2\n}\n",
+	}, "sorted": {
+		o:    Options{Sort: SortByContents},
+		want: "{\n\tint(1),\n\tint(2),\n\tint(3),\n}\n",
+	}, "max entries": {
+		o:    Options{Sort: SortByContents, MaxEntries: 1},
+		want: "{\n\tint(1),\n\t// ... 2 entries omitted\n}\n",
+	}, "max entries not reached": {
+		o:    Options{MaxEntries: 3},
+		want: "{\n\tint(3),\n\tint(1),\n\tint(2),\n}\n",
+	}, "offset": {
+		o:    Options{Sort: SortByContents, Offset: 1, MaxEntries: 1},
+		want: "{\n\t// ... 1 entry omitted\n\tint(2),\n\t// ... 1 entry omitted\n}\n",
+	}, "offset past the end": {
+		o:    Options{Offset: 5},
+		want: "{\n\t// ... 3 entries omitted\n}\n",
+	}, "limit and offset options": {
+		opts: []Option{WithCanonical(), WithOffset(2), WithLimit(1)},
+		want: "{\n\t// ... 2 entries omitted\n\tint(3),\n}\n",
+	}, "options over limit": {
+		o:    Options{MaxEntries: 2},
+		opts: []Option{WithLimit(1)},
+		want: "{\n\tint(3),\n\tint(1),\n\t// ... 1 entry omitted\n}\n",
+	}, "with options": {
+		o:    Options{MaxEntries: 2, OmitTypeNames: true},
+		opts: []Option{WithCanonical()},
+		want: "{\n\t1,\n\t2,\n\t// ... 1 entry omitted\n}\n",
+	}, "filter": {
+		o:    Options{Filter: skip("int(1)")},
+		want: "{\n\tint(3),\n\tint(2),\n}\n",
+	}, "filter and max entries": {
+		o:    Options{Filter: skip("int(3)"), MaxEntries: 1},
+		want: "{\n\tint(1),\n\t// ... 1 entry omitted\n}\n",
+	}, "filter with options": {
+		o:    Options{Filter: skip("int(3)")},
+		opts: []Option{WithMax(0, 1)},
+		want: "{\n\tint(1),\n}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			req := require.New(t)
+			req.NoError(DumpDirWith(w, fsys, dir, tt.o, tt.opts...))
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
+
+// skip returns an [Options.Filter] that skips the entries of a single
+// line.
+func skip(line string) func(corpus.Entry) bool {
+	return func(e corpus.Entry) bool {
+		return len(e) != 1 || string(e[0]) != line
+	}
+}
+
+func TestDumpDirWith_bytes(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{dir + "/1": corpusFile(`[]byte("\x00hi")`)}
+	tests := map[string]struct {
+		o    Options
+		opts []Option
+		want string
+	}{"options": {
+		o:    Options{BytesFormat: format.BytesHex},
+		want: "{\n\t[]byte{0x00, 0x68, 0x69},\n}\n",
+	}, "option": {
+		opts: []Option{WithBytesFormat(format.BytesSummary)},
+		want: "{\n\t[]byte(/* 3 bytes */),\n}\n",
+	}, "options over option": {
+		o:    Options{BytesFormat: format.BytesBase64},
+		opts: []Option{WithBytesFormat(format.BytesSummary)},
+		want: "{\n\tbase64(\"AGhp\"),\n}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			req := require.New(t)
+			req.NoError(DumpDirWith(w, fsys, dir, tt.o, tt.opts...))
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
+
+func TestDumpDirWith_sort(t *testing.T) {
+	const dir = "corpus"
+	files := map[string]struct {
+		value string
+		mtime int64
+	}{
+		"a": {"int(100)", 2},
+		"b": {"int(2)", 3},
+		"c": {"int(30)", 1},
+	}
+	fsys := fstest.MapFS{}
+	var byHash []string // Values, by the hashes of their files.
+	for name, f := range files {
+		mf := corpusFile(f.value)
+		mf.ModTime = time.Unix(f.mtime, 0)
+		fsys[dir+"/"+name] = mf
+		byHash = append(byHash, corpus.FileName(mf.Data)+" "+f.value)
+	}
+	sort.Strings(byHash)
+	for i, v := range byHash {
+		byHash[i] = v[strings.IndexByte(v, ' ')+1:]
+	}
+	tests := map[string]struct {
+		o    Options
+		opts []Option
+		want []string
+	}{
+		"name":             {want: []string{"int(100)", "int(2)", "int(30)"}},
+		"name descending":  {o: Options{Descending: true}, want: []string{"int(30)", "int(2)", "int(100)"}},
+		"contents":         {o: Options{Sort: SortByContents}, want: []string{"int(100)", "int(2)", "int(30)"}},
+		"size":             {o: Options{Sort: SortBySize}, want: []string{"int(2)", "int(30)", "int(100)"}},
+		"size descending":  {o: Options{Sort: SortBySize, Descending: true}, want: []string{"int(100)", "int(30)", "int(2)"}},
+		"mod time":         {o: Options{Sort: SortByModTime}, want: []string{"int(30)", "int(100)", "int(2)"}},
+		"hash":             {o: Options{Sort: SortByHash}, want: byHash},
+		"hash descending":  {o: Options{Sort: SortByHash, Descending: true}, want: []string{byHash[2], byHash[1], byHash[0]}},
+		"option":           {opts: []Option{WithSort(SortBySize, true)}, want: []string{"int(100)", "int(30)", "int(2)"}},
+		"options override": {o: Options{Sort: SortByModTime}, opts: []Option{WithSort(SortBySize, true)}, want: []string{"int(30)", "int(100)", "int(2)"}},
+		"canonical":        {o: Options{Sort: SortBySize}, opts: []Option{WithCanonical()}, want: []string{"int(100)", "int(2)", "int(30)"}},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			req := require.New(t)
+			req.NoError(DumpDirWith(w, fsys, dir, tt.o, tt.opts...))
+			req.Equal("{\n\t"+strings.Join(tt.want, ",\n\t")+",\n}\n", w.String())
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package fuzzdump
+
+import (
+	"io/fs"
+	"path"
+)
+
+// Duplicates returns the names of the entry files in the fuzz test
+// corpus directory dir in fsys that hold the same values as another,
+// grouped by those values, e.g., as long fuzzing runs accumulate them.
+// The entries are compared by their decoded values, so the files of an
+// entry with the values encoded differently, e.g. int(1) and int(0x1),
+// are duplicates too.
+//
+// The names in each group are sorted, and the groups are in the order
+// of their first names, which are those of the entries to keep, if the
+// rest are to be removed. Entries without any duplicates are left out.
+//
+// The invalid entries are reported in [CorpusErrors], along with the
+// duplicates of the valid ones. Any other error is returned as it is.
+func Duplicates(fsys fs.FS, dir string) (dups [][]string, err error) {
+	files, err := getFiles(fsys, dir)
+	if err != nil {
+		return
+	}
+	var errs CorpusErrors
+	groups := map[string]int{} // Indices in dups, by the key of the values.
+	var all [][]string
+	for _, f := range files {
+		name := f.Name()
+		b, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, readErr(err, name)
+		}
+		_, key, err := statEntry(b)
+		if err != nil {
+			errs.append(readErr(err, name))
+			continue
+		}
+		i, ok := groups[key]
+		if !ok {
+			i = len(all)
+			groups[key] = i
+			all = append(all, nil)
+		}
+		all[i] = append(all[i], name)
+	}
+	for _, names := range all {
+		if len(names) > 1 {
+			dups = append(dups, names)
+		}
+	}
+	return dups, errs.AsError()
+}
@@ -0,0 +1,35 @@
+package fuzzdump_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicates(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{
+		dir + "/a": corpusFile("int(1)\nstring(\"a\")"),
+		dir + "/b": corpusFile("int(2)\nstring(\"a\")"),
+		dir + "/c": corpusFile("int(0x1)\nstring(\"\\x61\")"),
+		dir + "/d": corpusFile("int(2)\nstring(\"a\")"),
+		dir + "/e": corpusFile("int(3)\nstring(\"a\")"),
+		dir + "/f": corpusFile("int(1)\nstring(\"a\")"),
+		dir + "/g": corpusFile("int("),
+	}
+	got, err := Duplicates(fsys, dir)
+	req := require.New(t)
+	req.ErrorIs(err, ErrMalformedValue)
+	req.Equal([][]string{{"a", "c", "f"}, {"b", "d"}}, got)
+
+	delete(fsys, dir+"/g")
+	got, err = Duplicates(fsys, dir)
+	req.NoError(err)
+	req.Len(got, 2)
+
+	_, err = Duplicates(fsys, "absent")
+	req.ErrorIs(err, fs.ErrNotExist)
+}
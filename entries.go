@@ -0,0 +1,87 @@
+//go:build go1.23
+
+package fuzzdump
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// Entries returns an iterator over the entries from a fuzz test corpus
+// directory in fsys, each along with the name, size and modification
+// time of its file, and its hash, for a program to process them one at a
+// time, as they are read, and stop whenever it is done, e.g.:
+//
+//	for f, err := range fuzzdump.Entries(fsys, dir) {
+//		if err != nil {
+//			// ...
+//		}
+//		vals, err := f.Entry.Values()
+//		// ...
+//	}
+//
+// The entries are in the order of their file names, as they are read,
+// or, with [WithCanonical], in the order of their normalized contents,
+// all read first, or, with [WithSort], in the order it sets. The options
+// that only concern the output of [DumpDir], such as [WithPreview], or
+// [WithStrict], have no effect.
+//
+// The corpus is validated the same way as with DumpDir, and the errors
+// of the invalid entries are yielded, one at a time, after the valid
+// ones. Any other error, such as an I/O one, or [ErrEmptyCorpus] (along
+// with those of the invalid entries), is yielded as soon as it occurs,
+// and ends the iteration.
+func Entries(fsys fs.FS, dir string, opts ...Option) iter.Seq2[corpus.File, error] {
+	return func(yield func(corpus.File, error) bool) {
+		o, files := newOptions(opts).listing()
+		o.retain = o.sorted()
+		var entries []namedEntry
+		emit := func(name string, lines corpus.Entry) error {
+			if o.sorted() {
+				entries = append(entries, namedEntry{name, lines})
+				return nil
+			}
+			f, err := newFile(files, namedEntry{name, lines})
+			if err != nil {
+				return err
+			}
+			if !yield(f, nil) {
+				return errStopped
+			}
+			return nil
+		}
+		begin := func(int) error { return nil }
+		err := readDir(fsys, dir, o, begin, emit)
+		if errors.Is(err, errStopped) {
+			return
+		}
+		var errs CorpusErrors
+		if e := errs.Capture(err); e != nil {
+			yield(corpus.File{}, e)
+			return
+		}
+		o.sortEntries(entries)
+		for _, v := range entries {
+			f, err := newFile(files, v)
+			if err != nil {
+				yield(corpus.File{}, err)
+				return
+			}
+			if !yield(f, nil) {
+				return
+			}
+		}
+		for _, e := range errs {
+			if !yield(corpus.File{}, e) {
+				return
+			}
+		}
+	}
+}
+
+// errStopped stops reading a corpus when the iteration over its entries
+// ends early.
+var errStopped = errors.New("iteration stopped")
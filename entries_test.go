@@ -0,0 +1,108 @@
+//go:build go1.23
+
+package fuzzdump_test
+
+import (
+	"crypto/sha256"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntries(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{
+		dir + "/a": corpusFile("int(3)"),
+		dir + "/b": {Data: []byte("int(2)\n")},
+		dir + "/c": corpusFile("int(1)"),
+		dir + "/d": corpusFile("int(2)"),
+	}
+	tests := map[string]struct {
+		fsys   fs.FS
+		opts   []Option
+		limit  int
+		wNames []string
+		wErrs  []error
+	}{"all": {
+		fsys:   fsys,
+		wNames: []string{"a", "c", "d"},
+		wErrs:  []error{ErrShortEntry},
+	}, "canonical": {
+		fsys:   fsys,
+		opts:   []Option{WithCanonical()},
+		wNames: []string{"c", "d", "a"},
+		wErrs:  []error{ErrShortEntry},
+	}, "stopped early": {
+		fsys:   fsys,
+		limit:  2,
+		wNames: []string{"a", "c"},
+	}, "stopped early sorted": {
+		fsys:   fsys,
+		opts:   []Option{WithCanonical()},
+		limit:  1,
+		wNames: []string{"c"},
+	}, "filtered": {
+		fsys:   fsys,
+		opts:   []Option{WithMax(0, 2)},
+		wNames: []string{"c", "d"},
+		wErrs:  []error{ErrShortEntry},
+	}, "critical": {
+		fsys:   failingFS{fsys, dir + "/c"},
+		wNames: []string{"a"},
+		wErrs:  []error{errSnap},
+	}, "empty": {
+		fsys:  fstest.MapFS{dir + "/a": {Data: []byte("int(1)\n")}},
+		wErrs: []error{ErrEmptyCorpus},
+	}, "allowed empty": {
+		fsys: fstest.MapFS{},
+		opts: []Option{WithAllowEmpty()},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			var (
+				names []string
+				errs  []error
+			)
+			for f, err := range Entries(tt.fsys, dir, tt.opts...) {
+				if err != nil {
+					errs = append(errs, err)
+				} else {
+					names = append(names, f.Name)
+				}
+				if tt.limit > 0 && len(names) == tt.limit {
+					break
+				}
+			}
+			req := require.New(t)
+			req.Equal(tt.wNames, names)
+			req.Len(errs, len(tt.wErrs))
+			for i, err := range errs {
+				req.ErrorIs(err, tt.wErrs[i])
+			}
+		})
+	}
+}
+
+func TestEntries_metadata(t *testing.T) {
+	const dir = "corpus"
+	data := []byte(corpus.Version1 + "\nint(1)\n")
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{dir + "/1": {Data: data, ModTime: mtime}}
+	for _, opts := range [][]Option{nil, {WithCanonical()}} {
+		var files []corpus.File
+		for f, err := range Entries(fsys, dir, opts...) {
+			require.NoError(t, err)
+			files = append(files, f)
+		}
+		req := require.New(t)
+		req.Len(files, 1)
+		req.Equal(int64(len(data)), files[0].Size)
+		req.Equal(mtime, files[0].ModTime)
+		req.Equal(sha256.Sum256(data), files[0].Hash)
+	}
+}
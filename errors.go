@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
 )
 
 // ErrEmptyCorpus is returned when there are no files in fuzz corpus.
@@ -11,11 +13,38 @@ const ErrEmptyCorpus Error = "no valid fuzz corpus files in directory"
 
 // ErrMalformedEntry is returned when a corpus entry does not have a
 // supported format.
-const ErrMalformedEntry Error = "must include version and at least one value"
+const ErrMalformedEntry = corpus.ErrMalformedEntry
 
 // ErrUnsupportedVersion is returned when a corpus entry does not have a
 // supported version header.
-const ErrUnsupportedVersion Error = "unsupported encoding version"
+const ErrUnsupportedVersion = corpus.ErrUnsupportedVersion
+
+// ErrMalformedValue is returned when a value in a corpus entry cannot
+// be decoded.
+const ErrMalformedValue = corpus.ErrMalformedValue
+
+// ErrShortEntry is returned when a corpus entry file is empty or too
+// small to hold an entry. Such files are detected by their size, without
+// being read.
+const ErrShortEntry Error = "corpus entry file too short"
+
+// ErrMissingVersion is reported for a corpus entry file that lacks the
+// version header, but was taken for a version 1 one, as
+// [WithAssumeVersion1] allows. The entry is dumped nonetheless.
+const ErrMissingVersion Error = "corpus entry lacks version header"
+
+// ErrRejectedValue is returned when the inspection of a value that
+// [WithInspection] enables fails.
+const ErrRejectedValue Error = "corpus entry value rejected by inspection"
+
+// ErrFlaggedValue is returned by an inspection function that
+// [WithInspection] is given, to have a value reported, but its entry
+// dumped nonetheless.
+const ErrFlaggedValue Error = "corpus entry value flagged by inspection"
+
+// ErrMemoryCap is returned when the entries that have to be held in
+// memory take more than [WithMemoryCap] allows.
+const ErrMemoryCap Error = "corpus memory cap exceeded"
 
 // ErrInconsistentArgCount is returned when a corpus entry provides a
 // different number of arguments than what was first detected.
@@ -99,9 +128,10 @@ func (e CorpusErrors) lastIndex() int {
 
 // Capture non-critical errors, pass critical ones.
 //
-// When err is one of the entry validation errors ([ErrMalformedEntry]
-// or [ErrUnsupportedVersion], [ErrInconsistentArgCount]), it is
-// appended to e and nil is returned.
+// When err is one of the entry validation errors ([ErrMalformedEntry],
+// [ErrMalformedValue], [ErrUnsupportedVersion], [ErrShortEntry],
+// [ErrMissingVersion], [ErrTrailingGarbage] or
+// [ErrInconsistentArgCount]), it is appended to e and nil is returned.
 //
 // When err is [ErrEmptyCorpus], it also gets appended to e, but since
 // it occurs when corpus is not usable, the whole e is returned as an
@@ -138,28 +168,52 @@ func (e *CorpusErrors) Capture(err error) error {
 }
 
 // Error is a plain string type and can be used to define a constant.
-type Error string
-
-// Implements the [error] interface.
-func (e Error) Error() string {
-	return string(e)
-}
+// It is the same type as [corpus.Error].
+type Error = corpus.Error
 
 // append errs to e.
 func (e *CorpusErrors) append(errs ...error) { *e = append(*e, errs...) }
 
 // IsValidationError returns true if err is one of the entry validation
-// errors ([ErrMalformedEntry], [ErrUnsupportedVersion] or
+// errors ([ErrMalformedEntry], [ErrMalformedValue],
+// [ErrUnsupportedVersion], [ErrShortEntry], [ErrMissingVersion],
+// [ErrTrailingGarbage], [ErrRejectedValue], [ErrFlaggedValue] or
 // [ErrInconsistentArgCount]).
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrMalformedEntry) ||
+		errors.Is(err, ErrMalformedValue) ||
 		errors.Is(err, ErrUnsupportedVersion) ||
+		errors.Is(err, ErrShortEntry) ||
+		errors.Is(err, ErrMissingVersion) ||
+		errors.Is(err, ErrTrailingGarbage) ||
+		errors.Is(err, ErrRejectedValue) ||
+		errors.Is(err, ErrFlaggedValue) ||
 		errors.Is(err, ErrInconsistentArgCount)
 }
 
+// An EntryError reports the line (and column) in a corpus entry file
+// that an error, such as [ErrMalformedValue], concerns, with an excerpt
+// of it. It is the same type as [corpus.EntryError].
+type EntryError = corpus.EntryError
+
+// A FileError reports an error with the named corpus entry file. The
+// errors about individual files in [CorpusErrors] are of this type, so
+// [errors.As] tells which file each of them is about.
+type FileError struct {
+	Name string // Of the file.
+	Err  error
+}
+
+// Implements the [error] interface.
+func (e *FileError) Error() string { return fmt.Sprintf("reading %q: %v", e.Name, e.Err) }
+
+// Unwrap returns the underlying error.
+// Implements the interface required by [errors.Unwrap].
+func (e *FileError) Unwrap() error { return e.Err }
+
 func readErr(err error, fileName string) error {
 	if err != nil {
-		return fmt.Errorf("reading %q: %w", fileName, err)
+		return &FileError{fileName, err}
 	}
 	return nil
 }
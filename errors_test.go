@@ -200,6 +200,7 @@ func TestCorpusErrors_Capture(t *testing.T) {
 		ver  = ErrUnsupportedVersion
 		empt = ErrEmptyCorpus
 		args = ErrInconsistentArgCount
+		shrt = ErrShortEntry
 	)
 	type CE = CorpusErrors
 	tests := map[string]struct {
@@ -226,6 +227,10 @@ func TestCorpusErrors_Capture(t *testing.T) {
 		err:   args,
 		want:  nil,
 		wantE: CE{args},
+	}, "ErrShortEntry": {
+		err:   shrt,
+		want:  nil,
+		wantE: CE{shrt},
 	}, "snap": {
 		err:  errSnap,
 		want: errSnap,
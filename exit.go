@@ -0,0 +1,42 @@
+package fuzzdump
+
+import "errors"
+
+// Exit status codes that the fuzzdump command reports errors with, for
+// other tools to report the same errors consistently, see [ExitCodeFor].
+const (
+	// ExitSuccess reports that no error occurred.
+	ExitSuccess = iota
+	// ExitSoft reports that some corpus entries were invalid, but the
+	// others could be processed.
+	ExitSoft
+	// ExitEmptyCorpus reports that no valid corpus entries were found.
+	ExitEmptyCorpus
+	// ExitHard reports that another, critical error occurred.
+	ExitHard
+	// ExitThreshold reports that a corpus exceeded a threshold, such as
+	// the number of its entries, see [ErrThresholdExceeded].
+	ExitThreshold
+)
+
+// ErrThresholdExceeded is returned when a corpus exceeds a threshold,
+// such as the number of its entries, that it is checked against.
+const ErrThresholdExceeded Error = "corpus thresholds exceeded"
+
+// ExitCodeFor returns the exit status code that reports err: the
+// validation errors of individual entries (see [IsValidationError]) are
+// reported as [ExitSoft], and any other error, as [ExitHard].
+func ExitCodeFor(err error) int {
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.Is(err, ErrThresholdExceeded):
+		return ExitThreshold
+	case errors.Is(err, ErrEmptyCorpus):
+		return ExitEmptyCorpus
+	case IsValidationError(err):
+		return ExitSoft
+	default:
+		return ExitHard
+	}
+}
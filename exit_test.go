@@ -0,0 +1,40 @@
+package fuzzdump_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{"nil": {
+		want: ExitSuccess,
+	}, "validation": {
+		err:  CorpusErrors{&FileError{Name: "a", Err: ErrMalformedEntry}},
+		want: ExitSoft,
+	}, "empty corpus": {
+		err:  CorpusErrors{&FileError{Name: "a", Err: ErrMalformedEntry}, ErrEmptyCorpus},
+		want: ExitEmptyCorpus,
+	}, "threshold": {
+		err:  fmt.Errorf("%w: 2 entries (max 1)", ErrThresholdExceeded),
+		want: ExitThreshold,
+	}, "other": {
+		err:  fs.ErrNotExist,
+		want: ExitHard,
+	}, "unknown": {
+		err:  errors.New("foo"),
+		want: ExitHard,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			require.Equal(t, tt.want, ExitCodeFor(tt.err))
+		})
+	}
+}
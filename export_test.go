@@ -1,18 +1,43 @@
 package fuzzdump
 
-const XencVersion1 = encVersion1
+import (
+	"context"
+	"io/fs"
 
-var (
-	XmultiArgSep = multiArgSep
+	"github.com/antichris/go-fuzzdump/corpus"
+)
 
+const XIncomparable = incomparable
+
+var (
 	XcorpusFiles = corpusFiles
 
 	XfirstValidFileLines = firstValidFileLines
 
-	XdumpFiles = dumpFiles
+	XreadFiles = readFiles
 	XreadLines = readLines
 	XgetFiles  = getFiles
 
+	XhashValue = hashValue
+
+	XformatSize = formatSize
+
+	XcompareArg = compareArg
+	XcheckLens  = checkLens
+
 	XreadErr  = readErr
 	XwriteErr = writeErr
 )
+
+// Xprefetch starts a prefetcher, returning its read and stop methods.
+func Xprefetch(
+	ctx context.Context,
+	fsys fs.FS,
+	dir string,
+	files []fs.DirEntry,
+	read func(fs.FS, string) (corpus.Entry, error),
+	jobs int,
+) (func(fs.FS, string) (corpus.Entry, error), func()) {
+	p := prefetch(ctx, fsys, dir, files, read, jobs, options{}.minEntrySize())
+	return p.read, p.stop
+}
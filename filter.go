@@ -0,0 +1,207 @@
+package fuzzdump
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithMin skips the entries whose argument at index arg is not a number
+// greater than or equal to min.
+//
+// The comparison is exact for integer values of any size, but min
+// itself is subject to the precision of float64.
+//
+// Filtering requires decoding every value in the corpus. A value that
+// cannot be decoded is reported as [ErrMalformedValue], and its entry
+// is not dumped.
+func WithMin(arg int, min float64) Option {
+	return withMatch(func(vals []any) bool {
+		return compareArg(vals, arg, min) >= 0
+	})
+}
+
+// WithMax skips the entries whose argument at index arg is not a number
+// less than or equal to max.
+//
+// The same considerations apply as to [WithMin].
+func WithMax(arg int, max float64) Option {
+	return withMatch(func(vals []any) bool {
+		c := compareArg(vals, arg, max)
+		return c <= 0 && c != incomparable
+	})
+}
+
+// WithMinLen skips the entries with a string or []byte argument shorter
+// than n bytes.
+//
+// If any args indexes are given, only the arguments at those indexes are
+// checked, and an entry is skipped if any of them is not a string or a
+// []byte. Otherwise all string and []byte arguments are checked, and an
+// entry is skipped if it has none.
+//
+// The same considerations about decoding apply as to [WithMin].
+func WithMinLen(n int, args ...int) Option {
+	return withMatch(func(vals []any) bool {
+		return checkLens(vals, args, func(l int) bool { return l >= n })
+	})
+}
+
+// WithMaxLen skips the entries with a string or []byte argument longer
+// than n bytes.
+//
+// The args are treated the same way as by [WithMinLen].
+func WithMaxLen(n int, args ...int) Option {
+	return withMatch(func(vals []any) bool {
+		return checkLens(vals, args, func(l int) bool { return l <= n })
+	})
+}
+
+// WithEqual skips the entries whose argument at index arg is not equal
+// to v: of the same type, and with the same value, compared as encoded
+// in its normal form by [corpus.EncodeValue], so that, e.g., uint(42)
+// equals uint(0x2a), but not int(42), and a NaN equals a NaN of the same
+// bits.
+//
+// The same considerations about decoding apply as to [WithMin].
+func WithEqual(arg int, v corpus.Value) Option {
+	want, wErr := corpus.EncodeValue(v)
+	return withMatch(func(vals []any) bool {
+		if wErr != nil || arg < 0 || arg >= len(vals) {
+			return false
+		}
+		got, err := corpus.EncodeValue(vals[arg])
+		return err == nil && bytes.Equal(got, want)
+	})
+}
+
+// withMatch adds a predicate that an entry must satisfy to be dumped.
+func withMatch(m func(vals []any) bool) Option {
+	return func(o *options) { o.match = append(o.match, m) }
+}
+
+// filtered returns emit wrapped to skip the entries that do not satisfy
+// all the predicates of o, nor its [Options.Filter].
+func (o options) filtered(emit emitter) emitter {
+	keep := o.style.Filter
+	if len(o.match) == 0 && keep == nil {
+		return emit
+	}
+	return func(name string, lines corpus.Entry) error {
+		if len(o.match) > 0 {
+			vals, err := lines.Values()
+			if err != nil {
+				return err
+			}
+			for _, m := range o.match {
+				if !m(vals) {
+					return nil
+				}
+			}
+		}
+		if keep != nil && !keep(lines) {
+			return nil
+		}
+		return emit(name, lines)
+	}
+}
+
+// compareArg compares the numeric value of the argument at index arg
+// in vals to f, returning -1, 0 or +1, as [big.Float.Cmp] does.
+//
+// It returns incomparable if there is no such argument, the argument is
+// not a number, or either of the numbers is a NaN.
+func compareArg(vals []any, arg int, f float64) int {
+	if arg < 0 || arg >= len(vals) || math.IsNaN(f) {
+		return incomparable
+	}
+	v, ok := bigFloat(vals[arg])
+	if !ok {
+		return incomparable
+	}
+	return v.Cmp(big.NewFloat(f))
+}
+
+// checkLens returns true if the lengths of the string and []byte
+// arguments in vals at the indexes in args (or all of them, if there
+// are no args) satisfy ok.
+func checkLens(vals []any, args []int, ok func(l int) bool) bool {
+	if len(args) == 0 {
+		checked := false
+		for _, v := range vals {
+			if l, isStr := byteLen(v); isStr {
+				if !ok(l) {
+					return false
+				}
+				checked = true
+			}
+		}
+		return checked
+	}
+	for _, i := range args {
+		if i < 0 || i >= len(vals) {
+			return false
+		}
+		if l, isStr := byteLen(vals[i]); !isStr || !ok(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// byteLen returns the length of v in bytes, if it is a string or a
+// []byte.
+func byteLen(v any) (l int, ok bool) {
+	switch t := v.(type) {
+	case string:
+		return len(t), true
+	case []byte:
+		return len(t), true
+	}
+	return 0, false
+}
+
+// incomparable is returned by compareArg for values that cannot be
+// compared. It is less than any other result, so it never satisfies a
+// lower bound.
+const incomparable = -2
+
+// bigFloat returns the exact value of v, if v is a number other than a
+// NaN.
+func bigFloat(v any) (f *big.Float, ok bool) {
+	f = new(big.Float)
+	switch t := v.(type) {
+	case int:
+		f.SetInt64(int64(t))
+	case int8:
+		f.SetInt64(int64(t))
+	case int16:
+		f.SetInt64(int64(t))
+	case int32:
+		f.SetInt64(int64(t))
+	case int64:
+		f.SetInt64(t)
+	case uint:
+		f.SetUint64(uint64(t))
+	case uint8:
+		f.SetUint64(uint64(t))
+	case uint16:
+		f.SetUint64(uint64(t))
+	case uint32:
+		f.SetUint64(uint64(t))
+	case uint64:
+		f.SetUint64(t)
+	case float32:
+		return bigFloat(float64(t))
+	case float64:
+		if math.IsNaN(t) {
+			return nil, false
+		}
+		f.SetFloat64(t)
+	default:
+		return nil, false
+	}
+	return f, true
+}
@@ -0,0 +1,126 @@
+package fuzzdump_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDir_filters(t *testing.T) {
+	const (
+		fooOut = `{{
+	string("foo"),
+	uint(8),
+}}` + LF
+		barOut = `{{
+	string("bar"),
+	uint(13),
+}}` + LF
+		noneOut  = "{{\n}}\n"
+		multiOut = `{{
+	string("foo"),
+	uint(8),
+}, {
+	string("bar"),
+	uint(13),
+}}` + LF
+	)
+	tests := map[string]struct {
+		opts []Option
+		wOut string
+	}{
+		"min":            {[]Option{WithMin(1, 10)}, barOut},
+		"max":            {[]Option{WithMax(1, 10)}, fooOut},
+		"inclusive":      {[]Option{WithMin(1, 8), WithMax(1, 8)}, fooOut},
+		"out of range":   {[]Option{WithMin(1, 14)}, noneOut},
+		"not a number":   {[]Option{WithMax(0, 10)}, noneOut},
+		"no such arg":    {[]Option{WithMin(2, 0)}, noneOut},
+		"negative index": {[]Option{WithMax(-1, 0)}, noneOut},
+		"min len":        {[]Option{WithMinLen(3)}, multiOut},
+		"max len":        {[]Option{WithMaxLen(2)}, noneOut},
+		"min len arg":    {[]Option{WithMinLen(3, 0)}, multiOut},
+		"max len arg":    {[]Option{WithMaxLen(2, 0)}, noneOut},
+		"len non-string": {[]Option{WithMaxLen(10, 1)}, noneOut},
+		"len no arg":     {[]Option{WithMinLen(0, 2)}, noneOut},
+		"equal":          {[]Option{WithEqual(1, uint(8))}, fooOut},
+		"equal string":   {[]Option{WithEqual(0, "bar")}, barOut},
+		"equal type":     {[]Option{WithEqual(1, int(8))}, noneOut},
+		"equal no arg":   {[]Option{WithEqual(2, uint(8))}, noneOut},
+		"equal bad type": {[]Option{WithEqual(1, struct{}{})}, noneOut},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			err := DumpDir(w, fsys, multiDir, tt.opts...)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.wOut, w.String())
+		})
+	}
+	t.Run("bad value", func(t *testing.T) {
+		w := &strings.Builder{}
+		err := DumpDir(w, fsys, badValueDir, WithMin(0, 4))
+		req := require.New(t)
+		req.ErrorIs(err, ErrMalformedValue)
+		req.Equal("{\n\tuint(5),\n}\n", w.String())
+	})
+}
+
+func Test_checkLens(t *testing.T) {
+	vals := []any{"ab", 1, []byte("abcd")}
+	tests := map[string]struct {
+		vals []any
+		args []int
+		max  int
+		want bool
+	}{
+		"all":          {vals: vals, max: 4, want: true},
+		"all too long": {vals: vals, max: 3, want: false},
+		"arg":          {vals: vals, args: []int{0}, max: 3, want: true},
+		"args":         {vals: vals, args: []int{0, 2}, max: 3, want: false},
+		"non-string":   {vals: vals, args: []int{1}, max: 3, want: false},
+		"out of range": {vals: vals, args: []int{3}, max: 3, want: false},
+		"none":         {vals: []any{1}, max: 3, want: false},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			ok := func(l int) bool { return l <= tt.max }
+			got := XcheckLens(tt.vals, tt.args, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_compareArg(t *testing.T) {
+	tests := map[string]struct {
+		v    any
+		f    float64
+		want int
+	}{
+		"int":          {int(-1), 0, -1},
+		"int8":         {int8(1), 1, 0},
+		"int16":        {int16(1), 0, 1},
+		"int32":        {int32(1), 0, 1},
+		"int64":        {int64(math.MaxInt64), math.MaxInt64, -1},
+		"uint":         {uint(1), 0, 1},
+		"uint8":        {uint8(1), 0, 1},
+		"uint16":       {uint16(1), 0, 1},
+		"uint32":       {uint32(1), 0, 1},
+		"uint64":       {uint64(math.MaxUint64), math.MaxUint64, -1},
+		"float32":      {float32(0.5), 0.5, 0},
+		"float64":      {0.1, 0.2, -1},
+		"+Inf":         {math.Inf(1), math.MaxFloat64, 1},
+		"NaN value":    {math.NaN(), 0, XIncomparable},
+		"NaN bound":    {0.0, math.NaN(), XIncomparable},
+		"not a number": {"1", 0, XIncomparable},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := XcompareArg([]any{tt.v}, 0, tt.f)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package fuzzdump
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// ErrBadFilter is returned by [ParseFilter] for an expression that is
+// not valid.
+const ErrBadFilter Error = "invalid filter expression"
+
+// WithFilter skips the entries whose values keep returns false for, e.g.,
+// as [ParseFilter] returns it.
+//
+// The same considerations about decoding apply as to [WithMin].
+func WithFilter(keep func(vals []corpus.Value) bool) Option {
+	return withMatch(keep)
+}
+
+// ParseFilter returns a predicate of the values of an entry that holds
+// when the filter expression expr does, for [WithFilter], e.g.:
+//
+//	arg[0].type == "string" && len > 100
+//
+// The expressions are written in a subset of the Go syntax:
+//
+//   - arg[N] is the value of the argument at index N, a number, a
+//     string (also for a []byte), or a bool;
+//   - arg[N].type is the name of its type, e.g. "int", "[]byte", or
+//     "uint8" for a byte;
+//   - len(x) is the length in bytes of a string or []byte x;
+//   - len is the total length of all the string and []byte arguments,
+//     and args is the number of arguments;
+//   - the literals are numbers, strings, characters (as numbers), true
+//     and false;
+//   - the operators are ==, !=, <, <=, >, >=, !, &&, ||, unary - and
+//     parentheses.
+//
+// A comparison with an argument that an entry does not have, or with a
+// value of another kind, e.g. arg[0] > 5 for a string argument, is
+// false, save for one with !=, which is true. Comparisons of literals
+// of different kinds, and any other syntax are reported as
+// [ErrBadFilter], along with the column where they are found.
+func ParseFilter(expr string) (keep func(vals []corpus.Value) bool, err error) {
+	e, err := parser.ParseExpr(renameTypeSelectors(expr))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadFilter, err)
+	}
+	n, err := compileFilter(e)
+	if err == nil && n.kind != boolKind && n.kind != anyKind {
+		err = filterErr(e, "not a condition")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func(vals []corpus.Value) bool {
+		b, _ := n.eval(vals).(bool)
+		return b
+	}, nil
+}
+
+// renameTypeSelectors returns expr with each type selector, which Go
+// does not allow, as it is a keyword, renamed to typeSelector.
+func renameTypeSelectors(expr string) string {
+	src, b := []byte(expr), []byte(expr)
+	fset := token.NewFileSet()
+	var s scanner.Scanner
+	// Errors are left for the parser to report.
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, 0)
+	prev := token.ILLEGAL
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			return string(b)
+		}
+		if tok == token.TYPE && prev == token.PERIOD {
+			copy(b[fset.Position(pos).Offset:], typeSelector)
+		}
+		prev = tok
+	}
+}
+
+// typeSelector is what type selectors are renamed to, of the same length
+// as "type", to keep the columns of the rest of the expression.
+const typeSelector = "typ_"
+
+// A filterKind is the kind of the result of a filterNode, if it is known
+// before evaluating it.
+type filterKind int
+
+const (
+	anyKind filterKind = iota
+	boolKind
+	numKind
+	strKind
+)
+
+// A filterNode is a compiled filter expression.
+type filterNode struct {
+	kind filterKind
+	// eval returns the value of the expression for the entry with vals,
+	// a bool, a *big.Float, a string, or nil if it has none.
+	eval func(vals []corpus.Value) any
+}
+
+// compileFilter compiles the filter expression e.
+func compileFilter(e ast.Expr) (n filterNode, err error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return compileFilter(e.X)
+	case *ast.BasicLit:
+		v, err := filterLiteral(e)
+		if err != nil {
+			return n, err
+		}
+		return constNode(v), nil
+	case *ast.Ident:
+		switch e.Name {
+		case "true", "false":
+			return constNode(e.Name == "true"), nil
+		case "len":
+			return filterNode{numKind, totalLen}, nil
+		case "args":
+			return filterNode{numKind, func(vals []corpus.Value) any {
+				return new(big.Float).SetInt64(int64(len(vals)))
+			}}, nil
+		}
+		return n, filterErr(e, "unknown identifier %q", e.Name)
+	case *ast.IndexExpr:
+		i, err := argIndex(e)
+		if err != nil {
+			return n, err
+		}
+		return filterNode{anyKind, func(vals []corpus.Value) any {
+			if i >= len(vals) {
+				return nil
+			}
+			return filterValue(vals[i])
+		}}, nil
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.IndexExpr)
+		if !ok || e.Sel.Name != typeSelector {
+			break
+		}
+		i, err := argIndex(x)
+		if err != nil {
+			return n, err
+		}
+		return filterNode{strKind, func(vals []corpus.Value) any {
+			if i >= len(vals) {
+				return nil
+			}
+			return typeName(vals[i])
+		}}, nil
+	case *ast.CallExpr:
+		if f, ok := e.Fun.(*ast.Ident); !ok || f.Name != "len" || len(e.Args) != 1 {
+			break
+		}
+		x, err := compileFilter(e.Args[0])
+		if err != nil {
+			return n, err
+		}
+		if x.kind != strKind && x.kind != anyKind {
+			return n, filterErr(e, "invalid argument of len")
+		}
+		return filterNode{numKind, func(vals []corpus.Value) any {
+			s, ok := x.eval(vals).(string)
+			if !ok {
+				return nil
+			}
+			return new(big.Float).SetInt64(int64(len(s)))
+		}}, nil
+	case *ast.UnaryExpr:
+		return compileUnary(e)
+	case *ast.BinaryExpr:
+		return compileBinary(e)
+	}
+	return n, filterErr(e, "unsupported expression")
+}
+
+// compileUnary compiles the filter expression e with a unary operator.
+func compileUnary(e *ast.UnaryExpr) (n filterNode, err error) {
+	x, err := compileFilter(e.X)
+	if err != nil {
+		return
+	}
+	switch {
+	case e.Op == token.NOT && (x.kind == boolKind || x.kind == anyKind):
+		return filterNode{boolKind, func(vals []corpus.Value) any {
+			b, _ := x.eval(vals).(bool)
+			return !b
+		}}, nil
+	case e.Op == token.SUB && (x.kind == numKind || x.kind == anyKind):
+		return filterNode{numKind, func(vals []corpus.Value) any {
+			f, ok := x.eval(vals).(*big.Float)
+			if !ok {
+				return nil
+			}
+			return new(big.Float).Neg(f)
+		}}, nil
+	}
+	return n, filterErr(e, "invalid operation %s", e.Op)
+}
+
+// compileBinary compiles the filter expression e with a binary operator.
+func compileBinary(e *ast.BinaryExpr) (n filterNode, err error) {
+	x, err := compileFilter(e.X)
+	if err != nil {
+		return
+	}
+	y, err := compileFilter(e.Y)
+	if err != nil {
+		return
+	}
+	switch e.Op {
+	case token.LAND, token.LOR:
+		for _, k := range []filterKind{x.kind, y.kind} {
+			if k != boolKind && k != anyKind {
+				return n, filterErr(e, "invalid operation %s", e.Op)
+			}
+		}
+		and := e.Op == token.LAND
+		return filterNode{boolKind, func(vals []corpus.Value) any {
+			a, _ := x.eval(vals).(bool)
+			if a != and {
+				return a
+			}
+			b, _ := y.eval(vals).(bool)
+			return b
+		}}, nil
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+	default:
+		return n, filterErr(e, "invalid operation %s", e.Op)
+	}
+	if x.kind != anyKind && y.kind != anyKind && x.kind != y.kind {
+		return n, filterErr(e, "mismatched operands of %s", e.Op)
+	}
+	if (x.kind == boolKind || y.kind == boolKind) && e.Op != token.EQL && e.Op != token.NEQ {
+		return n, filterErr(e, "invalid operation %s on bool", e.Op)
+	}
+	op := e.Op
+	return filterNode{boolKind, func(vals []corpus.Value) any {
+		c, ok := compareFilter(x.eval(vals), y.eval(vals))
+		switch op {
+		case token.EQL:
+			return ok && c == 0
+		case token.NEQ:
+			return !ok || c != 0
+		case token.LSS:
+			return ok && c < 0
+		case token.LEQ:
+			return ok && c <= 0
+		case token.GTR:
+			return ok && c > 0
+		}
+		return ok && c >= 0
+	}}, nil
+}
+
+// compareFilter compares the values a and b, as [big.Float.Cmp] does,
+// if they are of the same kind. Bools compare as equal or not.
+func compareFilter(a, b any) (c int, ok bool) {
+	switch a := a.(type) {
+	case bool:
+		b, ok := b.(bool)
+		if !ok || a == b {
+			return 0, ok
+		}
+		return 1, true
+	case *big.Float:
+		b, ok := b.(*big.Float)
+		if !ok {
+			return 0, false
+		}
+		return a.Cmp(b), true
+	case string:
+		b, ok := b.(string)
+		return strings.Compare(a, b), ok
+	}
+	return 0, false
+}
+
+// constNode returns a filterNode that evaluates to v.
+func constNode(v any) filterNode {
+	k := boolKind
+	switch v.(type) {
+	case *big.Float:
+		k = numKind
+	case string:
+		k = strKind
+	}
+	return filterNode{k, func([]corpus.Value) any { return v }}
+}
+
+// filterLiteral returns the value of the literal e.
+func filterLiteral(e *ast.BasicLit) (v any, err error) {
+	switch e.Kind {
+	case token.INT:
+		i, ok := new(big.Int).SetString(e.Value, 0)
+		if ok {
+			return new(big.Float).SetInt(i), nil
+		}
+	case token.FLOAT:
+		// As precise as a float64, to compare equal to the same one.
+		f, _, err := big.ParseFloat(e.Value, 0, 53, big.ToNearestEven)
+		if err == nil {
+			return f, nil
+		}
+	case token.CHAR:
+		r, _, _, err := strconv.UnquoteChar(e.Value[1:len(e.Value)-1], '\'')
+		if err == nil {
+			return new(big.Float).SetInt64(int64(r)), nil
+		}
+	case token.STRING:
+		s, err := strconv.Unquote(e.Value)
+		if err == nil {
+			return s, nil
+		}
+	}
+	return nil, filterErr(e, "invalid literal %s", e.Value)
+}
+
+// argIndex returns the index N of the argument that e, arg[N], is.
+func argIndex(e *ast.IndexExpr) (int, error) {
+	if x, ok := e.X.(*ast.Ident); !ok || x.Name != "arg" {
+		return 0, filterErr(e, "unsupported expression")
+	}
+	if l, ok := e.Index.(*ast.BasicLit); ok && l.Kind == token.INT {
+		if i, err := strconv.ParseInt(l.Value, 0, 0); err == nil {
+			return int(i), nil
+		}
+	}
+	return 0, filterErr(e.Index, "invalid argument index")
+}
+
+// filterValue returns the value of v as a filter expression evaluates
+// it, or nil if it has none.
+func filterValue(v corpus.Value) any {
+	switch v := v.(type) {
+	case bool, string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	if f, ok := bigFloat(v); ok {
+		return f
+	}
+	return nil
+}
+
+// totalLen returns the total length of the string and []byte values in
+// vals.
+func totalLen(vals []corpus.Value) any {
+	n := 0
+	for _, v := range vals {
+		l, _ := byteLen(v)
+		n += l
+	}
+	return new(big.Float).SetInt64(int64(n))
+}
+
+// filterErr returns an [ErrBadFilter] reporting the problem with e.
+func filterErr(e ast.Node, format string, a ...any) error {
+	return fmt.Errorf("%w: column %d: %s",
+		ErrBadFilter, e.Pos(), fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,102 @@
+package fuzzdump_test
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	vals := []corpus.Value{"foo", []byte("barbaz"), int(-5), 2.5, true, byte('a')}
+	tests := map[string]struct {
+		expr string
+		want bool
+	}{
+		"string":             {`arg[0] == "foo"`, true},
+		"bytes":              {`arg[1] == "barbaz"`, true},
+		"string order":       {`arg[0] < "goo"`, true},
+		"int":                {`arg[2] == -5`, true},
+		"int hex":            {`arg[2] > -0x6`, true},
+		"float":              {`arg[3] >= 2.5 && arg[3] < 3`, true},
+		"bool":               {`arg[4]`, true},
+		"not bool":           {`!arg[4]`, false},
+		"byte char":          {`arg[5] == 'a'`, true},
+		"type":               {`arg[1].type == "[]byte"`, true},
+		"byte type":          {`arg[5].type == "uint8"`, true},
+		"len":                {`len == 9`, true},
+		"len arg":            {`len(arg[1]) > 5`, true},
+		"len not string":     {`len(arg[2]) > 0`, false},
+		"args":               {`args == 6`, true},
+		"or":                 {`arg[0] == "bar" || arg[2] < 0`, true},
+		"parens":             {`!(arg[0] == "bar" || arg[2] < 0)`, false},
+		"kind mismatch":      {`arg[0] > 5`, false},
+		"kind mismatch neq":  {`arg[0] != 5`, true},
+		"missing arg":        {`arg[9] == 1`, false},
+		"missing arg type":   {`arg[9].type == "int"`, false},
+		"type in string":     {`arg[0] != ".type"`, true},
+		"example from issue": {`arg[0].type == "string" && len > 100`, false},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			keep, err := ParseFilter(tt.expr)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.want, keep(vals))
+		})
+	}
+}
+
+func TestParseFilter_invalid(t *testing.T) {
+	tests := map[string]struct {
+		expr string
+		wErr string
+	}{
+		"syntax":          {`arg[0] ==`, "invalid filter expression: 1:10: expected operand, found 'EOF'"},
+		"identifier":      {`foo == 1`, `invalid filter expression: column 1: unknown identifier "foo"`},
+		"not a condition": {`len`, "invalid filter expression: column 1: not a condition"},
+		"mismatched":      {`len == "a"`, "invalid filter expression: column 1: mismatched operands of =="},
+		"bool order":      {`true < false`, "invalid filter expression: column 1: invalid operation < on bool"},
+		"arithmetic":      {`len + 1 > 2`, "invalid filter expression: column 1: invalid operation +"},
+		"and number":      {`len && true`, "invalid filter expression: column 1: invalid operation &&"},
+		"index":           {`arg[x] == 1`, "invalid filter expression: column 5: invalid argument index"},
+		"indexed":         {`foo[0] == 1`, "invalid filter expression: column 1: unsupported expression"},
+		"selector":        {`arg[0].size == 1`, "invalid filter expression: column 1: unsupported expression"},
+		"call":            {`cap(arg[0]) == 1`, "invalid filter expression: column 1: unsupported expression"},
+		"len of number":   {`len(1) == 1`, "invalid filter expression: column 1: invalid argument of len"},
+		"imaginary":       {`arg[0] == 1i`, "invalid filter expression: column 11: invalid literal 1i"},
+	}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr)
+			req := require.New(t)
+			req.ErrorIs(err, ErrBadFilter)
+			req.EqualError(err, tt.wErr)
+		})
+	}
+}
+
+func TestWithFilter(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{
+		dir:        {Mode: fs.ModeDir},
+		dir + "/1": corpusFile("int(1)\nstring(\"a\")"),
+		dir + "/2": corpusFile("int(2)\nstring(\"bcd\")"),
+		dir + "/3": corpusFile("int(3)\nstring(\"ef\")"),
+	}
+	keep, err := ParseFilter(`len > 1 && arg[0] != 3`)
+	req := require.New(t)
+	req.NoError(err)
+	got := &bytes.Buffer{}
+	req.NoError(DumpDir(got, fsys, dir, WithFilter(keep)))
+	want := &bytes.Buffer{}
+	req.NoError(DumpDir(want, fstest.MapFS{
+		dir:        {Mode: fs.ModeDir},
+		dir + "/2": fsys[dir+"/2"],
+	}, dir))
+	req.Equal(want.String(), got.String())
+}
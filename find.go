@@ -0,0 +1,92 @@
+package fuzzdump
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithFind skips the entries without a string or []byte argument that
+// contains the byte sequence needle, and notes where it is found in a
+// comment ahead of the values of each of the rest, e.g.:
+//
+//	{
+//		// found in arg1 at 3, 17
+//		int(2),
+//		[]byte("..."),
+//	}
+//
+// The offsets are those of the bytes of the values, not of their encoding
+// in the corpus entry file. An empty needle is not searched for.
+//
+// The same considerations about decoding apply as to [WithMin].
+func WithFind(needle []byte) Option {
+	if len(needle) == 0 {
+		return func(*options) {}
+	}
+	match := withMatch(func(vals []any) bool {
+		for _, offsets := range findAll(vals, needle) {
+			if len(offsets) > 0 {
+				return true
+			}
+		}
+		return false
+	})
+	return func(o *options) {
+		match(o)
+		o.find = needle
+	}
+}
+
+// findAll returns the offsets of all the occurrences of needle, however
+// overlapping, in each of vals that is a string or a []byte.
+func findAll(vals []any, needle []byte) [][]int {
+	offsets := make([][]int, len(vals))
+	for i, v := range vals {
+		var b []byte
+		switch v := v.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		default:
+			continue
+		}
+		for at := 0; ; at++ {
+			n := bytes.Index(b[at:], needle)
+			if n < 0 {
+				break
+			}
+			at += n
+			offsets[i] = append(offsets[i], at)
+		}
+	}
+	return offsets
+}
+
+// findNote returns the comment on where needle is found in the entry
+// that lines hold, see [WithFind], or an empty string if it is not, or
+// the lines cannot be decoded.
+func findNote(lines corpus.Entry, needle []byte) string {
+	vals, err := lines.Values()
+	if err != nil {
+		return ""
+	}
+	var args []string
+	for i, offsets := range findAll(vals, needle) {
+		if len(offsets) == 0 {
+			continue
+		}
+		at := make([]string, len(offsets))
+		for j, o := range offsets {
+			at[j] = strconv.Itoa(o)
+		}
+		args = append(args, "arg"+strconv.Itoa(i)+" at "+strings.Join(at, ", "))
+	}
+	if args == nil {
+		return ""
+	}
+	return "found in " + strings.Join(args, "; ")
+}
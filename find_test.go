@@ -0,0 +1,45 @@
+package fuzzdump_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFind(t *testing.T) {
+	const dir = "found"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(1)\n[]byte(\"\\xde\\xad\\xbe\\xef\")"),
+		dir + "/2": corpusFile("int(2)\nstring(\"no magic\")"),
+		dir + "/3": corpusFile("string(\"aaa\")\n[]byte(\"baaab\")"),
+	}
+	tests := map[string]struct {
+		needle []byte
+		want   string
+	}{"magic": {
+		needle: []byte{0xde, 0xad},
+		want:   "{{\n\t// found in arg1 at 0\n\tint(1),\n\t[]byte(\"\\xde\\xad\\xbe\\xef\"),\n}}\n",
+	}, "overlapping in several args": {
+		needle: []byte("aa"),
+		want: "{{\n\t// found in arg0 at 0, 1; arg1 at 1, 2\n" +
+			"\tstring(\"aaa\"),\n\t[]byte(\"baaab\"),\n}}\n",
+	}, "none": {
+		needle: []byte("zz"),
+		want:   "{{\n}}\n",
+	}, "empty": {
+		want: "{{\n\tint(1),\n\t[]byte(\"\\xde\\xad\\xbe\\xef\"),\n" +
+			"}, {\n\tint(2),\n\tstring(\"no magic\"),\n" +
+			"}, {\n\tstring(\"aaa\"),\n\t[]byte(\"baaab\"),\n}}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			req := require.New(t)
+			req.NoError(DumpDir(w, fsys, dir, WithFind(tt.needle), WithAllowEmpty()))
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
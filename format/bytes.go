@@ -0,0 +1,73 @@
+package format
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// A BytesFormat is how a [Printer] renders []byte values, which are
+// unreadable as escaped strings when they hold binary data.
+type BytesFormat int
+
+const (
+	// BytesRaw renders []byte values as they are, as conversions of
+	// escaped string literals, e.g. []byte("\x00\x01hi").
+	BytesRaw BytesFormat = iota
+	// BytesHex renders []byte values as Go composite literals of hex
+	// bytes, e.g. []byte{0x00, 0x01, 0x68, 0x69}.
+	BytesHex
+	// BytesHexDump renders []byte values as their lengths, as
+	// BytesSummary does, preceded by comment lines with a hex dump of
+	// their contents, as [hex.Dump] formats it, e.g.:
+	//
+	//	// 00000000  00 01 68 69                                       |..hi|
+	//	[]byte(/* 4 bytes */),
+	BytesHexDump
+	// BytesBase64 renders []byte values as their standard base64 encoding
+	// (RFC 4648), e.g. base64("AAFoaQ==").
+	BytesBase64
+	// BytesSummary renders []byte values as just their lengths, e.g.
+	// []byte(/* 4 bytes */).
+	BytesSummary
+)
+
+// bytesValue returns the value line v rendered in the format f, with the
+// comment lines to write ahead of it, if v is a []byte value that can be
+// decoded, or v as it is otherwise.
+func (f BytesFormat) bytesValue(v []byte) (line []byte, comment []string) {
+	if f == BytesRaw || !bytes.HasPrefix(v, []byte("[]byte(")) {
+		return v, nil
+	}
+	dv, err := corpus.DecodeValue(v)
+	if err != nil {
+		return v, nil
+	}
+	b, ok := dv.([]byte)
+	if !ok {
+		return v, nil
+	}
+	switch f {
+	case BytesHex:
+		hs := make([]string, len(b))
+		for i, c := range b {
+			hs[i] = fmt.Sprintf("0x%02x", c)
+		}
+		return []byte("[]byte{" + strings.Join(hs, ", ") + "}"), nil
+	case BytesHexDump:
+		if len(b) > 0 {
+			comment = strings.Split(strings.TrimSuffix(hex.Dump(b), "\n"), "\n")
+		}
+	case BytesBase64:
+		return []byte(fmt.Sprintf("base64(%q)", base64.StdEncoding.EncodeToString(b))), nil
+	}
+	noun := "bytes"
+	if len(b) == 1 {
+		noun = "byte"
+	}
+	return []byte(fmt.Sprintf("[]byte(/* %d %s */)", len(b), noun)), comment
+}
@@ -0,0 +1,325 @@
+// Package format implements rendering fuzz test corpus entries in the
+// dump format.
+//
+// The dump of a single-argument corpus is similar to a plain slice with
+// the type omitted, e.g.:
+//
+//	{
+//		int(2),
+//		int(3),
+//	}
+//
+// The dump of a multiple-argument corpus is similar to a slice of
+// structs, again, with the type omitted, e.g.:
+//
+//	{{
+//		int(8),
+//		string("foo"),
+//	}, {
+//		int(13),
+//		string("bar"),
+//	}}
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// Separators are written around and between the entries of a dump:
+// Pre at its beginning, In between every two entries, and Post at its
+// end, each on a line of its own. An empty In is not written.
+type Separators struct{ Pre, In, Post string }
+
+var (
+	sigleArgSep = Separators{Pre: "{", Post: "}"}
+	multiArgSep = Separators{"{{", "}, {", "}}"}
+)
+
+// A Printer writes corpus entries to an output stream in the dump
+// format.
+type Printer struct {
+	// ArgLabels makes the printer prefix each value of a multiple-argument
+	// entry with a comment stating the index of the argument it holds,
+	// e.g. "/* arg0 */".
+	ArgLabels bool
+	// Framing, if set, makes the printer write each entry as a separate
+	// record, framed by it: a complete dump of just that entry. Nothing
+	// is written at the beginning or the end of the output then.
+	Framing Framing
+	// Indent is written ahead of each value and each comment line. They
+	// are indented with a tab, if it is empty.
+	Indent string
+	// Separators, if set, are written instead of those of the dump
+	// format, e.g., to make the dump a literal of a named slice type.
+	Separators *Separators
+	// OmitTrailingCommas makes the printer write no comma after the last
+	// value of each entry of a multiple-argument corpus, or after the
+	// last value of a single-argument one.
+	OmitTrailingCommas bool
+	// OmitTypeNames makes the printer write the values without their
+	// types, as untyped constants, e.g. 42 instead of int(42), save for
+	// the floats that have none, such as float64(NaN), which keep them.
+	OmitTypeNames bool
+	// Bytes sets how the printer renders []byte values, e.g. as hex
+	// literals, which binary data is more readable as.
+	Bytes BytesFormat
+
+	w       io.Writer
+	seps    Separators
+	multi   bool
+	count   int      // Of the entries printed so far.
+	omitted int      // Entries to be noted omitted before the next one.
+	comment []string // Lines of the comment on the next entry.
+	open    bool     // Whether the last value written lacks its line end.
+}
+
+// NewPrinter returns a printer that writes entries of argCount
+// arguments to w.
+func NewPrinter(w io.Writer, argCount int) *Printer {
+	p := &Printer{w: w, seps: sigleArgSep}
+	if argCount > 1 {
+		p.seps = multiArgSep
+		p.multi = true
+	}
+	return p
+}
+
+// Begin the output.
+func (p *Printer) Begin() error {
+	if p.Framing != nil {
+		return nil
+	}
+	return p.println(p.separators().Pre)
+}
+
+// Entry writes e to the output, separated from the previous entry.
+func (p *Printer) Entry(e corpus.Entry) error {
+	if p.Framing != nil {
+		return p.record(e)
+	}
+	// A single-argument entry continues the list of the previous one.
+	if err := p.endLine(!p.multi); err != nil {
+		return err
+	}
+	if in := p.separators().In; p.count > 0 && in != "" {
+		if err := p.println(in); err != nil {
+			return err
+		}
+	}
+	p.count++
+	if err := p.noteOmitted(); err != nil {
+		return err
+	}
+	for _, l := range p.comment {
+		if _, err := fmt.Fprintf(p.w, "%s// %s\n", p.indent(), l); err != nil {
+			return writeErr(err)
+		}
+	}
+	p.comment = nil
+	return p.values(e)
+}
+
+// Omit notes that n entries were left out of the output before the
+// next one, or at its end, with a comment written along with it, e.g.:
+//
+//	// ... 42 entries omitted
+//
+// Nothing is noted when the entries are framed.
+func (p *Printer) Omit(n int) {
+	p.omitted += n
+}
+
+// Comment sets the comment written along with the next entry, ahead of
+// its values, each line of text on its own line, e.g.:
+//
+//	// imported from corpus.json
+//
+// An empty text sets no comment.
+func (p *Printer) Comment(text string) {
+	p.comment = nil
+	if text != "" {
+		p.comment = strings.Split(text, "\n")
+	}
+}
+
+// End the output.
+func (p *Printer) End() error {
+	if p.Framing != nil {
+		return nil
+	}
+	if err := p.endLine(false); err != nil {
+		return err
+	}
+	if err := p.noteOmitted(); err != nil {
+		return err
+	}
+	return p.println(p.separators().Post)
+}
+
+// record writes a dump of just e to the output, framed by p.Framing.
+func (p *Printer) record(e corpus.Entry) error {
+	b := &bytes.Buffer{}
+	r := *p
+	r.Framing, r.w, r.count, r.omitted = nil, b, 0, 0
+	p.comment = nil
+	// Writing to a buffer never fails.
+	r.Begin()
+	r.Entry(e)
+	r.End()
+	p.count++
+	return writeErr(p.Framing(p.w, b.Bytes()))
+}
+
+// A Framing writes a record to w, framed so that a consumer reading a
+// stream of them can split it into records without parsing them.
+type Framing func(w io.Writer, record []byte) error
+
+// LengthPrefixed frames a record with a prefix of its length in bytes,
+// in decimal, followed by a newline, e.g.:
+//
+//	13
+//	{
+//		int(2),
+//	}
+func LengthPrefixed(w io.Writer, record []byte) error {
+	if _, err := fmt.Fprintf(w, "%d\n", len(record)); err != nil {
+		return err
+	}
+	_, err := w.Write(record)
+	return err
+}
+
+// RecordSeparated frames a record with a leading ASCII record separator
+// character (0x1E), as JSON text sequences (RFC 7464) do. Records end
+// with a newline.
+//
+// Every value in a record is quoted, so the separator character cannot
+// occur within a record, unless the corpus files were crafted by hand.
+func RecordSeparated(w io.Writer, record []byte) error {
+	if _, err := w.Write([]byte{recordSeparator}); err != nil {
+		return err
+	}
+	_, err := w.Write(record)
+	return err
+}
+
+const recordSeparator = 0x1e
+
+func (p *Printer) println(s string) error {
+	if _, err := fmt.Fprintln(p.w, s); err != nil {
+		return writeErr(err)
+	}
+	return nil
+}
+
+// values writes the value lines of an entry to the output, each
+// prefixed with a comment stating the index of the argument it holds,
+// if they are to be labeled. The line end of the last one is left to be
+// written with the next entry, or the end of the output, if it is to
+// lack a trailing comma.
+func (p *Printer) values(lines [][]byte) error {
+	for i, v := range lines {
+		v, comment := p.Bytes.bytesValue(v)
+		for _, l := range comment {
+			if _, err := fmt.Fprintf(p.w, "%s// %s\n", p.indent(), l); err != nil {
+				return writeErr(err)
+			}
+		}
+		if p.OmitTypeNames {
+			v = untyped(v)
+		}
+		label := ""
+		if p.ArgLabels && p.multi {
+			label = fmt.Sprintf("/* arg%d */ ", i)
+		}
+		end := ",\n"
+		if p.OmitTrailingCommas && i == len(lines)-1 {
+			end, p.open = "", true
+		}
+		if _, err := fmt.Fprintf(p.w, "%s%s%s%s", p.indent(), label, v, end); err != nil {
+			return writeErr(err)
+		}
+	}
+	return nil
+}
+
+// endLine ends the line of the last value written, with a comma, if
+// another value is to follow it, if it lacks its line end.
+func (p *Printer) endLine(comma bool) error {
+	if !p.open {
+		return nil
+	}
+	p.open = false
+	end := "\n"
+	if comma {
+		end = ",\n"
+	}
+	if _, err := io.WriteString(p.w, end); err != nil {
+		return writeErr(err)
+	}
+	return nil
+}
+
+// noteOmitted writes the comment noting the entries omitted, if any.
+func (p *Printer) noteOmitted() error {
+	if p.omitted == 0 {
+		return nil
+	}
+	noun := "entries"
+	if p.omitted == 1 {
+		noun = "entry"
+	}
+	if _, err := fmt.Fprintf(p.w, "%s// ... %d %s omitted\n", p.indent(), p.omitted, noun); err != nil {
+		return writeErr(err)
+	}
+	p.omitted = 0
+	return nil
+}
+
+func (p *Printer) indent() string {
+	if p.Indent == "" {
+		return "\t"
+	}
+	return p.Indent
+}
+
+func (p *Printer) separators() Separators {
+	if p.Separators != nil {
+		return *p.Separators
+	}
+	return p.seps
+}
+
+// untyped returns the value line v without its type, e.g. 42 for
+// int(42), or as it is, if it is not a conversion to one, or the value
+// has no plain literal form, as NaN, infinite, and negative zero floats,
+// and those encoded by their bits with math.Float64frombits, do not.
+func untyped(v []byte) []byte {
+	typ, lit, ok := bytes.Cut(v, []byte("("))
+	if !ok || !bytes.HasSuffix(lit, []byte(")")) || bytes.ContainsRune(typ, '.') {
+		return v
+	}
+	lit = lit[:len(lit)-1]
+	if t := string(typ); t == "float32" || t == "float64" {
+		f, err := strconv.ParseFloat(string(lit), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) ||
+			f == 0 && math.Signbit(f) {
+			return v
+		}
+	}
+	return lit
+}
+
+func writeErr(err error) error {
+	if err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package format_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+	. "github.com/antichris/go-fuzzdump/format"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrinter(t *testing.T) {
+	type bs = []byte
+	tests := map[string]struct {
+		entries []corpus.Entry
+		labels  bool
+		framing Framing
+		want    string
+	}{"empty": {
+		want: "{\n}\n",
+	}, "single": {
+		entries: []corpus.Entry{{bs("int(1)")}, {bs("int(2)")}},
+		want:    "{\n\tint(1),\n\tint(2),\n}\n",
+	}, "single labeled": {
+		entries: []corpus.Entry{{bs("int(1)")}},
+		labels:  true,
+		want:    "{\n\tint(1),\n}\n",
+	}, "multi": {
+		entries: []corpus.Entry{
+			{bs("int(1)"), bs(`string("a")`)},
+			{bs("int(2)"), bs(`string("b")`)},
+		},
+		want: "{{\n\tint(1),\n\tstring(\"a\"),\n}, {\n" +
+			"\tint(2),\n\tstring(\"b\"),\n}}\n",
+	}, "multi labeled": {
+		entries: []corpus.Entry{{bs("int(1)"), bs(`string("a")`)}},
+		labels:  true,
+		want:    "{{\n\t/* arg0 */ int(1),\n\t/* arg1 */ string(\"a\"),\n}}\n",
+	}, "empty framed": {
+		framing: LengthPrefixed,
+	}, "single length-prefixed": {
+		entries: []corpus.Entry{{bs("int(2)")}, {bs("int(13)")}},
+		framing: LengthPrefixed,
+		want:    "13\n{\n\tint(2),\n}\n14\n{\n\tint(13),\n}\n",
+	}, "multi record-separated labeled": {
+		entries: []corpus.Entry{
+			{bs("int(1)"), bs(`string("a")`)},
+			{bs("int(2)"), bs(`string("b")`)},
+		},
+		labels:  true,
+		framing: RecordSeparated,
+		want: "\x1e{{\n\t/* arg0 */ int(1),\n\t/* arg1 */ string(\"a\"),\n}}\n" +
+			"\x1e{{\n\t/* arg0 */ int(2),\n\t/* arg1 */ string(\"b\"),\n}}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			argCount := 1
+			if len(tt.entries) > 0 {
+				argCount = len(tt.entries[0])
+			}
+			w := &strings.Builder{}
+			p := NewPrinter(w, argCount)
+			p.ArgLabels = tt.labels
+			p.Framing = tt.framing
+			req := require.New(t)
+			req.NoError(p.Begin())
+			for _, e := range tt.entries {
+				req.NoError(p.Entry(e))
+			}
+			req.NoError(p.End())
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
+
+func TestPrinter_Omit(t *testing.T) {
+	e := corpus.Entry{[]byte("int(1)"), []byte(`string("a")`)}
+	w := &strings.Builder{}
+	p := NewPrinter(w, len(e))
+	req := require.New(t)
+	req.NoError(p.Begin())
+	req.NoError(p.Entry(e))
+	p.Omit(40)
+	p.Omit(2)
+	req.NoError(p.Entry(e))
+	req.NoError(p.End())
+	req.Equal("{{\n\tint(1),\n\tstring(\"a\"),\n}, {\n"+
+		"\t// ... 42 entries omitted\n\tint(1),\n\tstring(\"a\"),\n}}\n", w.String())
+}
+
+func TestPrinter_style(t *testing.T) {
+	type bs = []byte
+	single := []corpus.Entry{{bs("int(1)")}, {bs("int(2)")}}
+	multi := []corpus.Entry{
+		{bs("int(1)"), bs(`string("a")`)},
+		{bs("int(2)"), bs(`[]byte("b")`)},
+	}
+	tests := map[string]struct {
+		entries []corpus.Entry
+		style   func(p *Printer)
+		omit    int
+		want    string
+	}{"indent": {
+		entries: multi,
+		style:   func(p *Printer) { p.Indent = "  " },
+		want: "{{\n  int(1),\n  string(\"a\"),\n}, {\n" +
+			"  int(2),\n  []byte(\"b\"),\n}}\n",
+	}, "separators": {
+		entries: single,
+		style: func(p *Printer) {
+			p.Separators = &Separators{"[]int{", "", "}"}
+		},
+		want: "[]int{\n\tint(1),\n\tint(2),\n}\n",
+	}, "single without trailing commas": {
+		entries: single,
+		style:   func(p *Printer) { p.OmitTrailingCommas = true },
+		want:    "{\n\tint(1),\n\tint(2)\n}\n",
+	}, "multi without trailing commas": {
+		entries: multi,
+		style:   func(p *Printer) { p.OmitTrailingCommas = true },
+		want: "{{\n\tint(1),\n\tstring(\"a\")\n}, {\n" +
+			"\tint(2),\n\t[]byte(\"b\")\n}}\n",
+	}, "without type names": {
+		entries: multi,
+		style:   func(p *Printer) { p.OmitTypeNames = true },
+		want:    "{{\n\t1,\n\t\"a\",\n}, {\n\t2,\n\t\"b\",\n}}\n",
+	}, "without type names of literals": {
+		entries: []corpus.Entry{{
+			[]byte("float64(1.5)"),
+			[]byte("float32(0)"),
+			[]byte("float64(NaN)"),
+			[]byte("float64(+Inf)"),
+			[]byte("float32(-Inf)"),
+			[]byte("float64(-0)"),
+			[]byte("math.Float64frombits(0x7ff8000000000002)"),
+			[]byte("math.Float32frombits(0x7fc00001)"),
+		}},
+		style: func(p *Printer) { p.OmitTypeNames = true },
+		want: "{{\n\t1.5,\n\t0,\n\tfloat64(NaN),\n\tfloat64(+Inf),\n" +
+			"\tfloat32(-Inf),\n\tfloat64(-0),\n" +
+			"\tmath.Float64frombits(0x7ff8000000000002),\n" +
+			"\tmath.Float32frombits(0x7fc00001),\n}}\n",
+	}, "omitted at end": {
+		entries: single,
+		style: func(p *Printer) {
+			p.Indent, p.OmitTrailingCommas = " ", true
+			p.Comment("first")
+		},
+		omit: 3,
+		want: "{\n // first\n int(1),\n int(2)\n // ... 3 entries omitted\n}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			p := NewPrinter(w, len(tt.entries[0]))
+			tt.style(p)
+			req := require.New(t)
+			req.NoError(p.Begin())
+			for _, e := range tt.entries {
+				req.NoError(p.Entry(e))
+			}
+			p.Omit(tt.omit)
+			req.NoError(p.End())
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
+
+func TestPrinter_Comment(t *testing.T) {
+	e := corpus.Entry{[]byte("int(1)")}
+	w := &strings.Builder{}
+	p := NewPrinter(w, len(e))
+	req := require.New(t)
+	req.NoError(p.Begin())
+	p.Comment("from foo\nat noon")
+	req.NoError(p.Entry(e))
+	req.NoError(p.Entry(e))
+	p.Omit(1)
+	p.Comment("from bar")
+	req.NoError(p.Entry(e))
+	p.Comment("replaced")
+	p.Comment("")
+	req.NoError(p.Entry(e))
+	req.NoError(p.End())
+	req.Equal("{\n\t// from foo\n\t// at noon\n\tint(1),\n\tint(1),\n"+
+		"\t// ... 1 entry omitted\n\t// from bar\n\tint(1),\n\tint(1),\n}\n", w.String())
+
+	w.Reset()
+	p.Framing = LengthPrefixed
+	p.Comment("from foo")
+	req.NoError(p.Entry(e))
+	req.NoError(p.Entry(e))
+	req.Equal("26\n{\n\t// from foo\n\tint(1),\n}\n13\n{\n\tint(1),\n}\n", w.String())
+}
+
+func TestPrinter_writeErrors(t *testing.T) {
+	e := corpus.Entry{[]byte("int(1)"), []byte("int(2)")}
+	tests := map[string]func(p *Printer) error{
+		"Begin": func(p *Printer) error { return p.Begin() },
+		"Entry": func(p *Printer) error { return p.Entry(e) },
+		"second Entry": func(p *Printer) error {
+			p.Entry(e)
+			return p.Entry(e)
+		},
+		"labeled Entry": func(p *Printer) error {
+			p.ArgLabels = true
+			return p.Entry(e)
+		},
+		"End": func(p *Printer) error { return p.End() },
+		"omitted Entry": func(p *Printer) error {
+			p.Omit(1)
+			return p.Entry(e)
+		},
+		"commented Entry": func(p *Printer) error {
+			p.Comment("foo")
+			return p.Entry(e)
+		},
+		"length-prefixed Entry": func(p *Printer) error {
+			p.Framing = LengthPrefixed
+			return p.Entry(e)
+		},
+		"record-separated Entry": func(p *Printer) error {
+			p.Framing = RecordSeparated
+			return p.Entry(e)
+		},
+	}
+	for n, fn := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := fn(NewPrinter(errWriter{}, len(e)))
+			req := require.New(t)
+			req.ErrorIs(err, errSnap)
+			req.EqualError(err, "writing output: snap")
+		})
+	}
+}
+
+var errSnap = errors.New("snap")
+
+// errWriter returns errSnap on all Write calls.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errSnap }
+
+func TestPrinter_Bytes(t *testing.T) {
+	type bs = []byte
+	entries := []corpus.Entry{
+		{bs(`[]byte("\x00\x01hi")`), bs(`string("a")`)},
+		{bs(`[]byte("")`), bs(`string("b")`)},
+		{bs(`[]byte("x`), bs(`string("c")`)}, // Cannot be decoded.
+	}
+	tests := map[string]struct {
+		bytes BytesFormat
+		want  string
+	}{"raw": {
+		bytes: BytesRaw,
+		want: "{{\n\t[]byte(\"\\x00\\x01hi\"),\n\tstring(\"a\"),\n}, {\n" +
+			"\t[]byte(\"\"),\n\tstring(\"b\"),\n}, {\n" +
+			"\t[]byte(\"x,\n\tstring(\"c\"),\n}}\n",
+	}, "hex": {
+		bytes: BytesHex,
+		want: "{{\n\t[]byte{0x00, 0x01, 0x68, 0x69},\n\tstring(\"a\"),\n}, {\n" +
+			"\t[]byte{},\n\tstring(\"b\"),\n}, {\n" +
+			"\t[]byte(\"x,\n\tstring(\"c\"),\n}}\n",
+	}, "hex dump": {
+		bytes: BytesHexDump,
+		want: "{{\n\t// 00000000  00 01 68 69" + strings.Repeat(" ", 39) + "|..hi|\n" +
+			"\t[]byte(/* 4 bytes */),\n\tstring(\"a\"),\n}, {\n" +
+			"\t[]byte(/* 0 bytes */),\n\tstring(\"b\"),\n}, {\n" +
+			"\t[]byte(\"x,\n\tstring(\"c\"),\n}}\n",
+	}, "base64": {
+		bytes: BytesBase64,
+		want: "{{\n\tbase64(\"AAFoaQ==\"),\n\tstring(\"a\"),\n}, {\n" +
+			"\tbase64(\"\"),\n\tstring(\"b\"),\n}, {\n" +
+			"\t[]byte(\"x,\n\tstring(\"c\"),\n}}\n",
+	}, "summary": {
+		bytes: BytesSummary,
+		want: "{{\n\t[]byte(/* 4 bytes */),\n\tstring(\"a\"),\n}, {\n" +
+			"\t[]byte(/* 0 bytes */),\n\tstring(\"b\"),\n}, {\n" +
+			"\t[]byte(\"x,\n\tstring(\"c\"),\n}}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			p := NewPrinter(w, 2)
+			p.Bytes = tt.bytes
+			req := require.New(t)
+			req.NoError(p.Begin())
+			for _, e := range entries {
+				req.NoError(p.Entry(e))
+			}
+			req.NoError(p.End())
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
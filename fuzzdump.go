@@ -24,15 +24,27 @@
 //		string("qux"),
 //		// ... etc.
 //	}}
+//
+// This package, as well as [corpus] and [format], only ever reads a
+// corpus through the [fs.FS] interfaces, and writes nothing but the dump
+// to the given [io.Writer], so it is safe to point at a production
+// corpus store. None of them imports package os, or any other that
+// could be used to write files, as their tests make sure.
 package fuzzdump
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
 )
 
 // DumpDir writes the entries from a fuzz test corpus directory to w.
@@ -41,7 +53,8 @@ import (
 // number of fuzz arguments all entries should provide, and, consequently,
 // whether to format the output as a single or multiple argument corpus.
 //
-// If the directory is empty, it returns [ErrEmptyCorpus].
+// If the directory is empty, it returns [ErrEmptyCorpus], unless
+// [WithAllowEmpty] is given.
 //
 // An entry with a different number of arguments than initially detected
 // is not dumped, but reported with an [ErrInconsistentArgCount] in
@@ -61,38 +74,239 @@ import (
 // wrapped by a [fmt.Errorf].
 //
 // Do use [errors.Is] when checking the returned errors.
-func DumpDir(w io.Writer, fsys fs.FS, dir string) (err error) {
+//
+// The output can be adjusted by passing opts, such as [WithCanonical].
+func DumpDir(w io.Writer, fsys fs.FS, dir string, opts ...Option) (err error) {
+	o := newOptions(opts)
+	return dump(w, fsys, dir, o, o.newPrinter)
+}
+
+// newPrinter returns a [format.Printer] that writes to w the entries of
+// argCount arguments each, as o sets.
+func (o options) newPrinter(w io.Writer, argCount int) entryPrinter {
+	p := format.NewPrinter(w, argCount)
+	p.ArgLabels = o.argLabels
+	p.Framing = o.framing
+	o.styled(p)
+	return p
+}
+
+// dump implements [DumpDir] with the given options o, rendering the
+// entries with a printer that newPrinter returns.
+func dump(
+	w io.Writer, fsys fs.FS, dir string, o options, newPrinter printerFactory,
+) error {
+	return dumpFrom(w, dirSource(fsys, dir), o, newPrinter)
+}
+
+// dumpFrom writes the dump of the entries from src to w, see [dump].
+func dumpFrom(
+	w io.Writer, src source, o options, newPrinter printerFactory,
+) (err error) {
+	o.retain = o.retain || o.sorted() || o.atomic || o.strict
+	if !o.atomic && !o.strict {
+		return dumpEntries(w, src, o, newPrinter)
+	}
+	b := &bytes.Buffer{}
+	if err = dumpEntries(b, src, o, newPrinter); err != nil && o.strict {
+		return
+	}
+	var errs CorpusErrors
+	if e := errs.Capture(err); e != nil {
+		return e
+	}
+	if _, e := b.WriteTo(w); e != nil {
+		return writeErr(e)
+	}
+	return
+}
+
+// dumpEntries writes the dump of the entries from src to w, without
+// regard to whether it is atomic or strict, see [dump].
+func dumpEntries(
+	w io.Writer, src source, o options, newPrinter printerFactory,
+) (err error) {
+	var (
+		errs    CorpusErrors
+		p       entryPrinter
+		entries []namedEntry
+	)
+	begin := func(argCount int) error {
+		p = newPrinter(w, argCount)
+		return p.Begin()
+	}
+	printEntry := func(name string, lines corpus.Entry) error {
+		var notes []string
+		if o.names {
+			notes = append(notes, name)
+		}
+		if o.comment != nil {
+			if c := o.comment(name); c != "" {
+				notes = append(notes, c)
+			}
+		}
+		if o.sizes {
+			notes = append(notes, sizeNote(lines))
+		}
+		if o.find != nil {
+			notes = append(notes, findNote(lines, o.find))
+		}
+		p.Comment(strings.Join(notes, "\n"))
+		return p.Entry(lines)
+	}
+	var skipped int
+	if max := o.style.MaxEntries; max > 0 {
+		next, n := printEntry, 0
+		printEntry = func(name string, lines corpus.Entry) error {
+			if n++; n > max {
+				skipped++
+				return nil
+			}
+			return next(name, lines)
+		}
+	}
+	if off := o.style.Offset; off > 0 {
+		next, n := printEntry, 0
+		printEntry = func(name string, lines corpus.Entry) error {
+			if n++; n > off {
+				return next(name, lines)
+			}
+			p.Omit(1)
+			return nil
+		}
+	}
+	var pv *preview
+	if o.preview > 0 {
+		pv = &preview{n: o.preview, emit: printEntry}
+		printEntry = pv.add
+	}
+	emit := printEntry
+	if o.sorted() {
+		// Entries have to be sorted before any of them can be printed.
+		emit = func(name string, lines corpus.Entry) error {
+			entries = append(entries, namedEntry{name, lines})
+			return nil
+		}
+	}
+	err = src(o, begin, emit)
+	if e := errs.Capture(err); e != nil {
+		return e
+	}
+	o.sortEntries(entries)
+	for _, v := range entries {
+		if err := printEntry(v.name, v.lines); err != nil {
+			return err
+		}
+	}
+	if pv != nil {
+		if err := pv.flush(p); err != nil {
+			return err
+		}
+	}
+	if skipped > 0 {
+		p.Omit(skipped)
+	}
+	if err := p.End(); err != nil {
+		return err
+	}
+
+	return errs.AsError()
+}
+
+// An entryPrinter renders the entries of a dump, as [format.Printer]
+// does.
+type entryPrinter interface {
+	Begin() error
+	Entry(e corpus.Entry) error
+	Omit(n int)
+	Comment(text string)
+	End() error
+}
+
+// A printerFactory returns an entryPrinter that writes to w the entries
+// of argCount arguments each.
+type printerFactory func(w io.Writer, argCount int) entryPrinter
+
+// A source passes the entries of a corpus to emit, as [readDir] does
+// those of a directory, calling begin with their argument count first.
+type source func(o options, begin func(argCount int) error, emit emitter) error
+
+// dirSource returns the source of the entries of dir in fsys.
+func dirSource(fsys fs.FS, dir string) source {
+	return func(o options, begin func(argCount int) error, emit emitter) error {
+		return readDir(fsys, dir, o, begin, emit)
+	}
+}
+
+// An emitter is passed the lines of a valid corpus entry, along with the
+// name of its file.
+type emitter func(name string, lines corpus.Entry) error
+
+// A namedEntry holds the lines of a corpus entry, along with the name of
+// its file.
+type namedEntry struct {
+	name  string
+	lines corpus.Entry
+}
+
+// readDir reads the fuzz test corpus entries from dir in fsys, as
+// appropriate for o, and passes the lines of every valid one that o
+// does not filter out to emit, reporting the progress, if o says so.
+//
+// Before any lines are emitted, the number of arguments the entries
+// are expected to have is passed to begin. It is determined from the
+// first valid entry. If o allows an empty corpus, and dir is empty or
+// does not exist, begin is passed zero and nothing is emitted.
+func readDir(
+	fsys fs.FS,
+	dir string,
+	o options,
+	begin func(argCount int) error,
+	emit emitter,
+) (err error) {
 	var errs CorpusErrors
+	prog := newProgress(o, dir)
+	if prog != nil {
+		defer func() { prog.finish(err) }()
+	}
+	begin, emit = prog.begin(begin), o.filtered(prog.emit(o.capped(emit)))
 
 	files, err := corpusFiles(fsys, dir)
 	if err != nil {
+		if o.allowEmpty &&
+			(err == ErrEmptyCorpus || errors.Is(err, fs.ErrNotExist)) {
+			prog.start(0)
+			return begin(0)
+		}
 		return err
 	}
-	lines, files, err := firstValidFileLines(fsys, dir, files)
+	if o.listed != nil {
+		o.listed(files)
+	}
+	if err = o.sortFiles(files); err != nil {
+		return err
+	}
+	prog.start(len(files))
+	p := prefetch(o.context(), fsys, dir, files, o.lineReader(), o.jobs, o.minEntrySize())
+	defer p.stop()
+	read := prog.read(p.read)
+	lines, files, err := firstValidFileLines(fsys, dir, files, read)
 	if e := errs.Capture(err); e != nil {
 		return e
 	}
 
-	seps := sigleArgSep
 	argCount := len(lines)
-	if argCount > 1 {
-		seps = multiArgSep
-	}
-
-	if _, err := fmt.Fprintln(w, seps.Pre); err != nil {
-		return writeErr(err)
+	if err := begin(argCount); err != nil {
+		return err
 	}
-	if err := dumpLines(w, lines); err != nil {
+	if err := emit(files[0].Name(), lines); err != nil {
 		return err
 	}
-	// Since the above already dumped the first file, we skip that one.
-	err = dumpFiles(w, fsys, dir, files[1:], argCount)
+	// Since the above already emitted the first file, we skip that one.
+	err = readFiles(fsys, dir, files[1:], argCount, read, emit)
 	if e := errs.Capture(err); e != nil {
 		return e
 	}
-	if _, err := fmt.Fprintln(w, seps.Post); err != nil {
-		return writeErr(err)
-	}
 
 	return errs.AsError()
 }
@@ -113,20 +327,23 @@ func corpusFiles(fsys fs.FS, dir string) (files []fs.DirEntry, err error) {
 // firstValidFileLines returns the lines of the first valid fuzz corpus
 // file and a subslice of files starting at that file.
 func firstValidFileLines(
-	fsys fs.FS, dir string, allFiles []fs.DirEntry,
-) (lines [][]byte, files []fs.DirEntry, err error) {
+	fsys fs.FS, dir string, allFiles []fs.DirEntry, read lineReader,
+) (lines corpus.Entry, files []fs.DirEntry, err error) {
 	var errs CorpusErrors
 	i := 0
 	l := len(allFiles)
 	for ; i < l; i++ {
 		name := allFiles[i].Name()
-		lines, err = readLines(fsys, path.Join(dir, name))
+		lines, err = read(fsys, path.Join(dir, name))
 		if err == nil {
 			break // The first valid corpus file has been found.
 		}
 		if err = errs.Capture(readErr(err, name)); err != nil {
 			return
 		}
+		if lines != nil {
+			break // Usable, despite the error.
+		}
 	}
 	if i == l {
 		err = errs.Capture(ErrEmptyCorpus)
@@ -137,62 +354,91 @@ func firstValidFileLines(
 	return
 }
 
-type separators struct{ Pre, In, Post string }
-
-var (
-	sigleArgSep = separators{Pre: "{", Post: "}"}
-	multiArgSep = separators{"{{", "}, {", "}}"}
-)
-
-// dumpLines to w.
-func dumpLines(w io.Writer, lines [][]byte) error {
-	for _, v := range lines {
-		if _, err := fmt.Fprintf(w, "\t%s,\n", v); err != nil {
-			return writeErr(err)
-		}
-	}
-	return nil
-}
-
-// dumpFiles from the given dir in fsys to w.
+// readFiles from the given dir in fsys, passing the lines of every valid
+// one to emit.
 // In order to reduce complexity and provide more concise output, the
 // expected number of fuzz arguments per corpus entry must be determined
 // beforehand and passed as the value for argCount.
-func dumpFiles(
-	w io.Writer,
+func readFiles(
 	fsys fs.FS,
 	dir string,
 	files []fs.DirEntry,
 	argCount int,
+	read lineReader,
+	emit emitter,
 ) error {
 	var errs CorpusErrors
-	multiArg := argCount > 1
 	for _, f := range files {
 		name := f.Name()
-		lines, err := readLines(fsys, path.Join(dir, name))
+		lines, err := read(fsys, path.Join(dir, name))
 		if err != nil {
 			if e := errs.Capture(readErr(err, name)); e != nil {
 				return e
 			}
-			continue // Move right on to the next file.
+			if lines == nil {
+				continue // Move right on to the next file.
+			}
 		}
 		if l := len(lines); l != argCount {
-			errs.append(readErr(fmt.Errorf("%w: want %d, got %d",
-				ErrInconsistentArgCount, argCount, l), name))
+			errs.append(readErr(argCountErr(argCount, l), name))
 			continue // Skip this file.
 		}
-		if multiArg {
-			if _, err := fmt.Fprintln(w, multiArgSep.In); err != nil {
-				return writeErr(err)
-			}
-		}
-		if err := dumpLines(w, lines); err != nil {
+		if err := emit(name, lines); err != nil {
 			return err
 		}
 	}
 	return errs.AsError()
 }
 
+// argCountErr returns the [ErrInconsistentArgCount] reporting an entry
+// of got arguments, where want were expected.
+func argCountErr(want, got int) error {
+	return fmt.Errorf("%w: want %d, got %d", ErrInconsistentArgCount, want, got)
+}
+
+// sortEntries by their contents, or, if o says so, by their hashes, in
+// the direction that o sets.
+func (o options) sortEntries(entries []namedEntry) {
+	byHash := !o.canonical && o.style.Sort == SortByHash
+	keys := make([][]byte, len(entries))
+	for i, v := range entries {
+		keys[i] = bytes.Join(v.lines, []byte("\n"))
+		if byHash {
+			h := entryHash(v.lines)
+			keys[i] = h[:]
+		}
+	}
+	sort.Stable(byKey{entries, keys, o.style.Descending})
+}
+
+// entryHash returns the SHA-256 hash of the lines of a valid entry, as
+// encoded in a version 1 entry file.
+func entryHash(lines corpus.Entry) [sha256.Size]byte {
+	// The lines of a valid entry can always be marshaled.
+	data, _ := corpus.Marshal(lines)
+	return sha256.Sum256(data)
+}
+
+// byKey sorts entries by the respective keys, in descending order, if
+// desc is set.
+type byKey struct {
+	entries []namedEntry
+	keys    [][]byte
+	desc    bool
+}
+
+func (s byKey) Len() int { return len(s.keys) }
+func (s byKey) Less(i, j int) bool {
+	if s.desc {
+		return bytes.Compare(s.keys[i], s.keys[j]) > 0
+	}
+	return bytes.Compare(s.keys[i], s.keys[j]) < 0
+}
+func (s byKey) Swap(i, j int) {
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
 // getFiles returns those entries from dir in fsys that are regular
 // files.
 func getFiles(fsys fs.FS, dir string) (files []fs.DirEntry, err error) {
@@ -209,37 +455,151 @@ func getFiles(fsys fs.FS, dir string) (files []fs.DirEntry, err error) {
 	return
 }
 
-// readLines from file with the given name in fsys and return as a slice
-// of byte slices.
-func readLines(fsys fs.FS, name string) (lines [][]byte, err error) {
-	b, err := fs.ReadFile(fsys, name)
+// minEntrySize returns the size of the smallest file that can hold more
+// than a version header line accepted by o, and so a corpus entry.
+func (o options) minEntrySize() int64 {
+	versions := o.versions
+	if versions == nil {
+		versions = []string{corpus.Version1}
+	}
+	if o.assumeV1 {
+		// Even a lone value may be salvaged.
+		return 1
+	}
+	min := int64(-1)
+	for _, v := range versions {
+		if n := int64(len(v+"\n") + 1); min < 0 || n < min {
+			min = n
+		}
+	}
+	return min
+}
+
+// checkSize returns [ErrShortEntry] if the file f is smaller than min
+// and so too small to hold a corpus entry, so that it need not be read
+// at all.
+func checkSize(f fs.DirEntry, min int64) error {
+	fi, err := f.Info()
 	if err != nil {
-		return
+		// Left for reading the file to report.
+		return nil
 	}
+	if n := fi.Size(); n < min {
+		return fmt.Errorf("%w: %d bytes", ErrShortEntry, n)
+	}
+	return nil
+}
+
+// A lineReader reads the value lines of a corpus entry file.
+//
+// It may return the lines along with a validation error, if the entry
+// can be used, but the error still has to be reported.
+type lineReader func(fsys fs.FS, name string) (lines corpus.Entry, err error)
+
+// lineReader returns the function to read corpus entry files with, as
+// appropriate for o.
+func (o options) lineReader() lineReader {
+	read := readLines
+	if o.versions != nil {
+		read = versionLineReader(o.versions)
+	}
+	if o.assumeV1 {
+		read = headerlessLineReader(read)
+	}
+	if o.recover {
+		read = recoveringLineReader(read)
+	}
+	switch {
+	case o.canonical || o.normalize:
+		read = normalizingLineReader(read)
+	case o.decode || len(o.match) > 0:
+		read = valueLineReader(read)
+	}
+	if o.inspect != nil {
+		read = inspectingLineReader(read, o.inspect)
+	}
+	if o.hashSalt != nil {
+		read = hashingLineReader(read, o.hashSalt)
+	}
+	return read
+}
 
-	s := bytes.Split(b, []byte("\n"))
-	if len(s) < 2 {
-		// Not enough lines, so no point checking the version.
-		err = ErrMalformedEntry
+// normalizingLineReader returns a lineReader that reads the value lines
+// of a corpus entry file with read, and normalizes them.
+func normalizingLineReader(read lineReader) lineReader {
+	return func(fsys fs.FS, name string) (lines corpus.Entry, err error) {
+		if lines, err = read(fsys, name); lines == nil {
+			return
+		}
+		var nErr error
+		if lines, nErr = lines.Normalize(); nErr != nil {
+			return nil, nErr
+		}
 		return
 	}
-	if v := strings.TrimSuffix(string(s[0]), "\r"); v != encVersion1 {
-		err = fmt.Errorf("%w: %q", ErrUnsupportedVersion, v)
+}
+
+// valueLineReader returns a lineReader that reads the value lines of a
+// corpus entry file with read, and makes sure they can be decoded.
+func valueLineReader(read lineReader) lineReader {
+	return func(fsys fs.FS, name string) (lines corpus.Entry, err error) {
+		if lines, err = read(fsys, name); lines == nil {
+			return
+		}
+		if _, vErr := lines.Values(); vErr != nil {
+			return nil, vErr
+		}
 		return
 	}
-	for _, v := range s[1:] {
-		line := bytes.TrimSpace(v)
-		if len(line) == 0 {
-			continue
+}
+
+// headerlessLineReader returns a lineReader that reads corpus entry
+// files with read, but takes a file that lacks a version header for a
+// version 1 one, if all its lines are values that can be decoded. The
+// lines of such a file are returned along with [ErrMissingVersion].
+func headerlessLineReader(read lineReader) lineReader {
+	return func(fsys fs.FS, name string) (corpus.Entry, error) {
+		lines, err := read(fsys, name)
+		if !errors.Is(err, ErrUnsupportedVersion) &&
+			!errors.Is(err, ErrMalformedEntry) {
+			return lines, err
+		}
+		b, rErr := fs.ReadFile(fsys, name)
+		if rErr != nil {
+			return nil, rErr
+		}
+		var e corpus.Entry
+		for _, l := range bytes.Split(b, []byte("\n")) {
+			if l = bytes.TrimSpace(l); len(l) == 0 {
+				continue
+			}
+			if _, vErr := corpus.DecodeValue(l); vErr != nil {
+				// Not just values, so not salvageable.
+				return nil, err
+			}
+			e = append(e, l)
 		}
-		lines = append(lines, line)
+		if len(e) == 0 {
+			return nil, err
+		}
+		return e, fmt.Errorf("%w, assumed %q", ErrMissingVersion, corpus.Version1)
 	}
-	if len(lines) < 1 {
-		err = ErrMalformedEntry
-		return
+}
+
+// versionLineReader returns a lineReader that reads corpus entry files
+// with any of the given version headers.
+func versionLineReader(versions []string) lineReader {
+	return func(fsys fs.FS, name string) (corpus.Entry, error) {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		return corpus.UnmarshalVersions(b, versions...)
 	}
-	return
 }
 
-// encVersion1 is the first line of a file with version 1 encoding.
-const encVersion1 = "go test fuzz v1"
+// readLines from file with the given name in fsys and return them as
+// a corpus entry.
+func readLines(fsys fs.FS, name string) (corpus.Entry, error) {
+	return corpus.ReadFile(fsys, name)
+}
@@ -11,6 +11,8 @@ import (
 	"testing/fstest"
 
 	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/antichris/go-fuzzdump/format"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,11 +29,19 @@ func TestDumpDir(t *testing.T) {
 	uint(3),
 	uint(5),
 }` + LF
+		canonicalOut = `{{
+	string("bar"),
+	uint(13),
+}, {
+	string("foo"),
+	uint(8),
+}}` + LF
 	)
 	tests := map[string]struct {
 		dir          string
 		wErr         error
 		wErrContains string
+		opts         []Option
 		wOut         string
 	}{"absent": {
 		dir:  "foo",
@@ -64,6 +74,42 @@ func TestDumpDir(t *testing.T) {
 		wErr:         ErrInconsistentArgCount,
 		wErrContains: "want 2, got 1",
 		wOut:         multiOut,
+	}, "canonical": {
+		dir:  unsortedDir,
+		opts: []Option{WithCanonical()},
+		wOut: canonicalOut,
+	}, "arg labels": {
+		dir:  multiDir,
+		opts: []Option{WithArgLabels()},
+		wOut: `{{
+	/* arg0 */ string("foo"),
+	/* arg1 */ uint(8),
+}, {
+	/* arg0 */ string("bar"),
+	/* arg1 */ uint(13),
+}}` + LF,
+	}, "arg labels single arg": {
+		dir:  sigleDir,
+		opts: []Option{WithArgLabels()},
+		wOut: sigleOut,
+	}, "allow empty": {
+		dir:  emptyDir,
+		opts: []Option{WithAllowEmpty()},
+		wOut: "{\n}\n",
+	}, "allow empty absent": {
+		dir:  "foo",
+		opts: []Option{WithAllowEmpty()},
+		wOut: "{\n}\n",
+	}, "allow empty no valid files": {
+		dir:  badDir,
+		opts: []Option{WithAllowEmpty()},
+		wErr: ErrEmptyCorpus,
+	}, "canonical bad value": {
+		dir:          badValueDir,
+		opts:         []Option{WithCanonical()},
+		wErr:         ErrMalformedValue,
+		wErrContains: `"uint(-1)"`,
+		wOut:         sigleOut,
 	}}
 	for n, tt := range tests {
 		t.Run(n, func(t *testing.T) {
@@ -71,7 +117,7 @@ func TestDumpDir(t *testing.T) {
 			var err error
 			req := require.New(t)
 			req.NotPanics(func() {
-				err = DumpDir(w, fsys, tt.dir)
+				err = DumpDir(w, fsys, tt.dir, tt.opts...)
 			})
 			if tt.wErr != nil {
 				req.ErrorIs(err, tt.wErr)
@@ -94,9 +140,9 @@ func TestDumpDir_OutputErrors(t *testing.T) {
 	tests := []struct {
 		failOn string
 	}{
-		{failOn: XmultiArgSep.Pre},
-		{failOn: XmultiArgSep.In},
-		{failOn: XmultiArgSep.Post},
+		{failOn: "{{"},
+		{failOn: "}, {"},
+		{failOn: "}}"},
 		{failOn: "\tstring(\"foo\"),"},
 		{failOn: "\tstring(\"bar\"),"},
 	}
@@ -111,6 +157,234 @@ func TestDumpDir_OutputErrors(t *testing.T) {
 	}
 }
 
+func TestDumpDir_OutputErrors_labeled(t *testing.T) {
+	failOn := "\t/* arg1 */ uint(13),\n"
+	p := func(b []byte) bool { return string(b) == failOn }
+	w := PredicateErrWriter(io.Discard, errSnap, p)
+	err := DumpDir(w, fsys, multiDir, WithArgLabels())
+	require.ErrorIs(t, err, errSnap)
+}
+
+func TestDumpDir_atomic(t *testing.T) {
+	tests := map[string]struct {
+		fsys fs.FS
+		dir  string
+		w    io.Writer
+		wErr error
+		wOut string
+	}{"nominal": {
+		fsys: fsys,
+		dir:  sigleDir,
+		wOut: "{\n\tuint(3),\n\tuint(5),\n}\n",
+	}, "validation error": {
+		fsys: fsys,
+		dir:  badValueDir,
+		wOut: "{\n\tuint(3),\n\tuint(-1),\n\tuint(5),\n}\n",
+	}, "empty corpus": {
+		fsys: fsys,
+		dir:  emptyDir,
+		wErr: ErrEmptyCorpus,
+	}, "critical error": {
+		// The second file fails to be read.
+		fsys: failingFS{fsys, sigleDir + "/2"},
+		dir:  sigleDir,
+		wErr: errSnap,
+	}, "write error": {
+		fsys: fsys,
+		dir:  sigleDir,
+		w:    ErrWriter(errSnap),
+		wErr: errSnap,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			b := &strings.Builder{}
+			w := tt.w
+			if w == nil {
+				w = b
+			}
+			err := DumpDir(w, tt.fsys, tt.dir, WithAtomicOutput())
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, b.String())
+		})
+	}
+}
+
+func TestDumpDir_strict(t *testing.T) {
+	tests := map[string]struct {
+		dir  string
+		wErr error
+		wOut string
+	}{"nominal": {
+		dir:  sigleDir,
+		wOut: "{\n\tuint(3),\n\tuint(5),\n}\n",
+	}, "validation error": {
+		dir:  badValueDir,
+		wErr: ErrMalformedValue,
+	}, "empty corpus": {
+		dir:  emptyDir,
+		wErr: ErrEmptyCorpus,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			b := &strings.Builder{}
+			err := DumpDir(b, fsys, tt.dir, WithStrict())
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, b.String())
+		})
+	}
+}
+
+func TestDumpDir_framing(t *testing.T) {
+	w := &strings.Builder{}
+	err := DumpDir(w, fsys, sigleDir, WithFraming(format.LengthPrefixed))
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal("14\n{\n\tuint(3),\n}\n14\n{\n\tuint(5),\n}\n", w.String())
+}
+
+func TestDumpDir_acceptVersions(t *testing.T) {
+	const dir = "versions"
+	fsys := fstest.MapFS{
+		// Shorter than the version 1 header alone.
+		dir + "/1": &fstest.MapFile{Data: []byte("v1\nint(1)\n")},
+		dir + "/2": corpusFile("int(2)"),
+		dir + "/3": &fstest.MapFile{Data: []byte("other\nint(3)\n")},
+	}
+	tests := map[string]struct {
+		opts []Option
+		wErr error
+		wOut string
+	}{"default": {
+		// Too short for the version 1 header.
+		wErr: ErrShortEntry,
+		wOut: "{\n\tint(2),\n}\n",
+	}, "accepted": {
+		opts: []Option{WithAcceptVersions(corpus.Version1, "v1")},
+		wErr: ErrUnsupportedVersion,
+		wOut: "{\n\tint(1),\n\tint(2),\n}\n",
+	}, "accepted canonical": {
+		opts: []Option{WithAcceptVersions(corpus.Version1, "v1"), WithCanonical()},
+		wErr: ErrUnsupportedVersion,
+		wOut: "{\n\tint(1),\n\tint(2),\n}\n",
+	}, "accepted strict": {
+		opts: []Option{WithAcceptVersions("v1", "other"), WithStrict()},
+		wErr: ErrUnsupportedVersion,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			b := &strings.Builder{}
+			err := DumpDir(b, fsys, dir, tt.opts...)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.wOut, b.String())
+		})
+	}
+}
+
+func TestDumpDir_assumeVersion1(t *testing.T) {
+	const dir = "headerless"
+	fsys := fstest.MapFS{
+		dir + "/1": &fstest.MapFile{Data: []byte("int(0x1)")},
+		dir + "/2": corpusFile("int(2)"),
+		dir + "/3": &fstest.MapFile{Data: []byte("\nint(3)\n\nint(4)\n")},
+		dir + "/4": &fstest.MapFile{Data: []byte("foo\nint(5)\n")},
+	}
+	tests := map[string]struct {
+		opts  []Option
+		wErrs []error
+		wOut  string
+	}{"default": {
+		wErrs: []error{ErrShortEntry},
+		wOut:  "{\n\tint(2),\n}\n",
+	}, "salvaged": {
+		opts:  []Option{WithAssumeVersion1()},
+		wErrs: []error{ErrMissingVersion, ErrInconsistentArgCount, ErrUnsupportedVersion},
+		wOut:  "{\n\tint(0x1),\n\tint(2),\n}\n",
+	}, "salvaged canonical": {
+		opts:  []Option{WithAssumeVersion1(), WithCanonical()},
+		wErrs: []error{ErrMissingVersion, ErrInconsistentArgCount, ErrUnsupportedVersion},
+		wOut:  "{\n\tint(1),\n\tint(2),\n}\n",
+	}, "salvaged strict": {
+		opts:  []Option{WithAssumeVersion1(), WithStrict()},
+		wErrs: []error{ErrMissingVersion},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			b := &strings.Builder{}
+			err := DumpDir(b, fsys, dir, tt.opts...)
+			req := require.New(t)
+			for _, wErr := range tt.wErrs {
+				req.ErrorIs(err, wErr)
+			}
+			req.Equal(tt.wOut, b.String())
+		})
+	}
+}
+
+func TestDumpDir_entryComments(t *testing.T) {
+	const dir = "commented"
+	fsys := fstest.MapFS{
+		dir + "/a": corpusFile("int(3)"),
+		dir + "/b": corpusFile("int(1)"),
+		dir + "/c": corpusFile("int(2)"),
+		dir + "/d": corpusFile("int(4)"),
+	}
+	comment := func(name string) string {
+		if name == "c" {
+			return ""
+		}
+		return "from " + name
+	}
+	tests := map[string]struct {
+		opts []Option
+		wOut string
+	}{"default": {
+		wOut: "{\n\t// from a\n\tint(3),\n\t// from b\n\tint(1),\n\tint(2),\n\t// from d\n\tint(4),\n}\n",
+	}, "canonical": {
+		opts: []Option{WithCanonical()},
+		wOut: "{\n\t// from b\n\tint(1),\n\tint(2),\n\t// from a\n\tint(3),\n\t// from d\n\tint(4),\n}\n",
+	}, "preview": {
+		opts: []Option{WithPreview(1)},
+		wOut: "{\n\t// from a\n\tint(3),\n\t// ... 2 entries omitted\n\t// from d\n\tint(4),\n}\n",
+	}, "file names": {
+		opts: []Option{WithFileNames()},
+		wOut: "{\n\t// a\n\t// from a\n\tint(3),\n\t// b\n\t// from b\n\tint(1),\n" +
+			"\t// c\n\tint(2),\n\t// d\n\t// from d\n\tint(4),\n}\n",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			b := &strings.Builder{}
+			err := DumpDir(b, fsys, dir, append(tt.opts, WithEntryComments(comment))...)
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.wOut, b.String())
+		})
+	}
+}
+
+// failingFS fails to open the named file with errSnap.
+type failingFS struct {
+	fs.FS
+	name string
+}
+
+func (f failingFS) Open(name string) (fs.File, error) {
+	if name == f.name {
+		return nil, errSnap
+	}
+	return f.FS.Open(name)
+}
+
 func Test_corpusFiles(t *testing.T) {
 	t.Run("ErrEmptyCorpus", func(t *testing.T) {
 		want := ErrEmptyCorpus
@@ -124,27 +398,34 @@ func Test_firstValidFileLines(t *testing.T) {
 	t.Run("non-critical error", func(t *testing.T) {
 		want := ErrMalformedEntry
 		dir := badMultiDir
-		_, _, err := XfirstValidFileLines(fsys, dir, fsysFiles(t, dir))
+		files := fsysFiles(t, dir)
+		_, _, err := XfirstValidFileLines(fsys, dir, files, XreadLines)
 		require.ErrorIs(t, err, want)
 	})
 	t.Run("critical error", func(t *testing.T) {
 		checkErrNotExistPassedForFiles(t, func(
 			fsys fs.FS, dir string, files []fs.DirEntry,
 		) error {
-			_, _, err := XfirstValidFileLines(fsys, dir, files)
+			_, _, err := XfirstValidFileLines(fsys, dir, files, XreadLines)
 			return err
 		})
 	})
 }
 
-func Test_dumpFiles(t *testing.T) {
+func Test_readFiles(t *testing.T) {
 	t.Run("critical error", func(t *testing.T) {
 		checkErrNotExistPassedForFiles(t, func(
 			fsys fs.FS, dir string, files []fs.DirEntry,
 		) error {
-			return XdumpFiles(io.Discard, fsys, dir, files, 0)
+			return XreadFiles(fsys, dir, files, 0, XreadLines, nil)
 		})
 	})
+	t.Run("emit error", func(t *testing.T) {
+		dir := sigleDir
+		emit := func(string, corpus.Entry) error { return errSnap }
+		err := XreadFiles(fsys, dir, fsysFiles(t, dir), 1, XreadLines, emit)
+		require.ErrorIs(t, err, errSnap)
+	})
 }
 
 func Test_readLines(t *testing.T) {
@@ -174,7 +455,7 @@ func Test_readLines(t *testing.T) {
 	for n, tt := range tests {
 		t.Run(n, func(t *testing.T) {
 			wLines := bytes.Split([]byte(tt.wLines), []byte("\n"))
-			var gotLines [][]byte
+			var gotLines corpus.Entry
 			var gotErr error
 			req := require.New(t)
 			req.NotPanics(func() {
@@ -185,7 +466,7 @@ func Test_readLines(t *testing.T) {
 				return
 			}
 			req.NoError(gotErr)
-			req.Equal(wLines, gotLines)
+			req.Equal(corpus.Entry(wLines), gotLines)
 		})
 	}
 }
@@ -201,6 +482,9 @@ const (
 	multiDir    = "multi"
 	badMultiDir = "badMulti"
 
+	unsortedDir = "unsorted"
+	badValueDir = "badValue"
+
 	multiInSingleDir = "multi-in-single"
 	singleInMultiDir = "single-in-multi"
 
@@ -222,8 +506,8 @@ var fsys = func() fstest.MapFS {
 		emptyDir:    &fstest.MapFile{Mode: fs.ModeDir},
 		badFile:     &fstest.MapFile{},
 		badVerFile:  &fstest.MapFile{Data: []byte("foo" + LF)},
-		verOnlyFile: &fstest.MapFile{Data: []byte(XencVersion1)},
-		noArgsFile:  &fstest.MapFile{Data: []byte(XencVersion1 + LF)},
+		verOnlyFile: &fstest.MapFile{Data: []byte(corpus.Version1)},
+		noArgsFile:  &fstest.MapFile{Data: []byte(corpus.Version1 + LF)},
 
 		emptyArgsFile:      corpusFile(""),
 		sigleArgFile:       corpusFile(sigleData1),
@@ -235,6 +519,12 @@ var fsys = func() fstest.MapFS {
 		badMultiDir + "/3": corpusFile(multiData2),
 		badMultiDir + "/4": corpusFile(""),
 
+		unsortedDir + "/1": corpusFile("string( \"foo\" )\nuint(0x8)"),
+		unsortedDir + "/2": corpusFile(multiData2),
+		badValueDir + "/1": corpusFile(sigleData1),
+		badValueDir + "/2": corpusFile("uint(-1)"),
+		badValueDir + "/3": corpusFile(sigleData2),
+
 		multiInSingleDir + "/1": corpusFile(sigleData1),
 		multiInSingleDir + "/2": corpusFile(multiData1),
 		multiInSingleDir + "/3": corpusFile(sigleData2),
@@ -265,7 +555,23 @@ func fsysFiles(t *testing.T, dir string) (files []fs.DirEntry) {
 
 func corpusFile(contents string) *fstest.MapFile {
 	return &fstest.MapFile{Data: []byte(
-		XencVersion1 + LF +
+		corpus.Version1 + LF +
 			contents + LF,
 	)}
 }
+
+func TestDumpDir_normalizedValues(t *testing.T) {
+	const dir = "unnormal"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(0x2a)\nfloat64(1e0)\nstring(`raw`)"),
+		dir + "/2": corpusFile("int(-0b1)\nfloat64(.5)\nstring(\"\\x41\")"),
+	}
+	w := &strings.Builder{}
+	req := require.New(t)
+	req.NoError(DumpDir(w, fsys, dir, WithNormalizedValues()))
+	req.Equal("{{\n"+
+		"\tint(42),\n\tfloat64(1),\n\tstring(\"raw\"),\n"+
+		"}, {\n"+
+		"\tint(-1),\n\tfloat64(0.5),\n\tstring(\"A\"),\n"+
+		"}}\n", w.String())
+}
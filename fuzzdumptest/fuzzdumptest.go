@@ -0,0 +1,95 @@
+// Package fuzzdumptest provides utilities for testing fuzz test corpora.
+package fuzzdumptest
+
+import (
+	"bytes"
+	"flag"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/antichris/go-fuzzdump"
+)
+
+// Update makes [RequireCorpusEqual] write golden files instead of
+// comparing against them.
+//
+// It is set by the -fuzzdump.update test flag by default, but may be
+// pointed at a flag of one's own, e.g.:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	func init() { fuzzdumptest.Update = update }
+var Update = flag.Bool("fuzzdump.update", false,
+	"update golden fuzz test corpus dumps")
+
+// RequireCorpusEqual asserts that the canonical dump (see
+// [fuzzdump.WithCanonical]) of the corpus in dir of fsys equals the
+// contents of the golden file at goldenPath, and stops the test if it
+// does not, reporting the differences.
+//
+// This makes any change to a corpus visible in code review, as the
+// golden file has to be updated along with the corpus.
+//
+// When [Update] is true, the golden file is written instead.
+//
+// Any error from dumping the corpus stops the test.
+func RequireCorpusEqual(t testing.TB, fsys fs.FS, dir, goldenPath string) {
+	t.Helper()
+	b := &bytes.Buffer{}
+	if err := fuzzdump.DumpDir(b, fsys, dir, fuzzdump.WithCanonical()); err != nil {
+		t.Fatalf("dumping corpus %q: %s", dir, err)
+	}
+	if *Update {
+		if err := os.WriteFile(goldenPath, b.Bytes(), 0o644); err != nil {
+			t.Fatalf("updating golden file: %s", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -fuzzdump.update to create it): %s", err)
+	}
+	if got := b.String(); got != string(want) {
+		t.Fatalf("corpus %q differs from golden file %q:\n%s",
+			dir, goldenPath, diff(string(want), got))
+	}
+}
+
+// diff returns the lines of want and got that differ, those between the
+// lines they start and end with in common, prefixed with "-" and "+",
+// respectively, and those in common around them with " ", e.g.:
+//
+//	 {
+//	-	int(3),
+//	+	int(4),
+//	 	int(5),
+//	 }
+func diff(want, got string) string {
+	wl := strings.SplitAfter(want, "\n")
+	gl := strings.SplitAfter(got, "\n")
+	pre := 0
+	for pre < len(wl) && pre < len(gl) && wl[pre] == gl[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(wl)-pre && suf < len(gl)-pre &&
+		wl[len(wl)-1-suf] == gl[len(gl)-1-suf] {
+		suf++
+	}
+	b := &strings.Builder{}
+	lines := func(prefix string, ls []string) {
+		for _, l := range ls {
+			if l == "" {
+				continue
+			}
+			b.WriteString(prefix + strings.TrimSuffix(l, "\n") + "\n")
+		}
+	}
+	lines(" ", wl[:pre])
+	lines("-", wl[pre:len(wl)-suf])
+	lines("+", gl[pre:len(gl)-suf])
+	lines(" ", wl[len(wl)-suf:])
+	return b.String()
+}
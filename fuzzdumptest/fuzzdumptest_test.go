@@ -0,0 +1,120 @@
+package fuzzdumptest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump/fuzzdumptest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireCorpusEqual(t *testing.T) {
+	defer func(v *bool) { Update = v }(Update)
+	const (
+		dir  = "FuzzFoo"
+		want = "{\n\tint(3),\n\tint(5),\n}\n"
+	)
+	fsys := fstest.MapFS{
+		dir + "/a": {Data: []byte("go test fuzz v1\nint(5)\n")},
+		dir + "/b": {Data: []byte("go test fuzz v1\nint(0x3)\n")},
+	}
+	tests := map[string]struct {
+		golden  string // Initial golden file contents, absent if empty.
+		update  bool
+		dir     string
+		wFailed string
+		wGolden string
+	}{"equal": {
+		golden:  want,
+		dir:     dir,
+		wGolden: want,
+	}, "differs": {
+		golden:  "{\n}\n",
+		dir:     dir,
+		wFailed: "differs from golden file",
+		wGolden: "{\n}\n",
+	}, "differences": {
+		golden:  "{\n\tint(3),\n\tint(4),\n}\n",
+		dir:     dir,
+		wFailed: " {\n \tint(3),\n-\tint(4),\n+\tint(5),\n }\n",
+		wGolden: "{\n\tint(3),\n\tint(4),\n}\n",
+	}, "no golden file": {
+		dir:     dir,
+		wFailed: "-fuzzdump.update",
+	}, "bad corpus": {
+		golden:  want,
+		dir:     "absent",
+		wFailed: `dumping corpus "absent"`,
+		wGolden: want,
+	}, "update": {
+		golden:  "{\n}\n",
+		update:  true,
+		dir:     dir,
+		wGolden: want,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			golden := filepath.Join(t.TempDir(), "corpus.golden")
+			req := require.New(t)
+			if tt.golden != "" {
+				req.NoError(os.WriteFile(golden, []byte(tt.golden), 0o644))
+			}
+			Update = &tt.update
+
+			ft := &fakeT{TB: t}
+			runTest(ft, func() { RequireCorpusEqual(ft, fsys, tt.dir, golden) })
+
+			if tt.wFailed != "" {
+				req.Contains(ft.failure, tt.wFailed)
+			} else {
+				req.Empty(ft.failure)
+			}
+			if tt.wGolden == "" {
+				return
+			}
+			b, err := os.ReadFile(golden)
+			req.NoError(err)
+			req.Equal(tt.wGolden, string(b))
+		})
+	}
+	t.Run("bad golden path", func(t *testing.T) {
+		update := true
+		Update = &update
+		ft := &fakeT{TB: t}
+		golden := filepath.Join(t.TempDir(), "absent", "corpus.golden")
+		runTest(ft, func() { RequireCorpusEqual(ft, fsys, dir, golden) })
+		require.Contains(t, ft.failure, "updating golden file")
+	})
+}
+
+// fakeT records test failures instead of reporting them.
+type fakeT struct {
+	testing.TB
+	failure string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failure += fmt.Sprintf(format, args...)
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	t.FailNow()
+}
+
+func (t *fakeT) FailNow() { panic(t) }
+
+// runTest runs fn, recovering from a FailNow of t.
+func runTest(t *fakeT, fn func()) {
+	defer func() {
+		if r := recover(); r != nil && r != t {
+			panic(r)
+		}
+	}()
+	fn()
+}
@@ -2,7 +2,10 @@ module github.com/antichris/go-fuzzdump
 
 go 1.19
 
-require github.com/stretchr/testify v1.8.0
+require (
+	github.com/stretchr/testify v1.8.0
+	golang.org/x/sync v0.8.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
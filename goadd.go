@@ -0,0 +1,142 @@
+package fuzzdump
+
+import (
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// DumpDirGoAdd writes the entries from a fuzz test corpus directory to
+// w as Go source: a call of the Add method of f, a [testing.F], for
+// each, with the values as typed literals, e.g.:
+//
+//	f.Add(int(8), string("foo"))
+//	f.Add(int(13), string("bar"))
+//
+// The calls can be pasted into a fuzz test to seed it with the corpus.
+// With [WithReceiver], the Add method of another variable than f is
+// called.
+// The floats that Go has no literals for are written as calls of the
+// functions of package math that return them, e.g. math.Inf(1), so the
+// test may have to import it.
+//
+// Errors are reported as with [DumpDir], and so are opts applied, save
+// for those that concern the framing and the argument labels of the
+// dump format. Every value is decoded, and a value that cannot be is
+// reported as [ErrMalformedValue], and its entry is not dumped.
+func DumpDirGoAdd(w io.Writer, fsys fs.FS, dir string, opts ...Option) error {
+	o := newOptions(opts)
+	o.decode = true
+	recv := o.receiver
+	if recv == "" {
+		recv = "f"
+	}
+	return dump(w, fsys, dir, o, func(w io.Writer, _ int) entryPrinter {
+		return &goAddPrinter{w: w, recv: recv}
+	})
+}
+
+// WithReceiver makes [DumpDirGoAdd] call the Add method of the variable
+// with the given name instead of f, e.g., to paste the calls into a fuzz
+// test with its [testing.F] parameter named otherwise. The name is not
+// validated.
+func WithReceiver(name string) Option {
+	return func(o *options) { o.receiver = name }
+}
+
+// A goAddPrinter renders the entries of a dump as calls of Add, see
+// [DumpDirGoAdd].
+type goAddPrinter struct {
+	w       io.Writer
+	recv    string   // Name of the variable whose Add method is called.
+	omitted int      // Entries to be noted omitted before the next one.
+	comment []string // Lines of the comment on the next entry.
+}
+
+func (p *goAddPrinter) Begin() error { return nil }
+
+func (p *goAddPrinter) Entry(e corpus.Entry) error {
+	vals, err := e.Values()
+	if err != nil {
+		return err
+	}
+	args := make([]string, len(vals))
+	for i, v := range vals {
+		if args[i], err = goLiteral(v); err != nil {
+			return err
+		}
+	}
+	b := &strings.Builder{}
+	if p.omitted > 0 {
+		b.WriteString(omittedNote(p.omitted))
+		p.omitted = 0
+	}
+	for _, l := range p.comment {
+		b.WriteString("// " + l + "\n")
+	}
+	p.comment = nil
+	b.WriteString(p.recv + ".Add(" + strings.Join(args, ", ") + ")\n")
+	return p.print(b.String())
+}
+
+func (p *goAddPrinter) Omit(n int) { p.omitted += n }
+
+func (p *goAddPrinter) Comment(text string) {
+	p.comment = nil
+	if text != "" {
+		p.comment = strings.Split(text, "\n")
+	}
+}
+
+// End notes the entries omitted after the last one, if any: the calls
+// need no closing.
+func (p *goAddPrinter) End() error {
+	if p.omitted == 0 {
+		return nil
+	}
+	return p.print(omittedNote(p.omitted))
+}
+
+func (p *goAddPrinter) print(s string) error {
+	_, err := io.WriteString(p.w, s)
+	return writeErr(err)
+}
+
+// omittedNote returns the comment noting n entries omitted.
+func omittedNote(n int) string {
+	if n == 1 {
+		return "// ... 1 entry omitted\n"
+	}
+	return "// ... " + strconv.Itoa(n) + " entries omitted\n"
+}
+
+// goLiteral returns the Go expression of v: a typed literal, or, for a
+// float that Go has no literal for, a call of a function of package
+// math that returns it.
+func goLiteral(v corpus.Value) (string, error) {
+	b, err := corpus.EncodeValue(v)
+	if err != nil {
+		return "", err
+	}
+	s := string(b)
+	switch v.(type) {
+	case float32, float64:
+		typ, lit, _ := strings.Cut(strings.TrimSuffix(s, ")"), "(")
+		if f, ok := mathFloats[lit]; ok {
+			return typ + "(" + f + ")", nil
+		}
+	}
+	return s, nil
+}
+
+// mathFloats are the calls of the functions of package math that return
+// the floats Go has no literals for, by their corpus encoding.
+var mathFloats = map[string]string{
+	"+Inf": "math.Inf(1)",
+	"-Inf": "math.Inf(-1)",
+	"NaN":  "math.NaN()",
+	"-0":   "math.Copysign(0, -1)",
+}
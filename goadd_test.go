@@ -0,0 +1,97 @@
+package fuzzdump_test
+
+import (
+	"go/parser"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDirGoAdd(t *testing.T) {
+	const dir = "corpus"
+	tests := map[string]struct {
+		vals  []corpus.Value
+		wCall string
+	}{"ints": {
+		vals:  []corpus.Value{int(-1), int8(2), uint64(3), byte('A'), 'x', int32(-5)},
+		wCall: `f.Add(int(-1), int8(2), uint64(3), byte('A'), rune('x'), int32(-5))`,
+	}, "strings": {
+		vals:  []corpus.Value{"a\"b\n", []byte("\x00\xff"), true},
+		wCall: `f.Add(string("a\"b\n"), []byte("\x00\xff"), bool(true))`,
+	}, "floats": {
+		vals:  []corpus.Value{1.5, float32(-2), 1e100},
+		wCall: `f.Add(float64(1.5), float32(-2), float64(1e+100))`,
+	}, "special floats": {
+		vals: []corpus.Value{
+			math.Inf(1), float32(math.Inf(-1)), math.NaN(), math.Copysign(0, -1),
+			math.Float64frombits(0x7ff8000000000002),
+		},
+		wCall: `f.Add(float64(math.Inf(1)), float32(math.Inf(-1)), float64(math.NaN()), ` +
+			`float64(math.Copysign(0, -1)), math.Float64frombits(0x7ff8000000000002))`,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			e, err := corpus.NewEntry(tt.vals...)
+			req := require.New(t)
+			req.NoError(err)
+			data, err := corpus.Marshal(e)
+			req.NoError(err)
+			fsys := fstest.MapFS{dir + "/1": {Data: data}}
+			w := &strings.Builder{}
+			req.NoError(DumpDirGoAdd(w, fsys, dir))
+			req.Equal(tt.wCall+"\n", w.String())
+			_, err = parser.ParseExpr(tt.wCall)
+			req.NoError(err, "valid Go")
+		})
+	}
+}
+
+func TestDumpDirGoAdd_comments(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for _, n := range []string{"1", "2", "3", "4"} {
+		fsys["corpus/"+n] = corpusFile("int(" + n + ")")
+	}
+	w := &strings.Builder{}
+	err := DumpDirGoAdd(w, fsys, "corpus", WithPreview(1), WithSizeNotes())
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal("// 1 arg, 8 B\nf.Add(int(1))\n"+
+		"// ... 2 entries omitted\n// 1 arg, 8 B\nf.Add(int(4))\n", w.String())
+
+	w.Reset()
+	req.NoError(DumpDirGoAdd(w, fsys, "corpus", WithPreview(1), WithMax(0, 2)))
+	req.Equal("f.Add(int(1))\nf.Add(int(2))\n", w.String())
+
+	p := func(b []byte) bool { return strings.HasPrefix(string(b), "f.Add(int(2))") }
+	err = DumpDirGoAdd(PredicateErrWriter(io.Discard, errSnap, p), fsys, "corpus")
+	req.EqualError(err, XwriteErr(errSnap).Error())
+}
+
+func TestDumpDirGoAdd_limit(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for _, n := range []string{"1", "2", "3"} {
+		fsys["corpus/"+n] = corpusFile("int(" + n + ")")
+	}
+	w := &strings.Builder{}
+	req := require.New(t)
+	req.NoError(DumpDirGoAdd(w, fsys, "corpus", WithLimit(1)))
+	req.Equal("f.Add(int(1))\n// ... 2 entries omitted\n", w.String())
+
+	w.Reset()
+	req.NoError(DumpDirGoAdd(w, fsys, "corpus", WithOffset(1), WithLimit(1)))
+	req.Equal("// ... 1 entry omitted\nf.Add(int(2))\n// ... 1 entry omitted\n", w.String())
+}
+
+func TestWithReceiver(t *testing.T) {
+	fsys := fstest.MapFS{"corpus/1": corpusFile("int(1)")}
+	w := &strings.Builder{}
+	req := require.New(t)
+	req.NoError(DumpDirGoAdd(w, fsys, "corpus", WithReceiver("ff")))
+	req.Equal("ff.Add(int(1))\n", w.String())
+}
@@ -0,0 +1,86 @@
+package fuzzdump
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithHashedValues replaces the contents of every string and []byte
+// value with bytes derived from a hash of it, salted with salt, keeping
+// their lengths, as well as the rest of the values, intact. This lets
+// the shape of a corpus be shared without revealing its payloads.
+//
+// The replacements are stable: the same content is always replaced the
+// same way with the same salt, so equal values stay equal. A string is
+// replaced with hexadecimal digits, a []byte, with arbitrary bytes. All
+// the values are re-encoded in their normal form, as with [WithCanonical].
+//
+// Hashing requires decoding every value in the corpus. A value that
+// cannot be decoded is reported as [ErrMalformedValue], and its entry is
+// not dumped.
+func WithHashedValues(salt []byte) Option {
+	if salt == nil {
+		salt = []byte{}
+	}
+	return func(o *options) { o.hashSalt = salt }
+}
+
+// hashingLineReader returns a lineReader that reads the value lines of
+// a corpus entry file with read, and replaces its string and []byte
+// values with salted hashes of their contents.
+func hashingLineReader(read lineReader, salt []byte) lineReader {
+	return func(fsys fs.FS, name string) (lines corpus.Entry, err error) {
+		if lines, err = read(fsys, name); lines == nil {
+			return
+		}
+		vals, vErr := lines.Values()
+		if vErr != nil {
+			return nil, vErr
+		}
+		for i, v := range vals {
+			vals[i] = hashValue(v, salt)
+		}
+		var eErr error
+		if lines, eErr = corpus.NewEntry(vals...); eErr != nil {
+			return nil, eErr
+		}
+		return
+	}
+}
+
+// hashValue returns v with the contents of a string or a []byte replaced
+// by as many bytes derived from their hash, salted with salt. Values of
+// other types are returned as they are.
+func hashValue(v corpus.Value, salt []byte) corpus.Value {
+	switch v := v.(type) {
+	case string:
+		b := hashStream(salt, []byte(v), (len(v)+1)/2)
+		return hex.EncodeToString(b)[:len(v)]
+	case []byte:
+		return hashStream(salt, v, len(v))
+	}
+	return v
+}
+
+// hashStream returns n bytes derived from the HMAC-SHA256 of data keyed
+// with salt, by hashing it along with a counter as many times as needed.
+func hashStream(salt, data []byte, n int) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(data)
+	key := mac.Sum(nil)
+	out := make([]byte, 0, n+sha256.Size)
+	var ctr [8]byte
+	for i := uint64(0); len(out) < n; i++ {
+		binary.BigEndian.PutUint64(ctr[:], i)
+		h := sha256.New()
+		h.Write(key)
+		h.Write(ctr[:])
+		out = h.Sum(out)
+	}
+	return out[:n]
+}
@@ -0,0 +1,86 @@
+package fuzzdump_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_hashValue(t *testing.T) {
+	salt := []byte("salt")
+	tests := map[string]struct {
+		v     corpus.Value
+		wLen  int
+		wSame bool
+	}{"string": {
+		v:    "foo bar baz",
+		wLen: 11,
+	}, "long string": {
+		v:    strings.Repeat("x", 100),
+		wLen: 100,
+	}, "empty string": {
+		v:     "",
+		wSame: true,
+	}, "bytes": {
+		v:    []byte("\x00\x01\x02"),
+		wLen: 3,
+	}, "long bytes": {
+		v:    make([]byte, 70),
+		wLen: 70,
+	}, "int": {
+		v:     int(42),
+		wSame: true,
+	}, "rune": {
+		v:     'x',
+		wSame: true,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := XhashValue(tt.v, salt)
+			req := require.New(t)
+			req.IsType(tt.v, got)
+			if tt.wSame {
+				req.Equal(tt.v, got)
+				return
+			}
+			req.NotEqual(tt.v, got)
+			req.Equal(got, XhashValue(tt.v, salt), "stable")
+			req.NotEqual(got, XhashValue(tt.v, []byte("pepper")), "salted")
+			switch got := got.(type) {
+			case string:
+				req.Len(got, tt.wLen)
+			case []byte:
+				req.Len(got, tt.wLen)
+			}
+		})
+	}
+}
+
+func TestDumpDir_hashedValues(t *testing.T) {
+	const dir = "secrets"
+	salt := []byte("salt")
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(0x2a)\nstring(\"hunter2\")\n[]byte(\"\\x00key\")"),
+		dir + "/2": corpusFile("int(7)\nstring(\"hunter2\")\n[]byte(\"\")"),
+	}
+	line := func(v corpus.Value) string {
+		b, err := corpus.EncodeValue(v)
+		require.NoError(t, err)
+		return string(b)
+	}
+	secret := line(XhashValue("hunter2", salt))
+	want := "{{\n" +
+		"\tint(42),\n\t" + secret + ",\n\t" + line(XhashValue([]byte("\x00key"), salt)) + ",\n" +
+		"}, {\n" +
+		"\tint(7),\n\t" + secret + ",\n\t[]byte(\"\"),\n" +
+		"}}\n"
+	b := &strings.Builder{}
+	req := require.New(t)
+	req.NoError(DumpDir(b, fsys, dir, WithHashedValues(salt)))
+	req.Equal(want, b.String())
+	req.NotContains(b.String(), "hunter2")
+}
@@ -0,0 +1,61 @@
+package fuzzdump
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithInspection makes [DumpDir] pass the contents of every []byte value
+// to fn before dumping it, along with the name of the corpus entry file
+// and the index of the argument it holds, e.g., to have it scanned for
+// malware before a dump is published to a shared location.
+//
+// If fn returns an error, the entry is not dumped, and the error is
+// reported as [ErrRejectedValue]. If the error is (or wraps)
+// [ErrFlaggedValue], the entry is dumped nonetheless, but the error is
+// still reported.
+//
+// Inspection requires decoding every value in the corpus. A value that
+// cannot be decoded is reported as [ErrMalformedValue], and its entry is
+// not dumped. The contents are inspected as they are in the corpus, even
+// with [WithHashedValues].
+func WithInspection(fn func(name string, arg int, content []byte) error) Option {
+	return func(o *options) { o.inspect = fn }
+}
+
+// inspectingLineReader returns a lineReader that reads the value lines
+// of a corpus entry file with read, and inspects its []byte values with
+// inspect, see [WithInspection].
+func inspectingLineReader(
+	read lineReader, inspect func(name string, arg int, content []byte) error,
+) lineReader {
+	return func(fsys fs.FS, name string) (lines corpus.Entry, err error) {
+		if lines, err = read(fsys, name); lines == nil {
+			return
+		}
+		vals, vErr := lines.Values()
+		if vErr != nil {
+			return nil, vErr
+		}
+		for i, v := range vals {
+			b, ok := v.([]byte)
+			if !ok {
+				continue
+			}
+			switch iErr := inspect(path.Base(name), i, b); {
+			case iErr == nil:
+			case errors.Is(iErr, ErrFlaggedValue):
+				if err == nil {
+					err = fmt.Errorf("arg %d: %w", i, iErr)
+				}
+			default:
+				return nil, fmt.Errorf("%w: arg %d: %v", ErrRejectedValue, i, iErr)
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,47 @@
+package fuzzdump_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDir_inspection(t *testing.T) {
+	const dir = "uploads"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(1)\n[]byte(\"clean\")"),
+		dir + "/2": corpusFile("int(2)\n[]byte(\"EICAR\")"),
+		dir + "/3": corpusFile("int(3)\n[]byte(\"suspect\")"),
+		dir + "/4": corpusFile("int(4)\n[]byte(\"bad\""),
+	}
+	errVirus := errors.New("virus found")
+	var inspected []string
+	inspect := func(name string, arg int, content []byte) error {
+		inspected = append(inspected, fmt.Sprintf("%s/%d", name, arg))
+		switch {
+		case bytes.Equal(content, []byte("EICAR")):
+			return errVirus
+		case bytes.Equal(content, []byte("suspect")):
+			return fmt.Errorf("%w: heuristics", ErrFlaggedValue)
+		}
+		return nil
+	}
+	b := &strings.Builder{}
+	err := DumpDir(b, fsys, dir, WithInspection(inspect), WithConcurrency(1))
+	req := require.New(t)
+	req.Equal("{{\n"+
+		"\tint(1),\n\t[]byte(\"clean\"),\n"+
+		"}, {\n"+
+		"\tint(3),\n\t[]byte(\"suspect\"),\n"+
+		"}}\n", b.String())
+	req.ErrorIs(err, CorpusErrors{ErrRejectedValue, ErrFlaggedValue, ErrMalformedValue})
+	req.ErrorContains(err, `reading "2": corpus entry value rejected by inspection: arg 1: virus found`)
+	req.ErrorContains(err, `reading "3": arg 1: corpus entry value flagged by inspection: heuristics`)
+	req.Equal([]string{"1/1", "2/1", "3/1"}, inspected)
+}
@@ -0,0 +1,102 @@
+package fuzzdump
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// DumpDirJSON writes the entries from a fuzz test corpus directory to w
+// as a JSON array, one entry per line, each entry an array of its typed
+// values, as [corpus.Entry.MarshalJSON] encodes it, e.g.:
+//
+//	[
+//	[{"type":"int","value":8},{"type":"string","value":"foo"}],
+//	[{"type":"int","value":13},{"type":"string","value":"bar"}]
+//	]
+//
+// This is meant to be processed by other tools, such as jq, rather than
+// read. Errors are reported as with [DumpDir], and so are opts applied,
+// save for those that only concern the comments and the framing of the
+// dump format, which JSON has no use for. Every value is decoded, and a
+// value that cannot be is reported as [ErrMalformedValue], and its
+// entry is not dumped.
+func DumpDirJSON(w io.Writer, fsys fs.FS, dir string, opts ...Option) error {
+	o := newOptions(opts)
+	o.decode = true
+	return dump(w, fsys, dir, o, func(w io.Writer, _ int) entryPrinter {
+		return &jsonPrinter{w: w}
+	})
+}
+
+// DumpDirNDJSON writes the entries from a fuzz test corpus directory to
+// w as NDJSON (newline-delimited JSON), each entry on a line of its own
+// as an array of its typed values, as [DumpDirJSON] does, but without
+// the enclosing array, e.g.:
+//
+//	[{"type":"int","value":8},{"type":"string","value":"foo"}]
+//	[{"type":"int","value":13},{"type":"string","value":"bar"}]
+//
+// This lets tools process the entries as a stream, one at a time. Errors
+// are reported and opts applied as with [DumpDirJSON].
+func DumpDirNDJSON(w io.Writer, fsys fs.FS, dir string, opts ...Option) error {
+	o := newOptions(opts)
+	o.decode = true
+	return dump(w, fsys, dir, o, func(w io.Writer, _ int) entryPrinter {
+		return &jsonPrinter{w: w, lines: true}
+	})
+}
+
+// A jsonPrinter renders the entries of a dump as a JSON array, see
+// [DumpDirJSON], or as NDJSON lines, see [DumpDirNDJSON].
+type jsonPrinter struct {
+	w     io.Writer
+	lines bool // Whether to print NDJSON lines rather than an array.
+	count int  // Of the entries printed so far.
+}
+
+func (p *jsonPrinter) Begin() error {
+	if p.lines {
+		return nil
+	}
+	return p.print("[")
+}
+
+func (p *jsonPrinter) Entry(e corpus.Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if p.lines {
+		return p.print(string(b) + "\n")
+	}
+	sep := ",\n"
+	if p.count == 0 {
+		sep = "\n"
+	}
+	p.count++
+	return p.print(sep + string(b))
+}
+
+// Omit does nothing: JSON has no comments to note the omitted entries.
+func (p *jsonPrinter) Omit(int) {}
+
+// Comment does nothing: JSON has no comments.
+func (p *jsonPrinter) Comment(string) {}
+
+func (p *jsonPrinter) End() error {
+	if p.lines {
+		return nil
+	}
+	if p.count == 0 {
+		return p.print("]\n")
+	}
+	return p.print("\n]\n")
+}
+
+func (p *jsonPrinter) print(s string) error {
+	_, err := io.WriteString(p.w, s)
+	return writeErr(err)
+}
@@ -0,0 +1,133 @@
+package fuzzdump_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDirJSON(t *testing.T) {
+	const dir = "corpus"
+	tests := map[string]struct {
+		fsys  fstest.MapFS
+		opts  []Option
+		wOut  string
+		wErrs []error
+	}{"entries": {
+		fsys: fstest.MapFS{
+			dir + "/1": corpusFile("int(8)\nstring(\"foo\")"),
+			dir + "/2": corpusFile("int(13)\nstring(\"bar\")"),
+		},
+		wOut: "[\n" +
+			`[{"type":"int","value":8},{"type":"string","value":"foo"}],` + "\n" +
+			`[{"type":"int","value":13},{"type":"string","value":"bar"}]` + "\n" +
+			"]\n",
+	}, "canonical": {
+		fsys: fstest.MapFS{
+			dir + "/1": corpusFile("[]byte(\"b\")"),
+			dir + "/2": corpusFile("[]byte(\"a\")"),
+		},
+		opts: []Option{WithCanonical(), WithArgLabels(), WithSizeNotes()},
+		wOut: "[\n" +
+			`[{"type":"[]byte","value":"YQ=="}],` + "\n" +
+			`[{"type":"[]byte","value":"Yg=="}]` + "\n" +
+			"]\n",
+	}, "empty": {
+		fsys: fstest.MapFS{dir: {Mode: fs.ModeDir}},
+		opts: []Option{WithAllowEmpty()},
+		wOut: "[]\n",
+	}, "malformed value": {
+		fsys: fstest.MapFS{
+			dir + "/1": corpusFile("int(1)"),
+			dir + "/2": corpusFile("int(x)"),
+		},
+		wOut:  "[\n" + `[{"type":"int","value":1}]` + "\n]\n",
+		wErrs: []error{ErrMalformedValue},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			err := DumpDirJSON(w, tt.fsys, dir, tt.opts...)
+			req := require.New(t)
+			if tt.wErrs != nil {
+				req.ErrorIs(err, CorpusErrors(tt.wErrs))
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, w.String())
+
+			var entries []corpus.Entry
+			req.NoError(json.Unmarshal([]byte(w.String()), &entries), "valid JSON")
+		})
+	}
+}
+
+func TestDumpDirJSON_OutputErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"corpus/1": corpusFile("int(1)"),
+		"corpus/2": corpusFile("int(2)"),
+	}
+	for _, failOn := range []string{"[", "\n[{", ",\n[{", "\n]\n"} {
+		t.Run(fmt.Sprintf("fail writing=%q", failOn), func(t *testing.T) {
+			p := func(b []byte) bool { return strings.HasPrefix(string(b), failOn) }
+			w := PredicateErrWriter(io.Discard, errSnap, p)
+			err := DumpDirJSON(w, fsys, "corpus")
+			require.EqualError(t, err, XwriteErr(errSnap).Error())
+		})
+	}
+}
+
+func TestDumpDirNDJSON(t *testing.T) {
+	const dir = "corpus"
+	tests := map[string]struct {
+		fsys  fstest.MapFS
+		opts  []Option
+		wOut  string
+		wErrs []error
+	}{"entries": {
+		fsys: fstest.MapFS{
+			dir + "/1": corpusFile("int(8)\nstring(\"foo\")"),
+			dir + "/2": corpusFile("int(13)\nstring(\"bar\")"),
+		},
+		wOut: `[{"type":"int","value":8},{"type":"string","value":"foo"}]` + "\n" +
+			`[{"type":"int","value":13},{"type":"string","value":"bar"}]` + "\n",
+	}, "empty": {
+		fsys: fstest.MapFS{dir: {Mode: fs.ModeDir}},
+		opts: []Option{WithAllowEmpty()},
+		wOut: "",
+	}, "malformed value": {
+		fsys: fstest.MapFS{
+			dir + "/1": corpusFile("int(1)"),
+			dir + "/2": corpusFile("int(x)"),
+		},
+		wOut:  `[{"type":"int","value":1}]` + "\n",
+		wErrs: []error{ErrMalformedValue},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			err := DumpDirNDJSON(w, tt.fsys, dir, tt.opts...)
+			req := require.New(t)
+			if tt.wErrs != nil {
+				req.ErrorIs(err, CorpusErrors(tt.wErrs))
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, w.String())
+
+			dec := json.NewDecoder(strings.NewReader(w.String()))
+			for dec.More() {
+				var e corpus.Entry
+				req.NoError(dec.Decode(&e), "valid JSON")
+			}
+		})
+	}
+}
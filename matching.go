@@ -0,0 +1,31 @@
+package fuzzdump
+
+import (
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// Matching returns the names of the entry files in the fuzz test corpus
+// directory dir in fsys that [DumpDir] would dump with opts, e.g., those
+// with an argument of an exact value, as [WithEqual] selects them, to
+// extract them elsewhere. The names are in the order of the files, as
+// they are read, which [WithSort] can set.
+//
+// The corpus is validated the same way as with DumpDir, and the errors
+// of the invalid entries are reported in [CorpusErrors], along with the
+// names. Any other error is returned as it is.
+func Matching(fsys fs.FS, dir string, opts ...Option) (names []string, err error) {
+	o := newOptions(opts)
+	begin := func(int) error { return nil }
+	emit := func(name string, _ corpus.Entry) error {
+		names = append(names, name)
+		return nil
+	}
+	err = readDir(fsys, dir, o, begin, emit)
+	var errs CorpusErrors
+	if e := errs.Capture(err); e != nil {
+		return nil, e
+	}
+	return names, errs.AsError()
+}
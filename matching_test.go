@@ -0,0 +1,51 @@
+package fuzzdump_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatching(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("uint(42)"),
+		dir + "/2": corpusFile("uint(0x2a)"),
+		dir + "/3": corpusFile("int(42)"),
+		dir + "/4": corpusFile("uint("),
+	}
+	tests := map[string]struct {
+		opts  []Option
+		want  []string
+		wErrs []error
+	}{"equal": {
+		opts:  []Option{WithEqual(0, uint(42))},
+		want:  []string{"1", "2"},
+		wErrs: []error{ErrMalformedValue},
+	}, "none": {
+		opts:  []Option{WithEqual(0, uint(7))},
+		wErrs: []error{ErrMalformedValue},
+	}, "undecoded": {
+		want: []string{"1", "2", "3", "4"},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := Matching(fsys, dir, tt.opts...)
+			req := require.New(t)
+			if tt.wErrs == nil {
+				req.NoError(err)
+			}
+			for _, wErr := range tt.wErrs {
+				req.ErrorIs(err, wErr)
+			}
+			req.Equal(tt.want, got)
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		_, err := Matching(fstest.MapFS{}, dir)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
@@ -0,0 +1,55 @@
+package fuzzdump
+
+import (
+	"fmt"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithMemoryCap makes [DumpDir] (as well as [DumpDirJSON],
+// [DumpDirGoAdd] and [ReadDir]) fail with [ErrMemoryCap] as soon as the
+// entries it has to hold in memory at once take more than n bytes, so
+// that a service dumping untrusted corpora can bound its memory
+// footprint. With n less than 1, there is no cap.
+//
+// This is a hard cap: the entries are never spilled to disk to carry on
+// past it, so a corpus that needs more memory than n allows cannot be
+// dumped with these options at all.
+//
+// The entries have to be held when they are sorted, as with
+// [WithCanonical], when the output is, as with [WithAtomicOutput] and
+// [WithStrict], and when they are returned, as by [ReadDir]. The size of
+// an entry is taken to be that of its value lines and file name, which
+// is about as much as its dump takes. Otherwise, the entries are dumped
+// as they are read, and only as many are held as are read ahead
+// concurrently, see [WithConcurrency].
+func WithMemoryCap(n int64) Option {
+	return func(o *options) { o.memCap = n }
+}
+
+// capped returns emit wrapped to fail with [ErrMemoryCap] once the
+// entries it has been passed take more memory than o allows, if the
+// entries emitted are retained.
+func (o options) capped(emit emitter) emitter {
+	if !o.retain || o.memCap < 1 {
+		return emit
+	}
+	var used int64
+	return func(name string, lines corpus.Entry) error {
+		used += entrySize(name, lines)
+		if used > o.memCap {
+			return fmt.Errorf("%w: entries take more than %d bytes", ErrMemoryCap, o.memCap)
+		}
+		return emit(name, lines)
+	}
+}
+
+// entrySize returns the number of bytes that the lines of an entry and
+// the name of its file take.
+func entrySize(name string, lines corpus.Entry) (n int64) {
+	n = int64(len(name))
+	for _, l := range lines {
+		n += int64(len(l))
+	}
+	return
+}
@@ -0,0 +1,73 @@
+package fuzzdump_test
+
+import (
+	"io"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMemoryCap(t *testing.T) {
+	const dir = "corpus"
+	// Each entry takes 1 byte of name, and 6 of its value line.
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(1)"),
+		dir + "/2": corpusFile("int(2)"),
+		dir + "/3": corpusFile("int(3)"),
+	}
+	tests := map[string]struct {
+		dump  func(opts ...Option) error
+		opts  []Option
+		limit int64
+		wErr  error
+	}{"streaming": {
+		limit: 1,
+	}, "canonical within": {
+		opts:  []Option{WithCanonical()},
+		limit: 21,
+	}, "canonical over": {
+		opts:  []Option{WithCanonical()},
+		limit: 20,
+		wErr:  ErrMemoryCap,
+	}, "atomic over": {
+		opts:  []Option{WithAtomicOutput()},
+		limit: 20,
+		wErr:  ErrMemoryCap,
+	}, "strict over": {
+		opts:  []Option{WithStrict()},
+		limit: 20,
+		wErr:  ErrMemoryCap,
+	}, "filtered within": {
+		opts:  []Option{WithCanonical(), WithMax(0, 2)},
+		limit: 14,
+	}, "read over": {
+		dump: func(opts ...Option) error {
+			_, err := ReadDir(fsys, dir, opts...)
+			return err
+		},
+		limit: 20,
+		wErr:  ErrMemoryCap,
+	}, "no limit": {
+		opts: []Option{WithCanonical()},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			dump := tt.dump
+			if dump == nil {
+				dump = func(opts ...Option) error {
+					return DumpDir(io.Discard, fsys, dir, opts...)
+				}
+			}
+			err := dump(append(tt.opts, WithMemoryCap(tt.limit))...)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				req.Equal(ExitHard, ExitCodeFor(err))
+			} else {
+				req.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package fuzzdump
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/format"
+)
+
+// An Option modifies the behavior of [DumpDir].
+type Option func(*options)
+
+// WithCanonical makes the output suitable for committing to version
+// control as a golden file: every value is decoded and re-encoded in
+// its normal form, as with [WithNormalizedValues], and the entries are
+// sorted by their normalized content instead of their file names.
+//
+// The resulting output only changes when the values in the corpus do.
+// A value that cannot be decoded is reported as [ErrMalformedValue],
+// and its entry is not dumped.
+func WithCanonical() Option {
+	return func(o *options) { o.canonical = true }
+}
+
+// WithNormalizedValues makes [DumpDir] decode every value and render it
+// anew with [corpus.EncodeValue], which formats it with [strconv]
+// directly, instead of passing the value lines of the corpus entry files
+// through as they are. The output then depends on nothing but the
+// values themselves: it is the same on every platform, and with every
+// Go version, even if the toolchain were to change how it encodes them.
+// The entries are still dumped in the order of their file names, unlike
+// with [WithCanonical], which implies this.
+//
+// A value that cannot be decoded is reported as [ErrMalformedValue],
+// and its entry is not dumped.
+func WithNormalizedValues() Option {
+	return func(o *options) { o.normalize = true }
+}
+
+// WithArgLabels prefixes each value in a multiple-argument corpus dump
+// with a comment stating the index of the argument it holds, e.g.:
+//
+//	{{
+//		/* arg0 */ int(8),
+//		/* arg1 */ string("foo"),
+//	}}
+//
+// This keeps long entries navigable. A single-argument corpus dump is
+// not affected.
+func WithArgLabels() Option {
+	return func(o *options) { o.argLabels = true }
+}
+
+// WithAllowEmpty makes [DumpDir] treat a corpus directory that is empty
+// or does not exist as a valid corpus with no entries, dumping just the
+// empty braces instead of returning [ErrEmptyCorpus] or an
+// [fs.ErrNotExist]. A freshly added fuzz test has no corpus yet.
+//
+// A directory with only invalid corpus files is still reported as
+// [ErrEmptyCorpus] in [CorpusErrors].
+func WithAllowEmpty() Option {
+	return func(o *options) { o.allowEmpty = true }
+}
+
+// WithAtomicOutput makes [DumpDir] render the entire dump in memory and
+// only write it out if no critical error occurred, so that a partial,
+// truncated dump is never written.
+//
+// A dump is still written when only some of the corpus entries were
+// invalid, as it is complete, save for those entries.
+func WithAtomicOutput() Option {
+	return func(o *options) { o.atomic = true }
+}
+
+// WithStrict makes [DumpDir] write nothing at all if any error occurs,
+// including the validation errors of individual corpus entries, for when
+// a partial dump is worse than none. It implies [WithAtomicOutput], and
+// every value is decoded to make sure it is valid.
+func WithStrict() Option {
+	return func(o *options) { o.strict, o.decode = true, true }
+}
+
+// WithConcurrency makes [DumpDir] read and parse up to n corpus files
+// concurrently. By default, or if n is less than 1, as many files are
+// read as there are CPUs. The output is the same either way.
+func WithConcurrency(n int) Option {
+	return func(o *options) { o.jobs = n }
+}
+
+// WithContext makes [DumpDir] stop reading corpus files once ctx is
+// done, and return its error, wrapped in a [FileError] of the first file
+// left unread, e.g., to give up on a dump that its requester has gone
+// away from. Files already being read by the concurrent workers (see
+// [WithConcurrency]) are read to the end, but not dumped. By default,
+// the dump runs to the end.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithAcceptVersions makes [DumpDir] accept corpus entry files with any
+// of the given version headers, instead of just [corpus.Version1], e.g.,
+// when they were written by a toolchain patched to use another one:
+//
+//	fuzzdump.WithAcceptVersions(corpus.Version1, "acme test fuzz v1")
+//
+// The values are expected to be encoded as in version 1 regardless.
+func WithAcceptVersions(versions ...string) Option {
+	return func(o *options) { o.versions = versions }
+}
+
+// WithAssumeVersion1 makes [DumpDir] salvage the corpus entry files that
+// lack a version header, but otherwise consist of valid values, as when
+// they were concatenated or trimmed by hand: they are taken for version
+// 1 entries and dumped, but still reported as [ErrMissingVersion].
+func WithAssumeVersion1() Option {
+	return func(o *options) { o.assumeV1 = true }
+}
+
+// WithFraming makes [DumpDir] write each entry as a separate record,
+// a complete dump of just that entry, framed by f (such as
+// [format.LengthPrefixed]), so that a consumer reading the output as a
+// stream can split it into records without parsing the dump format.
+func WithFraming(f format.Framing) Option {
+	return func(o *options) { o.framing = f }
+}
+
+// WithPreview makes [DumpDir] dump just the first and the last n of the
+// entries (in the order they would be dumped in otherwise), noting the
+// number of those omitted in between with a comment, e.g.:
+//
+//	{
+//		int(2),
+//		// ... 42 entries omitted
+//		int(97),
+//	}
+//
+// This gives a representative glimpse of a huge corpus. With n less
+// than 1, all the entries are dumped.
+func WithPreview(n int) Option {
+	return func(o *options) { o.preview = n }
+}
+
+// WithLimit makes [DumpDir] dump at most n entries, noting the number of
+// the rest omitted with a comment at the end of the dump, as
+// [Options.MaxEntries] does. With n less than 1, all the entries are
+// dumped.
+//
+// The whole corpus is still read and validated.
+func WithLimit(n int) Option {
+	return func(o *options) { o.style.MaxEntries = n }
+}
+
+// WithSort makes [DumpDir] dump the entries in the order s sets, or the
+// reverse of it, if descending, as [Options.Sort] and
+// [Options.Descending] do, e.g., by their sizes, or, regardless of how
+// their files are named, by their hashes, for dumps of corpora fuzzed
+// in different runs to compare. [WithCanonical] takes precedence over
+// s, but not over descending.
+func WithSort(s SortOrder, descending bool) Option {
+	return func(o *options) { o.style.Sort, o.style.Descending = s, descending }
+}
+
+// WithBytesFormat makes [DumpDir] render the []byte values in the format
+// f, as [Options.BytesFormat] does, e.g., as hex literals, which binary
+// data is more readable as than the escaped strings of the corpus files:
+//
+//	{
+//		[]byte{0x00, 0x01, 0x68, 0x69},
+//	}
+//
+// The values that cannot be decoded are dumped as they are.
+func WithBytesFormat(f format.BytesFormat) Option {
+	return func(o *options) { o.style.BytesFormat = f }
+}
+
+// WithOffset makes [DumpDir] leave out the first n entries (in the order
+// they would be dumped in otherwise), noting their number with a comment
+// at the start of the dump, as [Options.Offset] does, e.g., to page
+// through a huge corpus along with [WithLimit]:
+//
+//	{
+//		// ... 100 entries omitted
+//		int(101),
+//		int(102),
+//		// ... 42 entries omitted
+//	}
+func WithOffset(n int) Option {
+	return func(o *options) { o.style.Offset = n }
+}
+
+// WithEntryComments makes [DumpDir] write a comment ahead of the values
+// of each entry, with the text that fn returns for the name of its file,
+// unless that is empty, e.g., to note where the entry came from:
+//
+//	{
+//		// imported from corpus.json
+//		int(2),
+//	}
+//
+// Each line of the text is written as a separate line comment.
+func WithEntryComments(fn func(name string) string) Option {
+	return func(o *options) { o.comment = fn }
+}
+
+// WithFileNames makes [DumpDir] write a comment with the name of the
+// file of each entry ahead of its values, ahead of any other comment,
+// e.g.:
+//
+//	{
+//		// 582528ddfad69eb5
+//		int(2),
+//	}
+//
+// This helps find, and delete, the file of an entry spotted in a dump.
+func WithFileNames() Option {
+	return func(o *options) { o.names = true }
+}
+
+type options struct {
+	ctx        context.Context
+	jobs       int
+	atomic     bool
+	strict     bool
+	canonical  bool
+	normalize  bool
+	argLabels  bool
+	allowEmpty bool
+	framing    format.Framing
+	preview    int
+	comment    func(name string) string
+	names      bool
+	sizes      bool
+	find       []byte
+	progress   func(Event)
+	memCap     int64
+	// Whether the entries emitted are held in memory, see WithMemoryCap.
+	retain bool
+	// Accepted version headers, if not just corpus.Version1.
+	versions []string
+	// Whether to salvage entries that lack a version header.
+	assumeV1 bool
+	// Whether to salvage the leading values of entries with garbage.
+	recover bool
+	// Inspects the contents of []byte values, if they are to be.
+	inspect func(name string, arg int, content []byte) error
+	// Name of the variable whose Add method DumpDirGoAdd calls.
+	receiver string
+	// Salt to hash string and []byte values with, if they are to be.
+	hashSalt []byte
+	// Whether all values must be decodable, even without any match.
+	decode bool
+	// Predicates that the decoded values of an entry must satisfy.
+	match []func(vals []any) bool
+	// Style of the dump format, see DumpDirWith.
+	style Options
+	// Passed the files of the corpus once they are listed, if set.
+	listed func(files []fs.DirEntry)
+}
+
+func newOptions(opts []Option) (o options) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return
+}
+
+// context returns the context that o sets, or the background one.
+func (o options) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
@@ -0,0 +1,131 @@
+package fuzzdump
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"runtime"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+	"golang.org/x/sync/errgroup"
+)
+
+// The corpus is dumped by a pipeline of stages:
+//
+//  1. the names of the corpus files are listed by [corpusFiles];
+//  2. the files are read and their values parsed (as the lineReader of
+//     the options requires) by concurrent workers of a [prefetcher];
+//  3. the entries are validated and filtered, in the order of their
+//     files, by [readDir];
+//  4. the entries are formatted by a [format.Printer].
+//
+// Only the second stage runs concurrently, handing the results over to
+// the third one in order, so the output and errors are the same as if
+// all the files were read one after another.
+
+// readAhead is how many files, per worker, a [prefetcher] may read ahead
+// of the one being consumed.
+const readAhead = 4
+
+// A prefetcher reads corpus files concurrently, ahead of the consumer
+// of their lines.
+type prefetcher struct {
+	ctx     context.Context // Of the consumer, to stop waiting once done.
+	readFn  lineReader
+	results map[string]*prefetched
+	ahead   chan struct{}
+	cancel  context.CancelFunc
+	g       *errgroup.Group
+}
+
+// prefetched is the result of reading a file.
+type prefetched struct {
+	lines corpus.Entry
+	err   error
+	done  chan struct{}
+}
+
+// prefetch starts reading files from dir in fsys with read, using up
+// to jobs concurrent workers (or as many as there are CPUs, if jobs is
+// less than 1), until ctx is done. Files smaller than minSize are
+// reported as [ErrShortEntry] without being read.
+//
+// The files have to be consumed with [prefetcher.read] in the order
+// given. The prefetcher must be stopped with [prefetcher.stop] once done
+// with.
+func prefetch(
+	ctx context.Context,
+	fsys fs.FS,
+	dir string,
+	files []fs.DirEntry,
+	read lineReader,
+	jobs int,
+	minSize int64,
+) *prefetcher {
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	gCtx, cancel := context.WithCancel(ctx)
+	g, gCtx := errgroup.WithContext(gCtx)
+	// One more for the goroutine feeding the workers.
+	g.SetLimit(jobs + 1)
+	p := &prefetcher{
+		ctx:     ctx,
+		readFn:  read,
+		results: make(map[string]*prefetched, len(files)),
+		ahead:   make(chan struct{}, jobs*readAhead),
+		cancel:  cancel,
+		g:       g,
+	}
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = path.Join(dir, f.Name())
+		p.results[names[i]] = &prefetched{done: make(chan struct{})}
+	}
+	g.Go(func() error {
+		for i, name := range names {
+			select {
+			case p.ahead <- struct{}{}:
+			case <-gCtx.Done():
+				return gCtx.Err()
+			}
+			f, name, r := files[i], name, p.results[name]
+			g.Go(func() error {
+				defer close(r.done)
+				if r.err = checkSize(f, minSize); r.err == nil {
+					r.lines, r.err = read(fsys, name)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return p
+}
+
+// read implements the lineReader interface, returning the result of
+// reading the named file, once it is available, or the error of the
+// context of p, once that is done. A file not given to [prefetch] is
+// read from fsys right away.
+func (p *prefetcher) read(fsys fs.FS, name string) (corpus.Entry, error) {
+	if err := p.ctx.Err(); err != nil {
+		return nil, err
+	}
+	r, ok := p.results[name]
+	if !ok {
+		return p.readFn(fsys, name)
+	}
+	select {
+	case <-r.done:
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+	<-p.ahead
+	return r.lines, r.err
+}
+
+// stop reading any more files and wait for those being read.
+func (p *prefetcher) stop() {
+	p.cancel()
+	p.g.Wait()
+}
@@ -0,0 +1,196 @@
+package fuzzdump_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_prefetch(t *testing.T) {
+	const (
+		dir  = "many"
+		jobs = 3
+	)
+	fsys := manyFS(dir, 20)
+	files, err := XgetFiles(fsys, dir)
+	require.NoError(t, err)
+	var running, peak int32
+	slowRead := func(fsys fs.FS, name string) (corpus.Entry, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		// Make the later files finish first.
+		time.Sleep(time.Duration(len(name)%3) * time.Millisecond)
+		return XreadLines(fsys, name)
+	}
+	read, stop := Xprefetch(context.Background(), fsys, dir, files, slowRead, jobs)
+	defer stop()
+
+	req := require.New(t)
+	for _, f := range files {
+		name := path.Join(dir, f.Name())
+		got, err := read(fsys, name)
+		req.NoError(err)
+		want, err := XreadLines(fsys, name)
+		req.NoError(err)
+		req.Equal(want, got)
+	}
+	req.LessOrEqual(peak, int32(jobs))
+}
+
+func Test_prefetch_stop(t *testing.T) {
+	const dir = "many"
+	fsys := manyFS(dir, 100)
+	files, err := XgetFiles(fsys, dir)
+	require.NoError(t, err)
+	var reads int32
+	countingRead := func(fsys fs.FS, name string) (corpus.Entry, error) {
+		atomic.AddInt32(&reads, 1)
+		return XreadLines(fsys, name)
+	}
+	read, stop := Xprefetch(context.Background(), fsys, dir, files, countingRead, 1)
+	_, err = read(fsys, path.Join(dir, files[0].Name()))
+	stop()
+
+	req := require.New(t)
+	req.NoError(err)
+	// Nothing is read after stopping.
+	n := atomic.LoadInt32(&reads)
+	req.Less(n, int32(len(files)))
+	time.Sleep(time.Millisecond)
+	req.Equal(n, atomic.LoadInt32(&reads))
+}
+
+func Test_prefetch_cancel(t *testing.T) {
+	const dir = "many"
+	fsys := manyFS(dir, 3)
+	files, err := XgetFiles(fsys, dir)
+	require.NoError(t, err)
+	release := make(chan struct{})
+	blockingRead := func(fsys fs.FS, name string) (corpus.Entry, error) {
+		<-release
+		return XreadLines(fsys, name)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	read, stop := Xprefetch(ctx, fsys, dir, files, blockingRead, 1)
+	defer stop()
+	defer close(release)
+
+	time.AfterFunc(time.Millisecond, cancel)
+	_, err = read(fsys, path.Join(dir, files[0].Name()))
+	req := require.New(t)
+	req.ErrorIs(err, context.Canceled)
+	_, err = read(fsys, path.Join(dir, files[1].Name()))
+	req.ErrorIs(err, context.Canceled)
+}
+
+func TestWithContext(t *testing.T) {
+	const dir = "many"
+	fsys := manyFS(dir, 5)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := map[string]struct {
+		ctx  context.Context
+		wOut string
+		wErr error
+	}{"background": {
+		ctx:  context.Background(),
+		wOut: "{\n\tint(0),\n\tint(1),\n\tint(2),\n\tint(3),\n\tint(4),\n}\n",
+	}, "canceled": {
+		ctx:  canceled,
+		wErr: context.Canceled,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			err := DumpDir(w, fsys, dir, WithContext(tt.ctx))
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+				var fErr *FileError
+				req.ErrorAs(err, &fErr)
+				req.Equal("f", fErr.Name)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wOut, w.String())
+		})
+	}
+}
+
+func Test_prefetch_unknownFile(t *testing.T) {
+	read, stop := Xprefetch(context.Background(), fsys, sigleDir, nil, XreadLines, 0)
+	defer stop()
+	got, err := read(fsys, sigleArgFile)
+	req := require.New(t)
+	req.NoError(err)
+	req.Equal(corpus.Entry{[]byte("uint(3)")}, got)
+}
+
+func Test_prefetch_shortEntry(t *testing.T) {
+	const dir = "short"
+	fsys := fstest.MapFS{
+		dir + "/empty":   &fstest.MapFile{},
+		dir + "/version": &fstest.MapFile{Data: []byte(corpus.Version1 + LF)},
+		dir + "/valid":   corpusFile("int(1)"),
+	}
+	files, err := XgetFiles(fsys, dir)
+	require.NoError(t, err)
+	var reads int32
+	countingRead := func(fsys fs.FS, name string) (corpus.Entry, error) {
+		atomic.AddInt32(&reads, 1)
+		return XreadLines(fsys, name)
+	}
+	read, stop := Xprefetch(context.Background(), fsys, dir, files, countingRead, 0)
+	defer stop()
+
+	req := require.New(t)
+	for _, f := range files {
+		_, err := read(fsys, path.Join(dir, f.Name()))
+		if f.Name() == "valid" {
+			req.NoError(err)
+		} else {
+			req.ErrorIs(err, ErrShortEntry, f.Name())
+		}
+	}
+	// Only the valid file is read.
+	req.Equal(int32(1), atomic.LoadInt32(&reads))
+}
+
+func TestDumpDir_concurrency(t *testing.T) {
+	const dir = "many"
+	fsys := manyFS(dir, 50)
+	req := require.New(t)
+	want := &strings.Builder{}
+	req.NoError(DumpDir(want, fsys, dir, WithConcurrency(1)))
+	for _, n := range []int{0, 2, 16} {
+		got := &strings.Builder{}
+		req.NoError(DumpDir(got, fsys, dir, WithConcurrency(n)))
+		req.Equal(want.String(), got.String(), "concurrency %d", n)
+	}
+}
+
+// manyFS returns a file system with n corpus files in dir.
+func manyFS(dir string, n int) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for i := 0; i < n; i++ {
+		name := path.Join(dir, strings.Repeat("f", i+1))
+		fsys[name] = corpusFile(fmt.Sprintf("int(%d)", i))
+	}
+	return fsys
+}
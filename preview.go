@@ -0,0 +1,47 @@
+package fuzzdump
+
+import "github.com/antichris/go-fuzzdump/corpus"
+
+// A preview passes on the first n entries it is given right away, and
+// keeps just the last n of the rest, to be passed on at the end.
+type preview struct {
+	n     int
+	emit  emitter
+	count int          // Of all the entries given.
+	last  []namedEntry // A ring of the last n of the rest.
+}
+
+// add an entry to v.
+func (v *preview) add(name string, lines corpus.Entry) error {
+	defer func() { v.count++ }()
+	if v.count < v.n {
+		return v.emit(name, lines)
+	}
+	e := namedEntry{name, lines}
+	if len(v.last) < v.n {
+		v.last = append(v.last, e)
+	} else {
+		v.last[(v.count-v.n)%v.n] = e
+	}
+	return nil
+}
+
+// flush passes on the last entries kept by v, noting the number of
+// those left out before them with p.
+func (v *preview) flush(p entryPrinter) error {
+	rest := v.count - v.n
+	if rest <= 0 {
+		return nil
+	}
+	start := 0
+	if rest > v.n {
+		p.Omit(rest - v.n)
+		start = rest % v.n
+	}
+	for _, e := range append(v.last[start:], v.last[:start]...) {
+		if err := v.emit(e.name, e.lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
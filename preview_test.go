@@ -0,0 +1,78 @@
+package fuzzdump_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDir_preview(t *testing.T) {
+	const dir = "many"
+	dump := func(vals ...int) string {
+		b := &strings.Builder{}
+		b.WriteString("{\n")
+		for _, v := range vals {
+			if v < 0 {
+				noun := "entries"
+				if v == -1 {
+					noun = "entry"
+				}
+				fmt.Fprintf(b, "\t// ... %d %s omitted\n", -v, noun)
+				continue
+			}
+			fmt.Fprintf(b, "\tint(%d),\n", v)
+		}
+		b.WriteString("}\n")
+		return b.String()
+	}
+	tests := map[string]struct {
+		entries int
+		n       int
+		want    string
+	}{"disabled": {
+		entries: 3,
+		want:    dump(0, 1, 2),
+	}, "fewer than n": {
+		entries: 2,
+		n:       3,
+		want:    dump(0, 1),
+	}, "fewer than 2n": {
+		entries: 5,
+		n:       3,
+		want:    dump(0, 1, 2, 3, 4),
+	}, "exactly 2n": {
+		entries: 4,
+		n:       2,
+		want:    dump(0, 1, 2, 3),
+	}, "one omitted": {
+		entries: 5,
+		n:       2,
+		want:    dump(0, 1, -1, 3, 4),
+	}, "many omitted": {
+		entries: 12,
+		n:       2,
+		want:    dump(0, 1, -8, 10, 11),
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			err := DumpDir(w, manyFS(dir, tt.entries), dir, WithPreview(tt.n))
+			req := require.New(t)
+			req.NoError(err)
+			req.Equal(tt.want, w.String())
+		})
+	}
+}
+
+func TestDumpDir_previewCanonical(t *testing.T) {
+	w := &strings.Builder{}
+	err := DumpDir(w, fsys, unsortedDir, WithCanonical(), WithPreview(1))
+	req := require.New(t)
+	req.NoError(err)
+	want := &strings.Builder{}
+	req.NoError(DumpDir(want, fsys, unsortedDir, WithCanonical()))
+	req.Equal(want.String(), w.String())
+}
@@ -0,0 +1,154 @@
+package fuzzdump
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithProgress makes [DumpDir] (as well as [DumpDirJSON] and [ReadDir])
+// pass fn an [Event] at each step of reading a corpus, so that a user
+// interface embedding this package can render its progress.
+//
+// The events are passed in order, from the goroutine that the corpus is
+// read in, so fn must return promptly for reading to go on.
+func WithProgress(fn func(Event)) Option {
+	return func(o *options) { o.progress = fn }
+}
+
+// An Event is a step of reading a corpus, see [WithProgress].
+type Event struct {
+	Kind EventKind
+	// Dir is the corpus directory.
+	Dir string
+	// Name is the name of the corpus entry file, for the events about
+	// one.
+	Name string
+	// Files is the number of files in Dir, for an [EventStarted].
+	Files int
+	// Err is the error that an [EventWarning] reports, or that reading
+	// ended with, for an [EventFinished].
+	Err error
+}
+
+// An EventKind is the kind of an [Event].
+type EventKind int
+
+// The kinds of events, in the order they occur.
+const (
+	// EventStarted is passed once the files in the directory are listed,
+	// before any of them are read.
+	EventStarted EventKind = iota + 1
+	// EventFileRead is passed when a corpus entry file is read.
+	EventFileRead
+	// EventEntryEmitted is passed when an entry is dumped (or, with
+	// [WithCanonical], taken to be dumped once all are read).
+	EventEntryEmitted
+	// EventWarning is passed when a corpus entry file is found invalid,
+	// reporting a validation error (see [IsValidationError]).
+	EventWarning
+	// EventFinished is passed when the directory is done reading,
+	// whether it succeeded or not.
+	EventFinished
+)
+
+var eventKinds = map[EventKind]string{
+	EventStarted:      "started",
+	EventFileRead:     "file-read",
+	EventEntryEmitted: "entry-emitted",
+	EventWarning:      "warning",
+	EventFinished:     "finished",
+}
+
+// String returns the name of k, e.g. "file-read".
+// Implements the [fmt.Stringer] interface.
+func (k EventKind) String() string {
+	if s, ok := eventKinds[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// A progress reports the progress of reading a corpus directory with
+// events, see [WithProgress]. Its methods do nothing on a nil progress,
+// so that it need not be checked for.
+type progress struct {
+	report   func(Event)
+	dir      string
+	argCount int // Of the entries, once known.
+}
+
+// newProgress returns the progress reporter for dir with o, or nil if
+// progress is not to be reported.
+func newProgress(o options, dir string) *progress {
+	if o.progress == nil {
+		return nil
+	}
+	return &progress{report: o.progress, dir: dir}
+}
+
+// start reports that the files of the directory were listed.
+func (p *progress) start(files int) {
+	if p != nil {
+		p.report(Event{Kind: EventStarted, Dir: p.dir, Files: files})
+	}
+}
+
+// finish reports that reading the directory ended with err.
+func (p *progress) finish(err error) {
+	if p != nil {
+		p.report(Event{Kind: EventFinished, Dir: p.dir, Err: err})
+	}
+}
+
+// begin returns begin wrapped to note the number of arguments.
+func (p *progress) begin(begin func(argCount int) error) func(argCount int) error {
+	if p == nil {
+		return begin
+	}
+	return func(argCount int) error {
+		p.argCount = argCount
+		return begin(argCount)
+	}
+}
+
+// read returns read wrapped to report each file read, and a warning for
+// each that is invalid.
+func (p *progress) read(read lineReader) lineReader {
+	if p == nil {
+		return read
+	}
+	return func(fsys fs.FS, name string) (corpus.Entry, error) {
+		lines, err := read(fsys, name)
+		name = path.Base(name)
+		p.report(Event{Kind: EventFileRead, Dir: p.dir, Name: name})
+		if IsValidationError(err) {
+			p.warn(name, err)
+		}
+		if l := len(lines); lines != nil && p.argCount > 0 && l != p.argCount {
+			p.warn(name, argCountErr(p.argCount, l))
+		}
+		return lines, err
+	}
+}
+
+// warn reports err about the named file.
+func (p *progress) warn(name string, err error) {
+	p.report(Event{Kind: EventWarning, Dir: p.dir, Name: name, Err: readErr(err, name)})
+}
+
+// emit returns emit wrapped to report each entry emitted.
+func (p *progress) emit(emit emitter) emitter {
+	if p == nil {
+		return emit
+	}
+	return func(name string, lines corpus.Entry) error {
+		if err := emit(name, lines); err != nil {
+			return err
+		}
+		p.report(Event{Kind: EventEntryEmitted, Dir: p.dir, Name: name})
+		return nil
+	}
+}
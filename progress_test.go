@@ -0,0 +1,73 @@
+package fuzzdump_test
+
+import (
+	"io"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDir_progress(t *testing.T) {
+	const dir = "corpus"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(1)\nstring(\"a\")"),
+		dir + "/2": corpusFile("int(2"),
+		dir + "/3": corpusFile("int(3)"),
+		dir + "/4": corpusFile("int(4)\nstring(\"b\")"),
+		dir + "/5": corpusFile("int(5)\nstring(\"c\")"),
+	}
+	type ev struct {
+		kind EventKind
+		name string
+		err  error
+	}
+	var got []ev
+	err := DumpDir(io.Discard, fsys, dir, WithCanonical(), WithMax(0, 4),
+		WithProgress(func(e Event) {
+			require.Equal(t, dir, e.Dir)
+			if e.Kind == EventStarted {
+				require.Equal(t, 5, e.Files)
+			}
+			got = append(got, ev{e.Kind, e.Name, e.Err})
+		}))
+	req := require.New(t)
+	req.ErrorIs(err, ErrMalformedValue)
+	req.ErrorIs(err, ErrInconsistentArgCount)
+
+	req.Len(got, 11)
+	req.ErrorIs(got[4].err, ErrMalformedValue)
+	req.ErrorIs(got[6].err, ErrInconsistentArgCount)
+	req.Equal(err, got[10].err)
+	for i := range got {
+		got[i].err = nil // Compared separately above.
+	}
+	req.Equal([]ev{
+		{EventStarted, "", nil},
+		{EventFileRead, "1", nil},
+		{EventEntryEmitted, "1", nil},
+		{EventFileRead, "2", nil},
+		{EventWarning, "2", nil},
+		{EventFileRead, "3", nil},
+		{EventWarning, "3", nil},
+		{EventFileRead, "4", nil},
+		{EventEntryEmitted, "4", nil},
+		{EventFileRead, "5", nil},
+		{EventFinished, "", nil},
+	}, got)
+}
+
+func TestEventKind_String(t *testing.T) {
+	tests := map[EventKind]string{
+		EventStarted:      "started",
+		EventFileRead:     "file-read",
+		EventEntryEmitted: "entry-emitted",
+		EventWarning:      "warning",
+		EventFinished:     "finished",
+		0:                 "EventKind(0)",
+	}
+	for k, want := range tests {
+		require.Equal(t, want, k.String())
+	}
+}
@@ -0,0 +1,112 @@
+package fuzzdump
+
+import (
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// A Corpus is the entries of a fuzz test corpus, each along with the
+// name, size and modification time of the file it was read from, and
+// its hash, as [ReadDir] returns them.
+//
+// A Corpus is safe for concurrent use by multiple goroutines, as long
+// as none of them modifies it.
+type Corpus []corpus.File
+
+// ReadDir reads the entries from a fuzz test corpus directory in fsys,
+// for a program to consume them, rather than parse a dump. Use
+// [corpus.Entry.Values] to decode their values.
+//
+// The entries are in the order of their file names, or, with
+// [WithCanonical], in the order of their normalized contents, or, with
+// [WithSort], in the order it sets. The options that only concern the
+// output of [DumpDir], such as [WithPreview], have no effect.
+//
+// The corpus is validated the same way, and errors are returned under
+// the same conditions as with [DumpDir]. The valid entries are returned
+// along with any [CorpusErrors] reporting the invalid ones, unless
+// [WithStrict] is given.
+func ReadDir(fsys fs.FS, dir string, opts ...Option) (Corpus, error) {
+	o, files := newOptions(opts).listing()
+	o.retain = true
+	var entries []namedEntry
+	begin := func(int) error { return nil }
+	emit := func(name string, lines corpus.Entry) error {
+		entries = append(entries, namedEntry{name, lines})
+		return nil
+	}
+	err := readDir(fsys, dir, o, begin, emit)
+	var errs CorpusErrors
+	if e := errs.Capture(err); e != nil {
+		return nil, e
+	}
+	if err != nil && o.strict {
+		return nil, err
+	}
+	if o.sorted() {
+		o.sortEntries(entries)
+	}
+	c, err := newCorpus(files, entries)
+	if err != nil {
+		return nil, err
+	}
+	return c, errs.AsError()
+}
+
+// Values returns the decoded values of each of the entries of c, e.g.,
+// for replaying them against a function:
+//
+//	for _, args := range vals {
+//		myFunc(args[0].(int), args[1].(string))
+//	}
+//
+// An entry with a value that cannot be decoded is reported as
+// [ErrMalformedValue] along with the name of its file, in a [FileError].
+func (c Corpus) Values() (vals [][]corpus.Value, err error) {
+	vals = make([][]corpus.Value, len(c))
+	for i, f := range c {
+		if vals[i], err = f.Entry.Values(); err != nil {
+			return nil, readErr(err, f.Name)
+		}
+	}
+	return
+}
+
+// listing returns o set to record the files of the corpus as it lists
+// them, in the returned map by their names, for [newFile] to take their
+// metadata from, without listing or statting them again.
+func (o options) listing() (options, map[string]fs.DirEntry) {
+	files := map[string]fs.DirEntry{}
+	o.listed = func(fs []fs.DirEntry) {
+		for _, f := range fs {
+			files[f.Name()] = f
+		}
+	}
+	return o, files
+}
+
+// newCorpus returns the entries read as a Corpus, see [newFile].
+func newCorpus(files map[string]fs.DirEntry, entries []namedEntry) (c Corpus, err error) {
+	c = make(Corpus, len(entries))
+	for i, v := range entries {
+		if c[i], err = newFile(files, v); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// newFile returns the entry e as a [corpus.File], with its hash, and the
+// size and modification time of its file, as listed in files.
+func newFile(files map[string]fs.DirEntry, e namedEntry) (corpus.File, error) {
+	f := corpus.File{Name: e.name, Entry: e.lines, Hash: entryHash(e.lines)}
+	if d, ok := files[e.name]; ok {
+		fi, err := d.Info()
+		if err != nil {
+			return corpus.File{}, readErr(err, e.name)
+		}
+		f.Size, f.ModTime = fi.Size(), fi.ModTime()
+	}
+	return f, nil
+}
@@ -0,0 +1,127 @@
+package fuzzdump_test
+
+import (
+	"crypto/sha256"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/antichris/go-fuzzdump/corpus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDir(t *testing.T) {
+	file := func(name string, lines ...string) corpus.File {
+		e := make(corpus.Entry, len(lines))
+		for i, l := range lines {
+			e[i] = []byte(l)
+		}
+		return corpus.File{Name: name, Entry: e}
+	}
+	tests := map[string]struct {
+		dir  string
+		opts []Option
+		want Corpus
+		wErr error
+	}{"absent": {
+		dir:  "foo",
+		wErr: os.ErrNotExist,
+	}, "no files": {
+		dir:  emptyDir,
+		wErr: ErrEmptyCorpus,
+	}, "allow empty": {
+		dir:  emptyDir,
+		opts: []Option{WithAllowEmpty()},
+		want: Corpus{},
+	}, "multi arg": {
+		dir: multiDir,
+		want: Corpus{
+			file("1", `string("foo")`, "uint(8)"),
+			file("2", `string("bar")`, "uint(13)"),
+		},
+	}, "canonical": {
+		dir:  unsortedDir,
+		opts: []Option{WithCanonical()},
+		want: Corpus{
+			file("2", `string("bar")`, "uint(13)"),
+			file("1", `string("foo")`, "uint(8)"),
+		},
+	}, "filtered": {
+		dir:  multiDir,
+		opts: []Option{WithMin(1, 10)},
+		want: Corpus{file("2", `string("bar")`, "uint(13)")},
+	}, "invalid entries": {
+		dir:  badValueDir,
+		opts: []Option{WithCanonical()},
+		want: Corpus{file("1", "uint(3)"), file("3", "uint(5)")},
+		wErr: ErrMalformedValue,
+	}, "strict": {
+		dir:  badValueDir,
+		opts: []Option{WithStrict()},
+		wErr: ErrMalformedValue,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := ReadDir(fsys, tt.dir, tt.opts...)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			for i := range got {
+				// The metadata is tested by TestReadDir_metadata.
+				got[i] = corpus.File{Name: got[i].Name, Entry: got[i].Entry}
+			}
+			req.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestReadDir_metadata(t *testing.T) {
+	const dir = "corpus"
+	var (
+		data  = []byte(corpus.Version1 + "\nint(1)\n")
+		mtime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+	fsys := fstest.MapFS{
+		dir + "/1": {Data: data, ModTime: mtime},
+		// The same entry, but not normalized.
+		dir + "/2": {Data: []byte(corpus.Version1 + "\nint(0x1)\n")},
+	}
+	req := require.New(t)
+	c, err := ReadDir(fsys, dir)
+	req.NoError(err)
+	req.Len(c, 2)
+	req.Equal(int64(len(data)), c[0].Size)
+	req.Equal(mtime, c[0].ModTime)
+	req.Equal(sha256.Sum256(data), c[0].Hash)
+	req.NotEqual(c[0].Hash, c[1].Hash)
+
+	c, err = ReadDir(fsys, dir, WithCanonical())
+	req.NoError(err)
+	req.Equal(c[0].Hash, c[1].Hash, "the hash of the normalized entry")
+	req.Equal(int64(len(data))+2, c[1].Size, "the size of the file as it is")
+
+	d, err := NewDumper(fsys, dir).Corpus()
+	req.NoError(err)
+	req.Equal(mtime, d[0].ModTime)
+	req.Equal(sha256.Sum256(data), d[0].Hash)
+}
+
+func TestCorpus_Values(t *testing.T) {
+	c, err := ReadDir(fsys, multiDir)
+	req := require.New(t)
+	req.NoError(err)
+	vals, err := c.Values()
+	req.NoError(err)
+	req.Equal([][]corpus.Value{{"foo", uint(8)}, {"bar", uint(13)}}, vals)
+
+	c = append(c, corpus.File{Name: "bad", Entry: corpus.Entry{[]byte("uint(-1)")}})
+	vals, err = c.Values()
+	req.ErrorIs(err, ErrMalformedValue)
+	req.ErrorContains(err, `reading "bad"`)
+	req.Nil(vals)
+}
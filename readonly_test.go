@@ -0,0 +1,28 @@
+package fuzzdump_test
+
+import (
+	"go/build"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadOnly makes sure that the library packages cannot write to the
+// file systems they read corpora from.
+func TestReadOnly(t *testing.T) {
+	writers := map[string]bool{
+		"io/ioutil": true,
+		"net":       true,
+		"os":        true,
+		"os/exec":   true,
+		"syscall":   true,
+		"unsafe":    true,
+	}
+	for _, dir := range []string{".", "corpus", "format"} {
+		pkg, err := build.ImportDir(dir, 0)
+		require.NoError(t, err)
+		for _, imp := range pkg.Imports {
+			require.False(t, writers[imp], "%s imports %s", dir, imp)
+		}
+	}
+}
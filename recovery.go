@@ -0,0 +1,85 @@
+package fuzzdump
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// ErrTrailingGarbage is reported for a corpus entry file with valid
+// values followed by lines that cannot be decoded, as when an append to
+// it was corrupted, and that [WithRecovery] salvaged the values of. The
+// entry is dumped nonetheless.
+const ErrTrailingGarbage Error = "corpus entry has trailing garbage"
+
+// WithRecovery makes [DumpDir] salvage the corpus entry files whose
+// leading values are valid, but are followed by a line that cannot be
+// decoded as a value, as when an append to the file was corrupted: the
+// valid values ahead of that line are dumped, and the rest of the file
+// is dropped, but still reported as [ErrTrailingGarbage], along with the
+// line number and the byte offset in the file where it starts, e.g.:
+//
+//	reading "a1b2": corpus entry has trailing garbage at line 4, byte 31: "int(1"
+//
+// Every value is decoded, so a file that has no valid leading values is
+// reported as [ErrMalformedValue], as with [WithNormalizedValues]. The
+// salvaged values still have to be as many as those of the rest of the
+// entries, or the entry is reported as [ErrInconsistentArgCount].
+func WithRecovery() Option {
+	return func(o *options) { o.recover = true }
+}
+
+// recoveringLineReader returns a lineReader that reads corpus entry
+// files with read, and keeps just the leading lines that are values that
+// can be decoded, reporting the rest as [ErrTrailingGarbage].
+func recoveringLineReader(read lineReader) lineReader {
+	return func(fsys fs.FS, name string) (corpus.Entry, error) {
+		lines, err := read(fsys, name)
+		if lines == nil {
+			return lines, err
+		}
+		n := 0
+		for ; n < len(lines); n++ {
+			if _, vErr := lines[n : n+1].Values(); vErr != nil {
+				if n == 0 {
+					return nil, vErr
+				}
+				break
+			}
+		}
+		if n == len(lines) {
+			return lines, err
+		}
+		b, rErr := fs.ReadFile(fsys, name)
+		if rErr != nil {
+			return nil, rErr
+		}
+		line, offset := linePosition(b, lines, n)
+		gErr := fmt.Errorf("%w at line %d, byte %d: %q",
+			ErrTrailingGarbage, line, offset, lines[n])
+		if err != nil {
+			gErr = fmt.Errorf("%w; %v", gErr, err)
+		}
+		return lines[:n], gErr
+	}
+}
+
+// linePosition returns the number (from 1) of the line of data, and the
+// byte offset where it starts, that holds the value lines[n], found by
+// matching the lines of the entry in order against those in data.
+func linePosition(data []byte, lines corpus.Entry, n int) (line, offset int) {
+	j := 0
+	for i, l := range bytes.Split(data, []byte("\n")) {
+		if bytes.Equal(bytes.TrimSpace(l), lines[j]) {
+			if j == n {
+				return i + 1, offset
+			}
+			j++
+		}
+		offset += len(l) + 1
+	}
+	// Not to be reached, as the lines are those of data.
+	return 0, 0
+}
@@ -0,0 +1,51 @@
+package fuzzdump_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDir_recovery(t *testing.T) {
+	const dir = "corrupt"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int(1)\nstring(\"a\")"),
+		dir + "/2": corpusFile("int(2)\nstring(\"b\")\nint(3\n\x00\x01"),
+		dir + "/3": corpusFile("int(\nstring(\"c\")"),
+	}
+	tests := map[string]struct {
+		opts     []Option
+		wErrs    []error
+		wErrText string
+		wOut     string
+	}{"default": {
+		wErrs: []error{ErrInconsistentArgCount},
+		wOut:  "{{\n\tint(1),\n\tstring(\"a\"),\n}, {\n\tint(,\n\tstring(\"c\"),\n}}\n",
+	}, "recovered": {
+		opts:  []Option{WithRecovery()},
+		wErrs: []error{ErrTrailingGarbage, ErrMalformedValue},
+		wErrText: `reading "2": corpus entry has trailing garbage` +
+			` at line 4, byte 35: "int(3"`,
+		wOut: "{{\n\tint(1),\n\tstring(\"a\"),\n}, {\n\tint(2),\n\tstring(\"b\"),\n}}\n",
+	}, "recovered strict": {
+		opts:  []Option{WithRecovery(), WithStrict()},
+		wErrs: []error{ErrTrailingGarbage},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			b := &strings.Builder{}
+			err := DumpDir(b, fsys, dir, tt.opts...)
+			req := require.New(t)
+			for _, wErr := range tt.wErrs {
+				req.ErrorIs(err, wErr)
+			}
+			if tt.wErrText != "" {
+				req.Contains(err.Error(), tt.wErrText)
+			}
+			req.Equal(tt.wOut, b.String())
+		})
+	}
+}
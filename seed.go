@@ -0,0 +1,44 @@
+package fuzzdump
+
+import (
+	"io/fs"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// A Seeder accepts seed corpus entries, as a [testing.F] does.
+type Seeder interface {
+	Add(args ...any)
+}
+
+// AddSeeds adds the entries from a fuzz test corpus directory in fsys to
+// f, so that a fuzz test can use a corpus shipped with the program, e.g.
+// one in an [embed.FS]:
+//
+//	func FuzzMyFunc(f *testing.F) {
+//		if err := fuzzdump.AddSeeds(f, corpus, "testdata/fuzz/FuzzMyFunc"); err != nil {
+//			f.Fatal(err)
+//		}
+//		f.Fuzz(func(t *testing.T, a int, b string) {
+//			// ...
+//		})
+//	}
+//
+// The values of each entry are decoded and passed to f in a single call
+// of its Add method.
+//
+// The corpus is validated the same way and errors are returned under
+// the same conditions as with [DumpDir]. A value that cannot be decoded
+// is reported as [ErrMalformedValue], and its entry is not added.
+func AddSeeds(f Seeder, fsys fs.FS, dir string) error {
+	begin := func(int) error { return nil }
+	emit := func(_ string, lines corpus.Entry) error {
+		vals, err := lines.Values()
+		if err != nil {
+			return err
+		}
+		f.Add(vals...)
+		return nil
+	}
+	return readDir(fsys, dir, options{decode: true}, begin, emit)
+}
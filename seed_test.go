@@ -0,0 +1,48 @@
+package fuzzdump_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSeeds(t *testing.T) {
+	tests := map[string]struct {
+		dir   string
+		wErr  error
+		wArgs [][]any
+	}{"absent": {
+		dir:  "foo",
+		wErr: os.ErrNotExist,
+	}, "no files": {
+		dir:  emptyDir,
+		wErr: ErrEmptyCorpus,
+	}, "multi arg": {
+		dir:   multiDir,
+		wArgs: [][]any{{"foo", uint(8)}, {"bar", uint(13)}},
+	}, "bad value": {
+		dir:   badValueDir,
+		wErr:  ErrMalformedValue,
+		wArgs: [][]any{{uint(3)}, {uint(5)}},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			s := &seeder{}
+			err := AddSeeds(s, fsys, tt.dir)
+			req := require.New(t)
+			if tt.wErr != nil {
+				req.ErrorIs(err, tt.wErr)
+			} else {
+				req.NoError(err)
+			}
+			req.Equal(tt.wArgs, s.args)
+		})
+	}
+}
+
+// seeder records the arguments of every Add call.
+type seeder struct{ args [][]any }
+
+func (s *seeder) Add(args ...any) { s.args = append(s.args, args) }
@@ -0,0 +1,88 @@
+package fuzzdump
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WithSizeNotes makes [DumpDir] write a comment ahead of the values of
+// each entry, noting the number of its arguments, and their total size
+// once decoded (not the size of its file), e.g.:
+//
+//	{
+//		// 3 args, 18 KiB
+//		int(2),
+//		string("..."),
+//		[]byte("..."),
+//	}
+//
+// This helps spot the heavyweight entries while reviewing a dump. The
+// size of a string or a []byte is that of its contents, of any other
+// value, that of its type in memory on a 64-bit platform, so the notes
+// are the same on any.
+//
+// Every value in the corpus is decoded. A value that cannot be is
+// reported as [ErrMalformedValue], and its entry is not dumped.
+func WithSizeNotes() Option {
+	return func(o *options) { o.sizes, o.decode = true, true }
+}
+
+// sizeNote returns the comment on the size of the entry that lines
+// hold, see [WithSizeNotes], or an empty string if they cannot be
+// decoded.
+func sizeNote(lines corpus.Entry) string {
+	vals, err := lines.Values()
+	if err != nil {
+		return ""
+	}
+	var size int
+	for _, v := range vals {
+		size += valueSize(v)
+	}
+	args := "args"
+	if len(vals) == 1 {
+		args = "arg"
+	}
+	return strconv.Itoa(len(vals)) + " " + args + ", " + formatSize(size)
+}
+
+// valueSize returns the size of the contents of v, if it is a string or
+// a []byte, otherwise, the size of its type in memory on a 64-bit
+// platform.
+func valueSize(v corpus.Value) int {
+	switch v := v.(type) {
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case int8, uint8, bool:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	}
+	return 8
+}
+
+// formatSize returns n bytes in a human-readable form, in binary units,
+// e.g., "512 B", "1.5 KiB", or "18 KiB".
+func formatSize(n int) string {
+	if n < 1024 {
+		return strconv.Itoa(n) + " B"
+	}
+	f, unit := float64(n)/1024, 0
+	for ; f >= 1024 && unit < len(sizeUnits)-1; unit++ {
+		f /= 1024
+	}
+	prec := 0
+	if f < 10 {
+		prec = 1
+	}
+	s := strings.TrimSuffix(strconv.FormatFloat(f, 'f', prec, 64), ".0")
+	return s + " " + sizeUnits[unit]
+}
+
+var sizeUnits = []string{"KiB", "MiB", "GiB", "TiB"}
@@ -0,0 +1,53 @@
+package fuzzdump_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatSize(t *testing.T) {
+	tests := map[int]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1024:            "1 KiB",
+		1536:            "1.5 KiB",
+		18 * 1024:       "18 KiB",
+		18*1024 + 700:   "19 KiB",
+		5 << 20:         "5 MiB",
+		3 << 40:         "3 TiB",
+		2048 << 40:      "2048 TiB",
+		10*1024 - 1:     "10 KiB",
+		9*1024 + 1024/4: "9.2 KiB",
+	}
+	for n, want := range tests {
+		require.Equal(t, want, XformatSize(n), n)
+	}
+}
+
+func TestDumpDir_sizeNotes(t *testing.T) {
+	const dir = "sized"
+	fsys := fstest.MapFS{
+		dir + "/1": corpusFile("int64(1)\nstring(\"" + strings.Repeat("x", 2040) + "\")\n[]byte(\"\")"),
+		dir + "/2": corpusFile("int8(2)\nstring(\"\")\n[]byte(\"abc\")"),
+	}
+	w := &strings.Builder{}
+	req := require.New(t)
+	req.NoError(DumpDir(w, fsys, dir, WithSizeNotes(),
+		WithEntryComments(func(name string) string {
+			if name == "2" {
+				return "second"
+			}
+			return ""
+		})))
+	req.Equal("{{\n"+
+		"\t// 3 args, 2 KiB\n"+
+		"\tint64(1),\n\tstring(\""+strings.Repeat("x", 2040)+"\"),\n\t[]byte(\"\"),\n"+
+		"}, {\n"+
+		"\t// second\n\t// 3 args, 4 B\n"+
+		"\tint8(2),\n\tstring(\"\"),\n\t[]byte(\"abc\"),\n"+
+		"}}\n", w.String())
+}
@@ -0,0 +1,243 @@
+package fuzzdump
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// CorpusStats are the statistics of a fuzz test corpus directory, as
+// [Stats] returns them.
+type CorpusStats struct {
+	// Entries is the number of entry files, valid or not.
+	Entries int
+	// Invalid is the number of those that are malformed, have values
+	// that cannot be decoded, or a different number of arguments than
+	// the first valid one.
+	Invalid int
+	// Duplicates is the number of valid entries with the same values as
+	// one before them, in the order of their file names, even if their
+	// files differ, e.g., in how the values are encoded.
+	Duplicates int
+	// ArgTypes are the names of the argument types of the first valid
+	// entry, e.g. "int" or "[]byte", or nil, if there is none.
+	ArgTypes []string
+	// MinSize, MaxSize and TotalSize are the smallest, the largest, and
+	// the total size of the entry files in bytes.
+	MinSize, MaxSize, TotalSize int64
+}
+
+// MeanSize returns the mean size of the entry files of s in bytes, or 0
+// if there are none.
+func (s CorpusStats) MeanSize() float64 {
+	if s.Entries == 0 {
+		return 0
+	}
+	return float64(s.TotalSize) / float64(s.Entries)
+}
+
+// Stats reads every entry file in the fuzz test corpus directory dir in
+// fsys and returns the statistics of the corpus, for a glance at its
+// health without dumping it.
+//
+// Every value is decoded. The invalid entries are reported in
+// [CorpusErrors], along with the statistics, and so is a directory
+// without any valid entries, as [ErrEmptyCorpus]. Any other error is
+// returned as it is.
+func Stats(fsys fs.FS, dir string) (s CorpusStats, err error) {
+	s, _, err = stats(fsys, dir)
+	return
+}
+
+// stats returns the statistics of the corpus in dir in fsys, as [Stats]
+// does, along with the set of the keys of the values of its valid
+// entries, see [statEntry].
+func stats(fsys fs.FS, dir string) (s CorpusStats, seen map[string]bool, err error) {
+	files, err := getFiles(fsys, dir)
+	if err != nil {
+		return
+	}
+	var errs CorpusErrors
+	seen = map[string]bool{}
+	for _, f := range files {
+		name := f.Name()
+		b, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return s, nil, readErr(err, name)
+		}
+		size := int64(len(b))
+		if s.Entries == 0 || size < s.MinSize {
+			s.MinSize = size
+		}
+		if size > s.MaxSize {
+			s.MaxSize = size
+		}
+		s.Entries++
+		s.TotalSize += size
+		vals, key, err := statEntry(b)
+		if err == nil && s.ArgTypes != nil && len(vals) != len(s.ArgTypes) {
+			err = argCountErr(len(s.ArgTypes), len(vals))
+		}
+		if err != nil {
+			s.Invalid++
+			errs.append(readErr(err, name))
+			continue
+		}
+		if s.ArgTypes == nil {
+			s.ArgTypes = typeNames(vals)
+		}
+		if seen[key] {
+			s.Duplicates++
+		}
+		seen[key] = true
+	}
+	if s.ArgTypes == nil {
+		errs.append(ErrEmptyCorpus)
+	}
+	return s, seen, errs.AsError()
+}
+
+// AggregateStats are the statistics of several fuzz test corpus
+// directories, as [StatsDirs] returns them.
+type AggregateStats struct {
+	// Targets are the statistics of each of the corpora, in the order
+	// of their directories.
+	Targets []TargetStats
+	// Total are the statistics of all the entries of the corpora taken
+	// together, save for Duplicates, which are those within each, and
+	// ArgTypes, which are nil, unless all the corpora with valid entries
+	// have the same argument types.
+	Total CorpusStats
+	// Shared is the number of the distinct values of entries that are in
+	// more than one of the corpora.
+	Shared int
+}
+
+// TargetStats are the statistics of one of several fuzz test corpus
+// directories, as [StatsDirs] returns them.
+type TargetStats struct {
+	// Dir is the path of the corpus directory.
+	Dir string
+	CorpusStats
+	// Shared is the number of the distinct values of its entries that
+	// are in another one of the corpora, too.
+	Shared int
+}
+
+// StatsDirs returns the statistics of each of the fuzz test corpus
+// directories dirs in fsys, as [Stats] does, and of all of them taken
+// together, along with how many entries they share, e.g., for related
+// fuzz targets of a monorepo, as [FindCorpora] finds them. The entries
+// with the same values are shared, even if their files differ.
+//
+// The errors of each corpus are reported in a [TreeError] after the
+// statistics of all have been gathered. Those of a corpus that cannot be
+// read are left zero.
+func StatsDirs(fsys fs.FS, dirs ...string) (a AggregateStats, err error) {
+	var errs TreeError
+	in := map[string]int{} // The number of corpora each value is in.
+	keys := make([]map[string]bool, len(dirs))
+	a.Targets = make([]TargetStats, len(dirs))
+	for i, dir := range dirs {
+		s, seen, err := stats(fsys, dir)
+		if err != nil {
+			errs = append(errs, &TargetError{dir, err})
+		}
+		for k := range seen {
+			in[k]++
+		}
+		a.Targets[i] = TargetStats{Dir: dir, CorpusStats: s}
+		keys[i] = seen
+		a.Total.add(s)
+	}
+	for i := range a.Targets {
+		for k := range keys[i] {
+			if in[k] > 1 {
+				a.Targets[i].Shared++
+			}
+		}
+	}
+	for _, n := range in {
+		if n > 1 {
+			a.Shared++
+		}
+	}
+	if len(errs) == 0 {
+		return a, nil
+	}
+	return a, errs
+}
+
+// add the statistics of another corpus to the total t, see
+// [AggregateStats.Total].
+func (t *CorpusStats) add(s CorpusStats) {
+	if s.Entries > 0 && (t.Entries == 0 || s.MinSize < t.MinSize) {
+		t.MinSize = s.MinSize
+	}
+	if s.MaxSize > t.MaxSize {
+		t.MaxSize = s.MaxSize
+	}
+	first := t.Entries == t.Invalid // No valid entries so far.
+	t.Entries += s.Entries
+	t.Invalid += s.Invalid
+	t.Duplicates += s.Duplicates
+	t.TotalSize += s.TotalSize
+	switch {
+	case s.ArgTypes == nil:
+	case first:
+		t.ArgTypes = s.ArgTypes
+	case !equalStrings(t.ArgTypes, s.ArgTypes):
+		t.ArgTypes = nil
+	}
+}
+
+// statEntry returns the values of the entry that data holds, and a key
+// that is the same for every entry with the same values.
+func statEntry(data []byte) (vals []corpus.Value, key string, err error) {
+	e, err := corpus.Unmarshal(data)
+	if err != nil {
+		return
+	}
+	if vals, err = e.Values(); err != nil {
+		return
+	}
+	if e, err = corpus.NewEntry(vals...); err != nil {
+		return
+	}
+	return vals, string(bytes.Join(e, []byte("\n"))), nil
+}
+
+// typeNames returns the names of the types of vals, as [typeName] does.
+func typeNames(vals []corpus.Value) []string {
+	names := make([]string, len(vals))
+	for i, v := range vals {
+		names[i] = typeName(v)
+	}
+	return names
+}
+
+// typeName returns the name of the type of v, as it is written in corpus
+// entries, save for byte and rune, e.g. "[]byte" or "uint8".
+func typeName(v corpus.Value) string {
+	if s := fmt.Sprintf("%T", v); s != "[]uint8" {
+		return s
+	}
+	return "[]byte"
+}
+
+// equalStrings reports whether a and b hold the same strings in the same
+// order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package fuzzdump_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	const dir = "corpus"
+	tests := map[string]struct {
+		files map[string]*fstest.MapFile
+		want  CorpusStats
+		wErrs []error
+	}{"nominal": {
+		files: map[string]*fstest.MapFile{
+			"1": corpusFile("int(1)\nstring(\"a\")"),
+			"2": corpusFile("int(0x1)\nstring(\"a\")"), // Same values.
+			"3": corpusFile("int(2)\n[]byte(\"bc\")"),
+			"4": corpusFile("int(1)\nstring(\"a\")"),
+		},
+		want: CorpusStats{
+			Entries:    4,
+			Duplicates: 2,
+			ArgTypes:   []string{"int", "string"},
+			MinSize:    35,
+			MaxSize:    37,
+			TotalSize:  143,
+		},
+	}, "invalid": {
+		files: map[string]*fstest.MapFile{
+			"1": {Data: []byte("garbage")},
+			"2": corpusFile("int(1)"),
+			"3": corpusFile("int(2)\nint(3)"),
+			"4": corpusFile("int("),
+		},
+		want: CorpusStats{
+			Entries:   4,
+			Invalid:   3,
+			ArgTypes:  []string{"int"},
+			MinSize:   7,
+			MaxSize:   30,
+			TotalSize: 7 + 23 + 30 + 21,
+		},
+		wErrs: []error{ErrMalformedEntry, ErrInconsistentArgCount, ErrMalformedValue},
+	}, "no valid entries": {
+		files: map[string]*fstest.MapFile{"1": {Data: []byte("garbage")}},
+		want: CorpusStats{
+			Entries:   1,
+			Invalid:   1,
+			MinSize:   7,
+			MaxSize:   7,
+			TotalSize: 7,
+		},
+		wErrs: []error{ErrMalformedEntry, ErrEmptyCorpus},
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			fsys := fstest.MapFS{dir: {Mode: fs.ModeDir}}
+			for name, f := range tt.files {
+				fsys[dir+"/"+name] = f
+			}
+			got, err := Stats(fsys, dir)
+			req := require.New(t)
+			if tt.wErrs == nil {
+				req.NoError(err)
+			}
+			for _, wErr := range tt.wErrs {
+				req.ErrorIs(err, wErr)
+			}
+			req.Equal(tt.want, got)
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		_, err := Stats(fstest.MapFS{}, dir)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestCorpusStats_MeanSize(t *testing.T) {
+	req := require.New(t)
+	req.Equal(0.0, CorpusStats{}.MeanSize())
+	req.Equal(2.5, CorpusStats{Entries: 2, TotalSize: 5}.MeanSize())
+}
+
+func TestStatsDirs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/1": corpusFile("int(1)"),
+		"a/2": corpusFile("int(2)"),
+		"a/3": corpusFile("int(0x2)"),
+		"b/1": corpusFile("int(0x1)"), // Same values as "a/1".
+		"b/2": corpusFile("int(3)"),
+		"c/1": corpusFile("string(\"a\")"),
+		"c/2": corpusFile("int("),
+	}
+	got, err := StatsDirs(fsys, "a", "b", "c", "missing")
+	req := require.New(t)
+	var tErr TreeError
+	req.ErrorAs(err, &tErr)
+	req.Len(tErr, 2)
+	req.ErrorIs(tErr[0], ErrMalformedValue)
+	req.ErrorIs(tErr[1], fs.ErrNotExist)
+	req.Equal(AggregateStats{
+		Targets: []TargetStats{{
+			Dir: "a",
+			CorpusStats: CorpusStats{
+				Entries:    3,
+				Duplicates: 1,
+				ArgTypes:   []string{"int"},
+				MinSize:    23,
+				MaxSize:    25,
+				TotalSize:  71,
+			},
+			Shared: 1,
+		}, {
+			Dir: "b",
+			CorpusStats: CorpusStats{
+				Entries:   2,
+				ArgTypes:  []string{"int"},
+				MinSize:   23,
+				MaxSize:   25,
+				TotalSize: 48,
+			},
+			Shared: 1,
+		}, {
+			Dir: "c",
+			CorpusStats: CorpusStats{
+				Entries:   2,
+				Invalid:   1,
+				ArgTypes:  []string{"string"},
+				MinSize:   21,
+				MaxSize:   28,
+				TotalSize: 49,
+			},
+		}, {
+			Dir: "missing",
+		}},
+		Total: CorpusStats{
+			Entries:    7,
+			Invalid:    1,
+			Duplicates: 1,
+			MinSize:    21,
+			MaxSize:    28,
+			TotalSize:  168,
+		},
+		Shared: 1,
+	}, got)
+
+	t.Run("same types", func(t *testing.T) {
+		got, err := StatsDirs(fsys, "a", "b")
+		req := require.New(t)
+		req.NoError(err)
+		req.Equal([]string{"int"}, got.Total.ArgTypes)
+	})
+}
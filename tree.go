@@ -0,0 +1,158 @@
+package fuzzdump
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNoCorpora is returned when there are no fuzz test corpus
+// directories in a directory tree.
+const ErrNoCorpora Error = "no fuzz test corpus directories in tree"
+
+// DumpTree writes the entries from every fuzz test corpus directory in
+// the tree under root to w, such as those of a whole testdata/fuzz, or
+// of the fuzz cache of the Go toolchain, as [FindCorpora] finds them.
+//
+// Each corpus is dumped as [DumpDir] does, with opts, in a section of
+// its own, headed by a comment naming its fuzz target by its path
+// relative to root, and preceded by an empty line, unless it is the
+// first, e.g.:
+//
+//	// FuzzDecode
+//	{
+//		string("foo"),
+//	}
+//
+//	// example.com/pkg/FuzzParse
+//	{
+//		int(42),
+//	}
+//
+// The corpora that cannot be dumped are reported in a [TreeError] after
+// all the others have been, and if there are none at all, it returns
+// [ErrNoCorpora].
+func DumpTree(w io.Writer, fsys fs.FS, root string, opts ...Option) error {
+	dirs, err := FindCorpora(fsys, root)
+	if err != nil {
+		return err
+	}
+	var errs TreeError
+	for i, dir := range dirs {
+		target := dir
+		switch {
+		case dir == root:
+			target = path.Base(dir)
+		case root != ".":
+			target = strings.TrimPrefix(dir, root+"/")
+		}
+		sep := "\n"
+		if i == 0 {
+			sep = ""
+		}
+		if _, err := fmt.Fprintf(w, "%s// %s\n", sep, target); err != nil {
+			return writeErr(err)
+		}
+		if err := DumpDir(w, fsys, dir, opts...); err != nil {
+			errs = append(errs, &TargetError{target, err})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// FindCorpora returns the paths in fsys of the fuzz test corpus
+// directories in the tree under root (root included), in lexical order.
+//
+// A corpus directory is one named as Go requires a fuzz target to be
+// named: "Fuzz", followed by anything that does not start with a lower
+// case letter, e.g. FuzzParse or Fuzz_parse. The directories in it are
+// not searched any further, and neither are hidden directories, those
+// whose names start with a ".".
+//
+// If there are no corpus directories in the tree, it returns
+// [ErrNoCorpora].
+func FindCorpora(fsys fs.FS, root string) (dirs []string, err error) {
+	err = fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		base := path.Base(name)
+		if name != root && strings.HasPrefix(base, ".") {
+			return fs.SkipDir
+		}
+		if isFuzzTarget(base) {
+			dirs = append(dirs, name)
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err == nil && len(dirs) == 0 {
+		err = ErrNoCorpora
+	}
+	return
+}
+
+// isFuzzTarget reports whether name is that of a fuzz target, as the go
+// test command tells them apart.
+func isFuzzTarget(name string) bool {
+	rest := strings.TrimPrefix(name, "Fuzz")
+	if rest == name {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !unicode.IsLower(r)
+}
+
+// A TargetError reports an error with dumping the corpus of the named
+// fuzz target in a tree, see [DumpTree].
+type TargetError struct {
+	Target string // The path of its corpus directory, relative to the root.
+	Err    error
+}
+
+// Implements the [error] interface.
+func (e *TargetError) Error() string { return fmt.Sprintf("%s: %v", e.Target, e.Err) }
+
+// Unwrap returns the underlying error.
+// Implements the interface required by [errors.Unwrap].
+func (e *TargetError) Unwrap() error { return e.Err }
+
+// TreeError reports the corpora in a tree that [DumpTree] failed to
+// dump, each with a [TargetError].
+type TreeError []*TargetError
+
+// Implements the [error] interface.
+func (e TreeError) Error() string {
+	mss := []string{fmt.Sprintf("%d fuzz test corpora failed:", len(e))}
+	for _, e := range e {
+		mss = append(mss, e.Error())
+	}
+	return strings.Join(mss, "\n\t")
+}
+
+// Unwrap returns the error of the corpus that warrants the highest exit
+// status code (see [ExitCodeFor]), the first of them, if there are more,
+// or nil if e is empty.
+// Implements the interface required by [errors.Unwrap].
+func (e TreeError) Unwrap() error {
+	var worst error
+	for _, t := range e {
+		if worst == nil || ExitCodeFor(t) > ExitCodeFor(worst) {
+			worst = t
+		}
+	}
+	return worst
+}
@@ -0,0 +1,76 @@
+package fuzzdump_test
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpTree(t *testing.T) {
+	tests := map[string]struct {
+		fsys    fstest.MapFS
+		root    string
+		want    string
+		wErr    error
+		wTarget string
+	}{"nominal": {
+		fsys: fstest.MapFS{
+			"fuzz/FuzzB/1":                 corpusFile("int(2)"),
+			"fuzz/FuzzA/1":                 corpusFile("string(\"a\")"),
+			"fuzz/example.com/m/Fuzz_c/1":  corpusFile("bool(true)"),
+			"fuzz/FuzzA/sub/FuzzNested/1":  corpusFile("int(0)"),
+			"fuzz/.FuzzHidden.0a1b/1":      corpusFile("int(0)"),
+			"fuzz/Fuzzy/1":                 corpusFile("int(0)"),
+			"fuzz/example.com/m/notes.txt": {Data: []byte("not a corpus")},
+		},
+		root: "fuzz",
+		want: "// FuzzA\n{\n\tstring(\"a\"),\n}\n" +
+			"\n// FuzzB\n{\n\tint(2),\n}\n" +
+			"\n// example.com/m/Fuzz_c\n{\n\tbool(true),\n}\n",
+	}, "root is a corpus": {
+		fsys: fstest.MapFS{"fuzz/FuzzA/1": corpusFile("int(1)")},
+		root: "fuzz/FuzzA",
+		want: "// FuzzA\n{\n\tint(1),\n}\n",
+	}, "dot root": {
+		fsys: fstest.MapFS{"a/FuzzA/1": corpusFile("int(1)")},
+		root: ".",
+		want: "// a/FuzzA\n{\n\tint(1),\n}\n",
+	}, "failed corpus": {
+		fsys: fstest.MapFS{
+			"FuzzA/1": corpusFile("int(1)"),
+			"FuzzA/2": {},
+			"FuzzB/1": corpusFile("int(2)"),
+		},
+		root:    ".",
+		want:    "// FuzzA\n{\n\tint(1),\n}\n\n// FuzzB\n{\n\tint(2),\n}\n",
+		wErr:    ErrShortEntry,
+		wTarget: "FuzzA",
+	}, "no corpora": {
+		fsys: fstest.MapFS{"fuzz/Fuzzy/1": corpusFile("int(1)")},
+		root: "fuzz",
+		wErr: ErrNoCorpora,
+	}, "missing root": {
+		fsys: fstest.MapFS{},
+		root: "fuzz",
+		wErr: fs.ErrNotExist,
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := DumpTree(w, tt.fsys, tt.root)
+			req := require.New(t)
+			req.ErrorIs(err, tt.wErr)
+			req.Equal(tt.want, w.String())
+			if tt.wTarget != "" {
+				var e *TargetError
+				req.True(errors.As(err, &e))
+				req.Equal(tt.wTarget, e.Target)
+			}
+		})
+	}
+}
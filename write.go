@@ -0,0 +1,26 @@
+package fuzzdump
+
+import (
+	"io"
+
+	"github.com/antichris/go-fuzzdump/corpus"
+)
+
+// WriteEntry writes a corpus entry file holding the values args to w,
+// in version 1 encoding, as the Go toolchain does, e.g.:
+//
+//	err := fuzzdump.WriteEntry(w, int(42), "foo", []byte("bar"))
+//
+// The name that the Go toolchain would give the file is returned by
+// [corpus.FileName]. To write a whole corpus directory, use
+// [github.com/antichris/go-fuzzdump/corpusdir.WriteDir].
+//
+// A value of a type that Go fuzzing does not support is reported as an
+// error, and so is an entry without any, as [ErrMalformedEntry].
+func WriteEntry(w io.Writer, args ...any) error {
+	e, err := corpus.NewEntry(args...)
+	if err != nil {
+		return err
+	}
+	return corpus.NewEncoder(w).Encode(e)
+}
@@ -0,0 +1,43 @@
+package fuzzdump_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/antichris/go-fuzzdump"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEntry(t *testing.T) {
+	tests := map[string]struct {
+		args     []any
+		want     string
+		wErr     error
+		wErrText string
+	}{"values": {
+		args: []any{int(42), "foo", []byte("bar\x00"), 'x', 1.5},
+		want: "go test fuzz v1\nint(42)\nstring(\"foo\")\n[]byte(\"bar\\x00\")\n" +
+			"rune('x')\nfloat64(1.5)\n",
+	}, "no values": {
+		wErr: ErrMalformedEntry,
+	}, "unsupported type": {
+		args:     []any{struct{}{}},
+		wErrText: "unsupported value type struct {}",
+	}}
+	for n, tt := range tests {
+		t.Run(n, func(t *testing.T) {
+			w := &strings.Builder{}
+			err := WriteEntry(w, tt.args...)
+			req := require.New(t)
+			switch {
+			case tt.wErr != nil:
+				req.ErrorIs(err, tt.wErr)
+			case tt.wErrText != "":
+				req.EqualError(err, tt.wErrText)
+			default:
+				req.NoError(err)
+			}
+			req.Equal(tt.want, w.String())
+		})
+	}
+}